@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/buckleypaul/gust/internal/app"
+	"github.com/buckleypaul/gust/internal/cli"
 	"github.com/buckleypaul/gust/internal/config"
 	"github.com/buckleypaul/gust/internal/pages"
 	"github.com/buckleypaul/gust/internal/store"
@@ -15,6 +19,17 @@ import (
 )
 
 func main() {
+	args := os.Args[1:]
+	if len(args) > 0 && cli.IsHelp(args[0]) {
+		cli.Usage(os.Stdout)
+		return
+	}
+	if len(args) > 0 && !cli.IsCommand(args[0]) {
+		fmt.Fprintf(os.Stderr, "gust: unknown command %q\n\n", args[0])
+		cli.Usage(os.Stderr)
+		os.Exit(cli.ExitUsage)
+	}
+
 	cwd, err := os.Getwd()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -28,23 +43,63 @@ func main() {
 	}
 
 	cfg := config.Load(ws.Root)
-	if err := west.InitEnv(ws, cfg.VenvPath); err != nil {
+	var base west.Runner = west.RealRunner()
+	if cfg.UseContainer() {
+		base = west.NewContainerRunner(ws.Root, west.ContainerOptions{
+			Engine:  cfg.Container.Engine,
+			Image:   cfg.Container.Image,
+			Mount:   cfg.Container.Mount,
+			Env:     cfg.Container.Env,
+			RunArgs: cfg.Container.RunArgs,
+		})
+	} else if err := west.InitEnv(ws, cfg.VenvPath); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: west auto-setup failed: %v\n", err)
 	}
 	st := store.New(filepath.Join(ws.Root, ".gust"))
-	runner := west.RealRunner()
+	// GUST_RECORD_TRANSCRIPT captures every command to a transcript that
+	// page tests can replay with west.NewReplayRunner.
+	if path := os.Getenv("GUST_RECORD_TRANSCRIPT"); path != "" {
+		base = west.NewRecordingRunner(base, path, ws.Root)
+	}
+
+	if len(args) > 0 {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		code := cli.Run(ctx, cli.Env{
+			WsRoot: ws.Root,
+			Cfg:    cfg,
+			Store:  st,
+			Runner: base,
+			Stdout: os.Stdout,
+			Stderr: os.Stderr,
+		}, args)
+		stop()
+		os.Exit(code)
+	}
+
+	jobs := west.NewJobManager()
+	notifier := &west.Notifier{Methods: cfg.Notify, Command: cfg.NotifyCommand}
+	// Without a terminal of its own only command notifications work.
+	if tty, err := west.OpenTerminal(); err == nil {
+		defer tty.Close()
+		notifier.Out = tty
+	}
+	runner := west.TrackJobs(west.NotifyRunner(base, notifier), jobs)
 
 	pageMap := map[app.PageID]app.Page{
-		app.WorkspacePage: pages.NewWorkspacePage(ws, runner),
-		app.MonitorPage:   pages.NewMonitorPage(st, cfg.SerialBaudRate),
-		app.TestPage:      pages.NewTestPage(st, &cfg, ws.Root, runner),
-		app.ArtifactsPage: pages.NewArtifactsPage(st),
-		app.WestPage:      pages.NewWestPage(runner),
-		app.ProjectPage:   pages.NewProjectPage(st, &cfg, ws.Root, ws.ManifestPath, runner),
-		app.SettingsPage:  pages.NewSettingsPage(&cfg, ws.Root),
+		app.WorkspacePage:  pages.NewWorkspacePage(ws, runner),
+		app.MonitorPage:    pages.NewMonitorPage(st, cfg.SerialBaudRate),
+		app.TestPage:       pages.NewTestPage(st, &cfg, ws.Root, runner),
+		app.ArtifactsPage:  pages.NewArtifactsPage(st, &cfg, runner),
+		app.FootprintPage:  pages.NewFootprintPage(&cfg, ws.Root, runner),
+		app.CMakeCachePage: pages.NewCMakeCachePage(&cfg, ws.Root, runner),
+		app.WestPage:       pages.NewWestPage(runner),
+		app.JobsPage:       pages.NewJobsPage(jobs),
+		app.ProjectPage:    pages.NewProjectPage(st, &cfg, ws.Root, ws.ManifestPath, runner),
+		app.MatrixPage:     pages.NewMatrixPage(st, &cfg, ws.Root, runner),
+		app.SettingsPage:   pages.NewSettingsPage(&cfg, ws.Root),
 	}
 
-	model := app.New(pageMap, &cfg, ws.Root, ws.ManifestPath)
+	model := app.New(pageMap, &cfg, ws.Root, ws.ManifestPath, jobs)
 
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
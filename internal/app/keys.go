@@ -6,6 +6,7 @@ type KeyMap struct {
 	ToggleFocus key.Binding
 	Help        key.Binding
 	Quit        key.Binding
+	Profile     key.Binding
 }
 
 var GlobalKeys = KeyMap{
@@ -21,4 +22,8 @@ var GlobalKeys = KeyMap{
 		key.WithKeys("q", "ctrl+c"),
 		key.WithHelp("q", "quit"),
 	),
+	Profile: key.NewBinding(
+		key.WithKeys("ctrl+p"),
+		key.WithHelp("ctrl+p", "profile"),
+	),
 }
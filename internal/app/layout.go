@@ -1,6 +1,7 @@
 package app
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -11,7 +12,7 @@ import (
 
 const sidebarWidth = 26 // 20 content + 2 padding + 2 border + 2 extra
 
-func renderProjectBar(selectedProject, selectedBoard string, width int) string {
+func renderProjectBar(selectedProject, selectedBoard, profile string, width int) string {
 	projectDisplay := selectedProject
 	if projectDisplay == "" {
 		projectDisplay = "(none)"
@@ -22,6 +23,9 @@ func renderProjectBar(selectedProject, selectedBoard string, width int) string {
 	}
 
 	left := "  Project: " + projectDisplay
+	if profile != "" {
+		left += "   Profile: " + profile
+	}
 	right := "Board: " + boardDisplay + "  "
 
 	// Pad between left and right zones
@@ -49,7 +53,7 @@ func renderSidebar(pages []PageID, active PageID, pageMap map[PageID]Page, heigh
 	return ui.Panel("gust", b.String(), sidebarWidth, height, focused)
 }
 
-func renderStatusBar(pageHelp []key.Binding, width int, focus FocusArea, wsRoot string) string {
+func renderStatusBar(pageHelp []key.Binding, width int, focus FocusArea, wsRoot string, runningJobs int, hasProfiles bool) string {
 	var parts []string
 
 	// Focus-specific instructions
@@ -70,6 +74,11 @@ func renderStatusBar(pageHelp []key.Binding, width int, focus FocusArea, wsRoot
 	// Always add global keys
 	parts = append(parts,
 		ui.StatusKey("tab", "focus"),
+	)
+	if hasProfiles {
+		parts = append(parts, ui.StatusKey("ctrl+p", "profile"))
+	}
+	parts = append(parts,
 		ui.StatusKey("?", "help"),
 		ui.StatusKey("q", "quit"),
 	)
@@ -82,6 +91,9 @@ func renderStatusBar(pageHelp []key.Binding, width int, focus FocusArea, wsRoot
 		wsDisplay = "…" + wsDisplay[len(wsDisplay)-21:]
 	}
 	right := wsDisplay
+	if runningJobs > 0 {
+		right = fmt.Sprintf("%d running  %s", runningJobs, wsDisplay)
+	}
 
 	// Build left+right layout
 	leftRendered := ui.StatusBarStyle.Render(left)
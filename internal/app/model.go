@@ -15,6 +15,11 @@ const (
 	FocusContent
 )
 
+// JobCounter reports how many background commands are currently running.
+type JobCounter interface {
+	Running() int
+}
+
 type Model struct {
 	pages           map[PageID]Page
 	activePage      PageID
@@ -28,12 +33,14 @@ type Model struct {
 	cfg             *config.Config
 	wsRoot          string
 	manifestPath    string
+	jobs            JobCounter
 }
 
-func New(pages map[PageID]Page, cfg *config.Config, wsRoot string, manifestPath string) Model {
+func New(pages map[PageID]Page, cfg *config.Config, wsRoot string, manifestPath string, jobs JobCounter) Model {
 	return Model{
 		pages:           pages,
 		cfg:             cfg,
+		jobs:            jobs,
 		wsRoot:          wsRoot,
 		manifestPath:    manifestPath,
 		selectedProject: cfg.LastProject,
@@ -134,16 +141,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, tea.Batch(cmds...)
 
+	case ShowPageMsg:
+		if _, ok := m.pages[msg.Page]; ok {
+			m.activePage = msg.Page
+			m.focus = FocusContent
+		}
+		return m, nil
+
 	case tea.KeyMsg:
 		// When a page has an active text input, forward all keys
-		// directly to the page — only ctrl+c still quits.
+		// directly to the page — only ctrl+c, tab and ctrl+p stay global.
 		if m.focus == FocusContent {
 			if ic, ok := m.pages[m.activePage].(InputCapturer); ok && ic.InputCaptured() {
 				if msg.String() == "ctrl+c" {
 					return m, tea.Quit
 				}
-				// Always let Tab through so it can toggle focus to the nav panel.
-				if !key.Matches(msg, GlobalKeys.ToggleFocus) {
+				// Always let Tab through so it can toggle focus to the nav panel,
+				// and ctrl+p so profiles can be switched from any page.
+				if !key.Matches(msg, GlobalKeys.ToggleFocus) && !key.Matches(msg, GlobalKeys.Profile) {
 					page := m.pages[m.activePage]
 					newPage, cmd := page.Update(msg)
 					m.pages[m.activePage] = newPage
@@ -159,6 +174,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, GlobalKeys.Help):
 			m.showHelp = !m.showHelp
 			return m, nil
+		case key.Matches(msg, GlobalKeys.Profile):
+			return m, m.switchProfile()
 		case key.Matches(msg, GlobalKeys.ToggleFocus):
 			if m.focus == FocusSidebar {
 				m.focus = FocusContent
@@ -218,15 +235,54 @@ func (m Model) View() string {
 
 	page := m.pages[m.activePage]
 
-	projectBar := renderProjectBar(m.selectedProject, m.selectedBoard, m.width)
+	projectBar := renderProjectBar(m.selectedProject, m.selectedBoard, m.cfg.ActiveProfile, m.width)
 	sidebar := renderSidebar(PageOrder, m.activePage, m.pages, contentHeight, m.focus == FocusSidebar)
 	content := ui.Panel(page.Name(), page.View(), contentWidth, contentHeight, m.focus == FocusContent)
 
-	statusBar := renderStatusBar(page.ShortHelp(), m.width, m.focus, m.wsRoot)
+	running := 0
+	if m.jobs != nil {
+		running = m.jobs.Running()
+	}
+	statusBar := renderStatusBar(page.ShortHelp(), m.width, m.focus, m.wsRoot, running, len(m.cfg.Profiles) > 0)
 
 	return renderLayout(projectBar, sidebar, content, statusBar)
 }
 
+// switchProfile activates the next build profile, or none after the last
+// one, saves the choice and broadcasts the selection it implies.
+func (m *Model) switchProfile() tea.Cmd {
+	next, ok := m.cfg.NextProfile()
+	if !ok {
+		m.cfg.ActiveProfile = ""
+		_ = config.Save(*m.cfg, m.wsRoot, false)
+		return send(ProfileSelectedMsg{})
+	}
+	m.cfg.ApplyProfile(next)
+	_ = config.Save(*m.cfg, m.wsRoot, false)
+
+	cmds := []tea.Cmd{send(ProfileSelectedMsg{Profile: next})}
+	if next.Project != "" {
+		cmds = append(cmds, send(ProjectSelectedMsg{Path: next.Project}))
+	}
+	if next.Board != "" {
+		cmds = append(cmds, send(BoardSelectedMsg{Board: next.Board}))
+	}
+	if len(next.Shields) > 0 {
+		cmds = append(cmds, send(ShieldSelectedMsg{Shield: m.cfg.LastShield}))
+	}
+	if next.BuildDir != "" {
+		cmds = append(cmds, send(BuildDirChangedMsg{Dir: next.BuildDir}))
+	}
+	if next.FlashRunner != "" {
+		cmds = append(cmds, send(FlashRunnerChangedMsg{Runner: next.FlashRunner}))
+	}
+	return tea.Batch(cmds...)
+}
+
+func send(msg tea.Msg) tea.Cmd {
+	return func() tea.Msg { return msg }
+}
+
 func (m *Model) nextPage() {
 	for i, id := range PageOrder {
 		if id == m.activePage {
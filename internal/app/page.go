@@ -3,6 +3,8 @@ package app
 import (
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/buckleypaul/gust/internal/config"
 )
 
 // PageID identifies each page in the application.
@@ -11,20 +13,28 @@ type PageID int
 const (
 	WorkspacePage PageID = iota
 	ProjectPage
+	MatrixPage
 	MonitorPage
 	TestPage
 	ArtifactsPage
+	FootprintPage
+	CMakeCachePage
 	WestPage
+	JobsPage
 	SettingsPage
 )
 
 var PageOrder = []PageID{
 	WorkspacePage,
 	ProjectPage,
+	MatrixPage,
 	MonitorPage,
 	TestPage,
 	ArtifactsPage,
+	FootprintPage,
+	CMakeCachePage,
 	WestPage,
+	JobsPage,
 	SettingsPage,
 }
 
@@ -68,3 +78,15 @@ type BuildDirChangedMsg struct {
 type FlashRunnerChangedMsg struct {
 	Runner string
 }
+
+// ShowPageMsg asks the app to switch to a page and focus its content.
+type ShowPageMsg struct {
+	Page PageID
+}
+
+// ProfileSelectedMsg is broadcast to all pages when a build profile is
+// switched to from the project bar. The profile's project, board, shields,
+// build directory and runner are also broadcast with their own messages.
+type ProfileSelectedMsg struct {
+	Profile config.Profile
+}
@@ -0,0 +1,355 @@
+// Package cli implements gust's headless subcommands, which run the same
+// configured west commands as the TUI and record them in the same history.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/buckleypaul/gust/internal/config"
+	"github.com/buckleypaul/gust/internal/store"
+	"github.com/buckleypaul/gust/internal/west"
+)
+
+// Exit codes returned by Run.
+const (
+	ExitOK        = 0   // command succeeded
+	ExitFailed    = 1   // the west command failed or history could not be read
+	ExitUsage     = 2   // bad flags, unknown command or missing board
+	ExitCancelled = 130 // interrupted, as a shell reports SIGINT
+)
+
+// Env is everything a subcommand needs from the detected workspace.
+type Env struct {
+	WsRoot string
+	Cfg    config.Config
+	Store  *store.Store
+	Runner west.Runner
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, env Env, args []string) int
+}
+
+var commands = []command{
+	{"build", "Build the selected project for the selected board", runBuild},
+	{"flash", "Flash the last build", runFlash},
+	{"test", "Build and run the project's run target", runTest},
+	{"history", "Show build, flash or test history", runHistory},
+}
+
+// IsCommand reports whether name is a subcommand Run understands, including
+// help.
+func IsCommand(name string) bool {
+	if IsHelp(name) {
+		return true
+	}
+	for _, c := range commands {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsHelp reports whether arg asks for the top-level usage.
+func IsHelp(arg string) bool {
+	return arg == "help" || arg == "-h" || arg == "--help"
+}
+
+// Usage writes the list of subcommands to w.
+func Usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: gust [command] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Without a command gust starts the interactive TUI.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'gust <command> -h' for the flags of a command.")
+}
+
+// Run executes the subcommand named by args[0] and returns the process exit
+// code. Cancelling ctx stops the running west command.
+func Run(ctx context.Context, env Env, args []string) int {
+	if len(args) == 0 || IsHelp(args[0]) {
+		Usage(env.Stdout)
+		return ExitOK
+	}
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(ctx, env, args[1:])
+		}
+	}
+	fmt.Fprintf(env.Stderr, "gust: unknown command %q\n\n", args[0])
+	Usage(env.Stderr)
+	return ExitUsage
+}
+
+// newFlagSet returns a flag set that reports errors to env.Stderr.
+func newFlagSet(env Env, name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(env.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(env.Stderr, "Usage: gust %s %s\n\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args and returns the exit code to stop with, or -1 to
+// continue.
+func parseFlags(fs *flag.FlagSet, args []string) int {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+	return -1
+}
+
+// report is the JSON document printed by build, flash and test.
+type report struct {
+	Command  string `json:"command"`
+	ExitCode int    `json:"exit_code"`
+	Record   any    `json:"record"`
+}
+
+// execute runs `west args...` through env.Runner, streaming its output to
+// out, and returns the final result. Output lines are also fed to diag when
+// it is non-nil.
+func execute(ctx context.Context, env Env, out io.Writer, args []string, diag *west.DiagnosticParser) west.CommandResultMsg {
+	fmt.Fprintf(out, "$ west %s\n", strings.Join(args, " "))
+	msg := west.Drain(env.Runner.Run(ctx, "west", args...), func(line string) {
+		fmt.Fprintln(out, line)
+		if diag != nil {
+			diag.Feed(line)
+		}
+	})
+	result, ok := msg.(west.CommandResultMsg)
+	if !ok {
+		return west.CommandResultMsg{Output: "unexpected result\n", ExitCode: -1}
+	}
+	if !result.Streamed {
+		fmt.Fprint(out, result.Output)
+		if diag != nil {
+			for _, line := range strings.Split(result.Output, "\n") {
+				diag.Feed(line)
+			}
+		}
+	}
+	return result
+}
+
+// finish prints the outcome of a west command and maps it to an exit code.
+// With asJSON the report goes to stdout and the command output has already
+// gone to stderr.
+func finish(env Env, op string, result west.CommandResultMsg, record any, saveErr error, asJSON bool) int {
+	code := ExitOK
+	status := "succeeded"
+	switch {
+	case result.Cancelled:
+		code = ExitCancelled
+		status = "cancelled"
+	case result.ExitCode != 0:
+		code = ExitFailed
+		status = fmt.Sprintf("failed (exit code: %d)", result.ExitCode)
+	}
+	if saveErr != nil {
+		fmt.Fprintf(env.Stderr, "gust: history save failed: %v\n", saveErr)
+	}
+
+	if asJSON {
+		writeJSON(env.Stdout, report{Command: op, ExitCode: result.ExitCode, Record: record})
+		return code
+	}
+	fmt.Fprintf(env.Stdout, "\n%s %s in %s\n", strings.ToUpper(op[:1])+op[1:], status, result.Duration.Round(time.Millisecond))
+	return code
+}
+
+// outputFor returns where west output goes: stdout normally, stderr when
+// stdout is reserved for JSON.
+func outputFor(env Env, asJSON bool) io.Writer {
+	if asJSON {
+		return env.Stderr
+	}
+	return env.Stdout
+}
+
+func runBuild(ctx context.Context, env Env, args []string) int {
+	fs := newFlagSet(env, "build", "[flags] [project]")
+	board := fs.String("board", env.Cfg.DefaultBoard, "target board")
+	shield := fs.String("shield", env.Cfg.LastShield, "shield to build with")
+	buildDir := fs.String("d", env.Cfg.BuildDir, "build directory")
+	pristine := fs.Bool("p", false, "pristine build")
+	sysbuild := fs.Bool("sysbuild", false, "build with sysbuild")
+	cmakeArgs := fs.String("cmake", "", "extra CMake arguments")
+	profileName := fs.String("profile", env.Cfg.ActiveProfile, "named build profile from the config")
+	asJSON := fs.Bool("json", false, "print the build record as JSON")
+	if code := parseFlags(fs, args); code >= 0 {
+		return code
+	}
+
+	project := env.Cfg.LastProject
+	var profile config.Profile
+	if *profileName != "" {
+		var ok bool
+		if profile, ok = env.Cfg.Profile(*profileName); !ok {
+			fmt.Fprintf(env.Stderr, "gust build: unknown profile %q\n", *profileName)
+			return ExitUsage
+		}
+		// The profile supplies every option not given explicitly on the
+		// command line.
+		set := map[string]bool{}
+		fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+		if profile.Project != "" {
+			project = profile.Project
+		}
+		if profile.Board != "" && !set["board"] {
+			*board = profile.Board
+		}
+		if len(profile.Shields) > 0 && !set["shield"] {
+			*shield = strings.Join(profile.Shields, " ")
+		}
+		if profile.BuildDir != "" && !set["d"] {
+			*buildDir = profile.BuildDir
+		}
+		if !set["p"] {
+			*pristine = profile.Pristine
+		}
+		if !set["sysbuild"] {
+			*sysbuild = profile.Sysbuild
+		}
+		if !set["cmake"] {
+			*cmakeArgs = profile.CMakeArgs
+		}
+	}
+	if fs.NArg() > 0 {
+		project = fs.Arg(0)
+	}
+	if project == "" {
+		project = "."
+	}
+	if *board == "" {
+		fmt.Fprintln(env.Stderr, "gust build: no board selected; pass -board or choose one in gust")
+		return ExitUsage
+	}
+	*buildDir = west.ExpandBuildDir(*buildDir, project, *board, profile.Name)
+
+	projectPath := west.ProjectPath(env.WsRoot, project)
+	git := west.ReadGitState(projectPath)
+	start := time.Now()
+	var diag west.DiagnosticParser
+	opts := west.BuildOptions{
+		Board:        *board,
+		Shield:       *shield,
+		BuildDir:     *buildDir,
+		Project:      projectPath,
+		Pristine:     *pristine,
+		Sysbuild:     *sysbuild,
+		CMakeArgs:    *cmakeArgs,
+		ExtraConf:    profile.ExtraConf,
+		ExtraOverlay: profile.ExtraOverlay,
+		Snippets:     profile.Snippets,
+	}
+	result := execute(ctx, env, outputFor(env, *asJSON), west.BuildArgs(opts), &diag)
+
+	record := west.NewBuildRecord(opts, project, profile.Name, git, start, result, &diag)
+	mount := ""
+	if env.Cfg.UseContainer() {
+		mount = env.Cfg.Container.Mount
+		if mount == "" {
+			mount = west.DefaultContainerMount
+		}
+	}
+	if env.Cfg.CompileCommands != config.CompileCommandsOff {
+		filter := env.Cfg.CompileCommands == config.CompileCommandsFiltered
+		if _, err := west.SyncCompileCommands(env.WsRoot, *buildDir, projectPath, filter, mount); err != nil {
+			fmt.Fprintf(env.Stderr, "gust build: compile_commands.json: %v\n", err)
+		}
+	}
+	if record.Success {
+		provenance := west.CollectProvenance(ctx, env.Runner, env.WsRoot, record.BuildDir, mount)
+		record.Provenance = (*store.Provenance)(&provenance)
+	}
+	if err := west.CompleteBuildRecord(&record, env.Store, env.WsRoot); err != nil {
+		fmt.Fprintf(env.Stderr, "gust build: archiving outputs failed: %v\n", err)
+	}
+	// Only archived builds have an ID by now; the report needs the one
+	// AddBuild would otherwise give its own copy.
+	if record.ID == "" {
+		record.ID = store.NewRecordID(record.Timestamp)
+	}
+	return finish(env, "build", result, record, env.Store.AddBuild(record), *asJSON)
+}
+
+func runFlash(ctx context.Context, env Env, args []string) int {
+	fs := newFlagSet(env, "flash", "[flags]")
+	buildDir := fs.String("d", env.Cfg.BuildDir, "build directory")
+	runner := fs.String("runner", env.Cfg.FlashRunner, "flash runner override")
+	board := fs.String("board", env.Cfg.DefaultBoard, "board recorded in history")
+	domain := fs.String("domain", "", "sysbuild domain to flash (default: all)")
+	asJSON := fs.Bool("json", false, "print the flash record as JSON")
+	if code := parseFlags(fs, args); code >= 0 {
+		return code
+	}
+
+	dir := west.ExpandBuildDir(*buildDir, env.Cfg.LastProject, *board, env.Cfg.ActiveProfile)
+	if runners, err := west.ReadFlashRunners(env.WsRoot, dir); err == nil {
+		if err := runners.Validate(*runner); err != nil {
+			fmt.Fprintf(env.Stderr, "gust flash: %v\n", err)
+			return ExitUsage
+		}
+	}
+	start := time.Now()
+	result := execute(ctx, env, outputFor(env, *asJSON), west.FlashArgs(dir, *runner, *domain), nil)
+	record := store.FlashRecord{
+		Board:     *board,
+		Timestamp: start,
+		Success:   result.ExitCode == 0 && !result.Cancelled,
+		Duration:  result.Duration.String(),
+		Cancelled: result.Cancelled,
+		Domain:    *domain,
+	}
+	return finish(env, "flash", result, record, env.Store.AddFlash(record), *asJSON)
+}
+
+func runTest(ctx context.Context, env Env, args []string) int {
+	fs := newFlagSet(env, "test", "[flags] [project]")
+	board := fs.String("board", env.Cfg.DefaultBoard, "target board")
+	buildDir := fs.String("d", env.Cfg.BuildDir, "build directory")
+	asJSON := fs.Bool("json", false, "print the test record as JSON")
+	if code := parseFlags(fs, args); code >= 0 {
+		return code
+	}
+
+	project := env.Cfg.LastProject
+	if fs.NArg() > 0 {
+		project = fs.Arg(0)
+	}
+
+	start := time.Now()
+	result := execute(ctx, env, outputFor(env, *asJSON),
+		west.TestArgs(*board, west.ExpandBuildDir(*buildDir, project, *board, env.Cfg.ActiveProfile),
+			west.ProjectPath(env.WsRoot, project)), nil)
+	record := store.TestRecord{
+		Board:     *board,
+		Timestamp: start,
+		Success:   result.ExitCode == 0 && !result.Cancelled,
+		Duration:  result.Duration.String(),
+		Cancelled: result.Cancelled,
+	}
+	return finish(env, "test", result, record, env.Store.AddTest(record), *asJSON)
+}
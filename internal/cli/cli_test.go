@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/buckleypaul/gust/internal/config"
+	"github.com/buckleypaul/gust/internal/store"
+	"github.com/buckleypaul/gust/internal/west"
+)
+
+func newTestEnv(t *testing.T, entries ...west.TranscriptEntry) (Env, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	wsRoot := t.TempDir()
+	cfg := config.Defaults()
+	cfg.DefaultBoard = "nrf52840dk"
+	cfg.LastProject = filepath.Join("apps", "blinky")
+	var stdout, stderr bytes.Buffer
+	env := Env{
+		WsRoot: wsRoot,
+		Cfg:    cfg,
+		Store:  store.New(filepath.Join(wsRoot, ".gust")),
+		Runner: west.NewReplayRunner(west.Transcript{Commands: entries}, wsRoot),
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	return env, &stdout, &stderr
+}
+
+func TestBuildUsesSavedSelectionAndRecordsHistory(t *testing.T) {
+	env, stdout, _ := newTestEnv(t, west.TranscriptEntry{
+		Method:     "run",
+		Name:       "west",
+		Args:       []string{"build", "-b", "nrf52840dk", "-d", "build", "$WORKSPACE/apps/blinky"},
+		Output:     []string{"[132/132] Linking C executable zephyr/zephyr.elf"},
+		DurationMS: 1500,
+	})
+
+	code := Run(context.Background(), env, []string{"build"})
+	if code != ExitOK {
+		t.Fatalf("expected exit %d, got %d", ExitOK, code)
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "Linking C executable") || !strings.Contains(out, "Build succeeded in 1.5s") {
+		t.Fatalf("unexpected output %q", out)
+	}
+
+	builds, err := env.Store.Builds()
+	if err != nil {
+		t.Fatalf("Builds() error: %v", err)
+	}
+	if len(builds) != 1 || !builds[0].Success || builds[0].Board != "nrf52840dk" || builds[0].App != filepath.Join("apps", "blinky") {
+		t.Fatalf("expected one successful build record, got %+v", builds)
+	}
+}
+
+func TestBuildFailureReturnsFailedAndJSONReport(t *testing.T) {
+	env, stdout, stderr := newTestEnv(t, west.TranscriptEntry{
+		Method:   "run",
+		Name:     "west",
+		Args:     []string{"build", "-b", "native_sim", "-d", "build", "-p", "always", "$WORKSPACE/apps/other"},
+		Output:   []string{"/src/main.c:3:5: error: 'foo' undeclared"},
+		ExitCode: 1,
+	})
+
+	code := Run(context.Background(), env, []string{"build", "-json", "-board", "native_sim", "-p", "apps/other"})
+	if code != ExitFailed {
+		t.Fatalf("expected exit %d, got %d", ExitFailed, code)
+	}
+	if !strings.Contains(stderr.String(), "'foo' undeclared") {
+		t.Fatalf("expected west output on stderr in JSON mode, got %q", stderr.String())
+	}
+
+	var rep struct {
+		Command  string            `json:"command"`
+		ExitCode int               `json:"exit_code"`
+		Record   store.BuildRecord `json:"record"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &rep); err != nil {
+		t.Fatalf("stdout is not a JSON report: %v\n%s", err, stdout.String())
+	}
+	if rep.Command != "build" || rep.ExitCode != 1 || rep.Record.Success || !rep.Record.Pristine || rep.Record.Board != "native_sim" || rep.Record.Errors != 1 {
+		t.Fatalf("unexpected report %+v", rep)
+	}
+	builds, err := env.Store.Builds()
+	if err != nil || len(builds) != 1 {
+		t.Fatalf("expected one stored build, got %+v, %v", builds, err)
+	}
+	if rep.Record.ID == "" || rep.Record.ID != builds[0].ID {
+		t.Fatalf("expected report ID %q to match stored record %q", rep.Record.ID, builds[0].ID)
+	}
+}
+
+func TestBuildWithoutBoardIsUsageError(t *testing.T) {
+	env, _, stderr := newTestEnv(t)
+	env.Cfg.DefaultBoard = ""
+
+	if code := Run(context.Background(), env, []string{"build"}); code != ExitUsage {
+		t.Fatalf("expected exit %d, got %d", ExitUsage, code)
+	}
+	if !strings.Contains(stderr.String(), "no board selected") {
+		t.Fatalf("expected board error, got %q", stderr.String())
+	}
+}
+
+func TestFlashAndTestRecordHistory(t *testing.T) {
+	env, _, _ := newTestEnv(t,
+		west.TranscriptEntry{Method: "run", Name: "west", Args: []string{"flash", "-d", "build", "--runner", "jlink"}},
+		west.TranscriptEntry{Method: "run", Name: "west", Args: []string{"build", "-t", "run", "-b", "nrf52840dk", "-d", "build", "$WORKSPACE/apps/blinky"}, ExitCode: 2},
+	)
+	env.Cfg.FlashRunner = "jlink"
+
+	if code := Run(context.Background(), env, []string{"flash"}); code != ExitOK {
+		t.Fatalf("flash: expected exit %d, got %d", ExitOK, code)
+	}
+	if code := Run(context.Background(), env, []string{"test"}); code != ExitFailed {
+		t.Fatalf("test: expected exit %d, got %d", ExitFailed, code)
+	}
+
+	flashes, _ := env.Store.Flashes()
+	if len(flashes) != 1 || !flashes[0].Success {
+		t.Fatalf("expected one successful flash, got %+v", flashes)
+	}
+	tests, _ := env.Store.Tests()
+	if len(tests) != 1 || tests[0].Success || tests[0].Board != "nrf52840dk" {
+		t.Fatalf("expected one failed test on nrf52840dk, got %+v", tests)
+	}
+}
+
+func TestFlashRejectsRunnerTheBoardDoesNotSupport(t *testing.T) {
+	env, _, stderr := newTestEnv(t)
+	env.Cfg.FlashRunner = "stlink"
+	path := filepath.Join(env.WsRoot, "build", "zephyr", "runners.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("runners:\n- nrfjprog\n- jlink\nflash-runner: nrfjprog\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := Run(context.Background(), env, []string{"flash"}); code != ExitUsage {
+		t.Fatalf("expected exit %d, got %d", ExitUsage, code)
+	}
+	if !strings.Contains(stderr.String(), `runner "stlink" is not supported`) {
+		t.Fatalf("expected runner error, got %q", stderr.String())
+	}
+	if flashes, _ := env.Store.Flashes(); len(flashes) != 0 {
+		t.Fatalf("expected no flash to be recorded, got %+v", flashes)
+	}
+}
+
+func TestHistoryPrintsNewestLastAndLimits(t *testing.T) {
+	env, stdout, _ := newTestEnv(t)
+	for _, board := range []string{"a_board", "b_board", "c_board"} {
+		if err := env.Store.AddBuild(store.BuildRecord{Board: board, Success: true, Duration: "1s"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if code := Run(context.Background(), env, []string{"history", "-n", "2"}); code != ExitOK {
+		t.Fatalf("expected exit %d, got %d", ExitOK, code)
+	}
+	out := stdout.String()
+	if strings.Contains(out, "a_board") || !strings.Contains(out, "b_board") || !strings.Contains(out, "c_board") {
+		t.Fatalf("expected the last two builds, got %q", out)
+	}
+
+	stdout.Reset()
+	if code := Run(context.Background(), env, []string{"history", "-json", "tests"}); code != ExitOK {
+		t.Fatalf("expected exit %d, got %d", ExitOK, code)
+	}
+	if strings.TrimSpace(stdout.String()) != "[]" {
+		t.Fatalf("expected empty JSON array, got %q", stdout.String())
+	}
+
+	if code := Run(context.Background(), env, []string{"history", "deploys"}); code != ExitUsage {
+		t.Fatalf("expected exit %d for unknown history, got %d", ExitUsage, code)
+	}
+}
+
+func TestUnknownCommandIsUsageError(t *testing.T) {
+	env, _, stderr := newTestEnv(t)
+	if code := Run(context.Background(), env, []string{"deploy"}); code != ExitUsage {
+		t.Fatalf("expected exit %d, got %d", ExitUsage, code)
+	}
+	if !strings.Contains(stderr.String(), `unknown command "deploy"`) {
+		t.Fatalf("expected unknown command message, got %q", stderr.String())
+	}
+}
+
+func TestBuildAppliesProfileUnderExplicitFlags(t *testing.T) {
+	env, _, _ := newTestEnv(t, west.TranscriptEntry{
+		Method: "run",
+		Name:   "west",
+		Args: []string{"build", "-b", "native_sim", "-d", "build-dbg", "-p", "always", "-S", "rtt-console",
+			"$WORKSPACE/apps/blinky", "--", "-DEXTRA_CONF_FILE=debug.conf"},
+	})
+	env.Cfg.Profiles = []config.Profile{{
+		Name: "debug", Board: "qemu_x86", BuildDir: "build-dbg", Pristine: true,
+		ExtraConf: []string{"debug.conf"}, Snippets: []string{"rtt-console"},
+	}}
+
+	if code := Run(context.Background(), env, []string{"build", "-profile", "debug", "-board", "native_sim"}); code != ExitOK {
+		t.Fatalf("expected exit %d, got %d", ExitOK, code)
+	}
+	builds, _ := env.Store.Builds()
+	if len(builds) != 1 || builds[0].Profile != "debug" || builds[0].Board != "native_sim" || builds[0].BuildDir != "build-dbg" {
+		t.Fatalf("expected profile build recorded, got %+v", builds)
+	}
+
+	if code := Run(context.Background(), env, []string{"build", "-profile", "nope"}); code != ExitUsage {
+		t.Fatalf("expected exit %d for unknown profile, got %d", ExitUsage, code)
+	}
+}
+
+func TestBuildDirTemplateIsExpandedForBuildAndFlash(t *testing.T) {
+	env, _, _ := newTestEnv(t,
+		west.TranscriptEntry{Method: "run", Name: "west", Args: []string{"build", "-b", "nrf52840dk", "-d", "build/apps_blinky/nrf52840dk", "$WORKSPACE/apps/blinky"}},
+		west.TranscriptEntry{Method: "run", Name: "west", Args: []string{"flash", "-d", "build/apps_blinky/nrf52840dk"}},
+	)
+	env.Cfg.BuildDir = "build/{project}/{board}"
+
+	if code := Run(context.Background(), env, []string{"build"}); code != ExitOK {
+		t.Fatalf("build: expected exit %d, got %d", ExitOK, code)
+	}
+	if code := Run(context.Background(), env, []string{"flash"}); code != ExitOK {
+		t.Fatalf("flash: expected exit %d, got %d", ExitOK, code)
+	}
+	builds, _ := env.Store.Builds()
+	if len(builds) != 1 || builds[0].BuildDir != "build/apps_blinky/nrf52840dk" {
+		t.Fatalf("expected the expanded build dir recorded, got %+v", builds)
+	}
+}
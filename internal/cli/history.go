@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/buckleypaul/gust/internal/store"
+)
+
+const historyTimeFormat = "2006-01-02 15:04:05"
+
+func runHistory(_ context.Context, env Env, args []string) int {
+	fs := newFlagSet(env, "history", "[flags] [builds|flashes|tests]")
+	limit := fs.Int("n", 20, "show at most n records, newest last (0 for all)")
+	asJSON := fs.Bool("json", false, "print records as a JSON array")
+	if code := parseFlags(fs, args); code >= 0 {
+		return code
+	}
+
+	kind := "builds"
+	if fs.NArg() > 0 {
+		kind = fs.Arg(0)
+	}
+
+	var (
+		records any
+		rows    []string
+		err     error
+	)
+	switch kind {
+	case "builds":
+		var builds []store.BuildRecord
+		builds, err = env.Store.Builds()
+		builds = tail(builds, *limit)
+		records = builds
+		for _, r := range builds {
+			row := fmt.Sprintf("%s  %-9s  %-10s  %-24s  %s",
+				r.Timestamp.Format(historyTimeFormat), status(r.Success, r.Cancelled), r.Duration, r.Board, r.App)
+			if r.GitCommit != "" {
+				row += "  @" + r.GitCommit
+				if r.GitDirty {
+					row += "-dirty"
+				}
+			}
+			rows = append(rows, row)
+		}
+	case "flashes":
+		var flashes []store.FlashRecord
+		flashes, err = env.Store.Flashes()
+		flashes = tail(flashes, *limit)
+		records = flashes
+		for _, r := range flashes {
+			rows = append(rows, fmt.Sprintf("%s  %-9s  %-10s  %s",
+				r.Timestamp.Format(historyTimeFormat), status(r.Success, r.Cancelled), r.Duration, r.Board))
+		}
+	case "tests":
+		var tests []store.TestRecord
+		tests, err = env.Store.Tests()
+		tests = tail(tests, *limit)
+		records = tests
+		for _, r := range tests {
+			rows = append(rows, fmt.Sprintf("%s  %-9s  %-10s  %s",
+				r.Timestamp.Format(historyTimeFormat), status(r.Success, r.Cancelled), r.Duration, r.Board))
+		}
+	default:
+		fmt.Fprintf(env.Stderr, "gust history: unknown history %q (want builds, flashes or tests)\n", kind)
+		return ExitUsage
+	}
+	if err != nil {
+		fmt.Fprintf(env.Stderr, "gust history: %v\n", err)
+		return ExitFailed
+	}
+
+	if *asJSON {
+		return writeJSON(env.Stdout, records)
+	}
+	if len(rows) == 0 {
+		fmt.Fprintf(env.Stdout, "No %s recorded yet.\n", kind)
+		return ExitOK
+	}
+	for _, row := range rows {
+		fmt.Fprintln(env.Stdout, row)
+	}
+	return ExitOK
+}
+
+// tail returns the last n records, or all of them when n <= 0. It never
+// returns nil so JSON output is always an array.
+func tail[T any](records []T, n int) []T {
+	if records == nil {
+		records = []T{}
+	}
+	if n > 0 && len(records) > n {
+		return records[len(records)-n:]
+	}
+	return records
+}
+
+func status(success, cancelled bool) string {
+	switch {
+	case cancelled:
+		return "CANCELLED"
+	case success:
+		return "OK"
+	default:
+		return "FAILED"
+	}
+}
+
+func writeJSON(w io.Writer, v any) int {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return ExitFailed
+	}
+	return ExitOK
+}
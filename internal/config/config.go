@@ -4,11 +4,28 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 const (
 	DefaultBaudRate = 115200
 	DefaultBuildDir = "build"
+	// DefaultMatrixJobs is how many matrix builds run at once. Each build
+	// already uses every core through ninja.
+	DefaultMatrixJobs = 2
+)
+
+// Execution backends for west and toolchain commands.
+const (
+	BackendLocal     = "local"
+	BackendContainer = "container"
+)
+
+// Values for Config.CompileCommands.
+const (
+	CompileCommandsLink     = "link"     // link the build's database (the default)
+	CompileCommandsFiltered = "filtered" // link a copy without Zephyr's own sources
+	CompileCommandsOff      = "off"
 )
 
 // Config holds all gust configuration.
@@ -21,6 +38,108 @@ type Config struct {
 	VenvPath       string `json:"venv_path,omitempty"`
 	LastProject    string `json:"last_project,omitempty"`
 	LastShield     string `json:"last_shield,omitempty"`
+	MatrixJobs     int    `json:"matrix_jobs,omitempty"`
+
+	// CompileCommands controls the compile_commands.json link kept at the
+	// project root for clangd: CompileCommandsLink when empty,
+	// CompileCommandsFiltered or CompileCommandsOff.
+	CompileCommands string `json:"compile_commands,omitempty"`
+
+	// Notify maps "build", "flash", "test" and "update" to how gust
+	// announces that operation finishing: any of "bell", "osc9", "osc777"
+	// and "command". NotifyCommand is what "command" runs; {title} and
+	// {message} in its arguments are replaced.
+	Notify        map[string][]string `json:"notify,omitempty"`
+	NotifyCommand []string            `json:"notify_command,omitempty"`
+
+	// Profiles are named build presets; ActiveProfile is the one last
+	// switched to from the project bar.
+	Profiles      []Profile `json:"profiles,omitempty"`
+	ActiveProfile string    `json:"active_profile,omitempty"`
+
+	// Backend selects where commands run: BackendLocal (the default) or
+	// BackendContainer, which uses Container.
+	Backend   string           `json:"backend,omitempty"`
+	Container *ContainerConfig `json:"container,omitempty"`
+}
+
+// Profile is a named set of build settings, e.g. "dev-nrf52" or
+// "release-nrf53". Empty fields leave the current setting alone.
+type Profile struct {
+	Name         string   `json:"name"`
+	Project      string   `json:"project,omitempty"`
+	Board        string   `json:"board,omitempty"`
+	Shields      []string `json:"shields,omitempty"`
+	BuildDir     string   `json:"build_dir,omitempty"`
+	Pristine     bool     `json:"pristine,omitempty"`
+	Sysbuild     bool     `json:"sysbuild,omitempty"`
+	CMakeArgs    string   `json:"cmake_args,omitempty"`
+	ExtraConf    []string `json:"extra_conf,omitempty"`    // EXTRA_CONF_FILE entries
+	ExtraOverlay []string `json:"extra_overlay,omitempty"` // EXTRA_DTC_OVERLAY_FILE entries
+	Snippets     []string `json:"snippets,omitempty"`
+	FlashRunner  string   `json:"flash_runner,omitempty"`
+}
+
+// Profile returns the profile called name.
+func (c Config) Profile(name string) (Profile, bool) {
+	for _, p := range c.Profiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
+
+// NextProfile returns the profile after the active one, or ok=false when
+// the active profile is the last one (switching back to no profile).
+func (c Config) NextProfile() (Profile, bool) {
+	if len(c.Profiles) == 0 {
+		return Profile{}, false
+	}
+	if c.ActiveProfile == "" {
+		return c.Profiles[0], true
+	}
+	for i, p := range c.Profiles {
+		if p.Name == c.ActiveProfile && i+1 < len(c.Profiles) {
+			return c.Profiles[i+1], true
+		}
+	}
+	return Profile{}, false
+}
+
+// ApplyProfile makes p active and copies its project, board, shields, build
+// directory and flash runner into the remembered selection.
+func (c *Config) ApplyProfile(p Profile) {
+	c.ActiveProfile = p.Name
+	if p.Project != "" {
+		c.LastProject = p.Project
+	}
+	if p.Board != "" {
+		c.DefaultBoard = p.Board
+	}
+	if len(p.Shields) > 0 {
+		c.LastShield = strings.Join(p.Shields, " ")
+	}
+	if p.BuildDir != "" {
+		c.BuildDir = p.BuildDir
+	}
+	if p.FlashRunner != "" {
+		c.FlashRunner = p.FlashRunner
+	}
+}
+
+// ContainerConfig describes the container used by the container backend.
+type ContainerConfig struct {
+	Engine  string   `json:"engine,omitempty"` // e.g. "docker" (default) or "podman"
+	Image   string   `json:"image"`
+	Mount   string   `json:"mount,omitempty"`    // workspace path inside the container, default /workdir
+	Env     []string `json:"env,omitempty"`      // NAME to forward, or NAME=value
+	RunArgs []string `json:"run_args,omitempty"` // extra flags for `<engine> run`
+}
+
+// UseContainer reports whether commands should run in a container.
+func (c Config) UseContainer() bool {
+	return c.Backend == BackendContainer && c.Container != nil && c.Container.Image != ""
 }
 
 // Defaults returns a Config with default values.
@@ -28,6 +147,7 @@ func Defaults() Config {
 	return Config{
 		BuildDir:       DefaultBuildDir,
 		SerialBaudRate: DefaultBaudRate,
+		MatrixJobs:     DefaultMatrixJobs,
 	}
 }
 
@@ -112,6 +232,30 @@ func mergeFromFile(cfg *Config, path string) {
 	if fileCfg.LastShield != "" {
 		cfg.LastShield = fileCfg.LastShield
 	}
+	if fileCfg.MatrixJobs > 0 {
+		cfg.MatrixJobs = fileCfg.MatrixJobs
+	}
+	if fileCfg.CompileCommands != "" {
+		cfg.CompileCommands = fileCfg.CompileCommands
+	}
+	if fileCfg.Notify != nil {
+		cfg.Notify = fileCfg.Notify
+	}
+	if fileCfg.NotifyCommand != nil {
+		cfg.NotifyCommand = fileCfg.NotifyCommand
+	}
+	if fileCfg.Profiles != nil {
+		cfg.Profiles = fileCfg.Profiles
+	}
+	if fileCfg.ActiveProfile != "" {
+		cfg.ActiveProfile = fileCfg.ActiveProfile
+	}
+	if fileCfg.Backend != "" {
+		cfg.Backend = fileCfg.Backend
+	}
+	if fileCfg.Container != nil {
+		cfg.Container = fileCfg.Container
+	}
 }
 
 func writeFileAtomic(path string, data []byte, mode os.FileMode) error {
@@ -89,3 +89,66 @@ func TestLastShieldPersistence(t *testing.T) {
 		t.Errorf("expected LastShield=nrf7002ek, got=%s", loaded.LastShield)
 	}
 }
+
+func TestLoadContainerBackend(t *testing.T) {
+	tmp := t.TempDir()
+	gustDir := filepath.Join(tmp, ".gust")
+	os.MkdirAll(gustDir, 0o755)
+	os.WriteFile(filepath.Join(gustDir, "config.json"), []byte(`{
+		"backend": "container",
+		"container": {
+			"engine": "podman",
+			"image": "ghcr.io/zephyrproject-rtos/ci:v0.27.4",
+			"env": ["ZEPHYR_TOOLCHAIN_VARIANT=zephyr", "CCACHE_DIR"]
+		}
+	}`), 0o644)
+
+	cfg := Load(tmp)
+
+	if !cfg.UseContainer() {
+		t.Fatal("expected container backend to be enabled")
+	}
+	if cfg.Container.Engine != "podman" || len(cfg.Container.Env) != 2 {
+		t.Errorf("unexpected container config %+v", cfg.Container)
+	}
+
+	cfg.Container.Image = ""
+	if cfg.UseContainer() {
+		t.Error("expected container backend without an image to be disabled")
+	}
+}
+
+func TestProfilesLoadCycleAndApply(t *testing.T) {
+	tmp := t.TempDir()
+	gustDir := filepath.Join(tmp, ".gust")
+	os.MkdirAll(gustDir, 0o755)
+	os.WriteFile(filepath.Join(gustDir, "config.json"), []byte(`{
+		"default_board": "native_sim",
+		"profiles": [
+			{"name": "dev-nrf52", "project": "apps/blinky", "board": "nrf52840dk", "shields": ["a", "b"], "build_dir": "build/nrf52"},
+			{"name": "release-nrf53", "board": "nrf5340dk/nrf5340/cpuapp", "sysbuild": true}
+		]
+	}`), 0o644)
+
+	cfg := Load(tmp)
+	next, ok := cfg.NextProfile()
+	if !ok || next.Name != "dev-nrf52" {
+		t.Fatalf("expected first profile, got %+v", next)
+	}
+	cfg.ApplyProfile(next)
+	if cfg.ActiveProfile != "dev-nrf52" || cfg.DefaultBoard != "nrf52840dk" || cfg.LastShield != "a b" || cfg.BuildDir != "build/nrf52" {
+		t.Fatalf("profile not applied: %+v", cfg)
+	}
+
+	next, _ = cfg.NextProfile()
+	cfg.ApplyProfile(next)
+	if cfg.LastProject != "apps/blinky" || cfg.BuildDir != "build/nrf52" {
+		t.Errorf("expected empty profile fields to keep the current values, got %+v", cfg)
+	}
+	if _, ok := cfg.NextProfile(); ok {
+		t.Error("expected cycling past the last profile to return to none")
+	}
+	if p, ok := cfg.Profile("release-nrf53"); !ok || !p.Sysbuild {
+		t.Errorf("expected to find release-nrf53, got %+v", p)
+	}
+}
@@ -29,12 +29,12 @@ func TestIntegrationBuildKernelCommon(t *testing.T) {
 	zephyrBase(t)
 
 	runner := west.DefaultRunner{}
-	cmd := runner.Run("west", "build",
+	cmd := runner.Run(context.Background(), "west", "build",
 		"-b", "qemu_cortex_m3",
 		"tests/kernel/common",
 	)
 
-	msg := cmd()
+	msg := west.Drain(cmd, nil)
 	result, ok := msg.(west.CommandResultMsg)
 	if !ok {
 		t.Fatalf("expected CommandResultMsg, got %T", msg)
@@ -53,12 +53,12 @@ func TestIntegrationWestTest(t *testing.T) {
 	zephyrBase(t)
 
 	runner := west.DefaultRunner{}
-	cmd := runner.Run("west", "test",
+	cmd := runner.Run(context.Background(), "west", "test",
 		"-b", "qemu_cortex_m3",
 		"tests/kernel/common",
 	)
 
-	msg := cmd()
+	msg := west.Drain(cmd, nil)
 	result, ok := msg.(west.CommandResultMsg)
 	if !ok {
 		t.Fatalf("expected CommandResultMsg, got %T", msg)
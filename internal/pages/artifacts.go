@@ -1,15 +1,21 @@
 package pages
 
 import (
+	"context"
 	"fmt"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"github.com/buckleypaul/gust/internal/app"
+	"github.com/buckleypaul/gust/internal/config"
 	"github.com/buckleypaul/gust/internal/store"
 	"github.com/buckleypaul/gust/internal/ui"
+	"github.com/buckleypaul/gust/internal/west"
 )
 
 type artifactTab int
@@ -25,38 +31,200 @@ var tabNames = []string{"Builds", "Flashes", "Tests", "Serial Logs"}
 
 type ArtifactsPage struct {
 	store         *store.Store
+	cfg           *config.Config
+	runner        west.Runner
 	activeTab     artifactTab
+	buildCursor   int // selected row of the Builds tab, newest first
+	showDetails   bool
 	width, height int
+
+	// Flashing an archived build
+	flashBuild      *store.BuildRecord
+	flashStart      time.Time
+	output          strings.Builder
+	activeRequestID string
+	cancel          context.CancelFunc
+	seq             int
+	message         string
 }
 
-func NewArtifactsPage(s *store.Store) *ArtifactsPage {
-	return &ArtifactsPage{store: s}
+func NewArtifactsPage(s *store.Store, cfg *config.Config, runners ...west.Runner) *ArtifactsPage {
+	runner := west.RealRunner()
+	if len(runners) > 0 && runners[0] != nil {
+		runner = runners[0]
+	}
+	return &ArtifactsPage{store: s, cfg: cfg, runner: runner}
 }
 
 func (p *ArtifactsPage) Init() tea.Cmd { return nil }
 
 func (p *ArtifactsPage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
 	switch msg := msg.(type) {
+	case west.CommandOutputMsg:
+		if p.activeRequestID == "" || msg.RequestID != p.activeRequestID {
+			return p, nil
+		}
+		p.output.WriteString(msg.Line + "\n")
+		return p, msg.Next
+
+	case west.CommandResultMsg:
+		if p.activeRequestID == "" || msg.RequestID != p.activeRequestID {
+			return p, nil
+		}
+		p.completeFlash(msg)
+		return p, nil
+
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "right":
 			p.activeTab = (p.activeTab + 1) % artifactTab(len(tabNames))
 		case "left":
 			p.activeTab = (p.activeTab - 1 + artifactTab(len(tabNames))) % artifactTab(len(tabNames))
+		case "up":
+			if p.buildCursor > 0 {
+				p.buildCursor--
+			}
+		case "down":
+			if p.buildCursor < len(p.buildRows())-1 {
+				p.buildCursor++
+			}
+		case "f":
+			if p.activeTab == tabBuilds {
+				return p, p.flashSelected()
+			}
+		case "enter":
+			if p.activeTab == tabBuilds {
+				p.showDetails = !p.showDetails
+			}
+		case "r", "R":
+			if p.activeTab == tabBuilds {
+				return p, p.reproduceSelected(msg.String() == "R")
+			}
+		case "ctrl+x":
+			if p.activeRequestID != "" && p.cancel != nil {
+				p.cancel()
+				p.output.WriteString("\nCancelling flash...\n")
+			}
+		case "esc":
+			if p.activeRequestID == "" {
+				p.output.Reset()
+				p.message = ""
+			}
 		}
 	}
 	return p, nil
 }
 
+// buildRows returns the build records shown on the Builds tab, newest first.
+func (p *ArtifactsPage) buildRows() []store.BuildRecord {
+	builds, err := p.store.Builds()
+	if err != nil {
+		return nil
+	}
+	var rows []store.BuildRecord
+	for i := len(builds) - 1; i >= 0; i-- {
+		if !builds[i].Timestamp.IsZero() {
+			rows = append(rows, builds[i])
+		}
+	}
+	return rows
+}
+
+// flashSelected flashes the archived outputs of the selected build.
+func (p *ArtifactsPage) flashSelected() tea.Cmd {
+	if p.activeRequestID != "" {
+		p.message = "A flash is already running."
+		return nil
+	}
+	rows := p.buildRows()
+	if p.buildCursor >= len(rows) {
+		return nil
+	}
+	r := rows[p.buildCursor]
+	if r.ArchiveDir == "" {
+		p.message = "This build has no archived outputs to flash."
+		return nil
+	}
+
+	runner := ""
+	if p.cfg != nil {
+		runner = p.cfg.FlashRunner
+	}
+	args := west.ArchiveFlashArgs(r.ArchiveDir, runner, "")
+	p.flashBuild = &r
+	p.flashStart = time.Now()
+	p.message = ""
+	p.output.Reset()
+	p.output.WriteString("$ west " + strings.Join(args, " ") + "\n\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.seq++
+	p.activeRequestID = fmt.Sprintf("artifact-flash-%d", p.seq)
+	return west.WithRequestID(p.activeRequestID, p.runner.Run(ctx, "west", args...))
+}
+
+// reproduceSelected sets up the Project page to rebuild the selected
+// build, into a separate directory when separate is set, and shows it.
+func (p *ArtifactsPage) reproduceSelected(separate bool) tea.Cmd {
+	rows := p.buildRows()
+	if p.buildCursor >= len(rows) {
+		return nil
+	}
+	r := rows[p.buildCursor]
+	dir := r.BuildDir
+	if separate {
+		dir = reproduceDir(r)
+	}
+	return tea.Sequence(
+		func() tea.Msg { return reproduceBuildMsg{record: r, buildDir: dir} },
+		func() tea.Msg { return app.ShowPageMsg{Page: app.ProjectPage} },
+	)
+}
+
+// completeFlash records the flash of an archived build.
+func (p *ArtifactsPage) completeFlash(result west.CommandResultMsg) {
+	p.activeRequestID = ""
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+	if !result.Streamed {
+		p.output.WriteString(result.Output)
+	}
+	success := result.ExitCode == 0 && !result.Cancelled
+	status := "success"
+	if result.Cancelled {
+		status = "cancelled"
+	} else if !success {
+		status = fmt.Sprintf("failed (exit code: %d)", result.ExitCode)
+	}
+	p.output.WriteString(fmt.Sprintf("\nFlash %s in %s\n", status, result.Duration))
+	if p.flashBuild == nil {
+		return
+	}
+	if err := p.store.AddFlash(store.FlashRecord{
+		Board:     p.flashBuild.Board,
+		Timestamp: p.flashStart,
+		Success:   success,
+		Duration:  result.Duration.String(),
+		Cancelled: result.Cancelled,
+		BuildID:   p.flashBuild.ID,
+	}); err != nil {
+		p.message = fmt.Sprintf("History save failed: %v", err)
+	}
+	p.flashBuild = nil
+}
+
 func (p *ArtifactsPage) View() string {
 	var b strings.Builder
 
 	// Tab bar
 	for i, name := range tabNames {
 		if artifactTab(i) == p.activeTab {
-			b.WriteString(ui.BoldStyle.Render(" ["+name+"] "))
+			b.WriteString(ui.BoldStyle.Render(" [" + name + "] "))
 		} else {
-			b.WriteString(ui.DimStyle.Render("  "+name+"  "))
+			b.WriteString(ui.DimStyle.Render("  " + name + "  "))
 		}
 	}
 	b.WriteString("\n\n")
@@ -65,6 +233,9 @@ func (p *ArtifactsPage) View() string {
 	switch p.activeTab {
 	case tabBuilds:
 		p.renderBuilds(&b)
+		if p.showDetails {
+			p.renderBuildDetails(&b)
+		}
 	case tabFlashes:
 		p.renderFlashes(&b)
 	case tabTests:
@@ -73,6 +244,22 @@ func (p *ArtifactsPage) View() string {
 		p.renderSerialLogs(&b)
 	}
 
+	if p.message != "" {
+		b.WriteString("\n\n  " + p.message)
+	}
+	if p.output.Len() > 0 {
+		const outputHeight = 12
+		label := "Flash Output"
+		if p.activeRequestID != "" {
+			label = "Flashing..."
+		}
+		lines := strings.Split(strings.TrimRight(p.output.String(), "\n"), "\n")
+		if len(lines) > outputHeight-2 {
+			lines = lines[len(lines)-(outputHeight-2):]
+		}
+		b.WriteString("\n\n" + ui.Panel(label, strings.Join(lines, "\n"), p.width, outputHeight, false))
+	}
+
 	return b.String()
 }
 
@@ -82,18 +269,25 @@ func (p *ArtifactsPage) renderBuilds(b *strings.Builder) {
 		b.WriteString(fmt.Sprintf("Error: %v\n", err))
 		return
 	}
-	b.WriteString(ui.DimStyle.Render(fmt.Sprintf("  %-12s  %-30s  %-22s  %-12s  %-8s  %-6s",
-		"TIME", "BOARD", "GIT", "BUILD DIR", "SIZE", "STATUS")) + "\n")
-	b.WriteString(ui.DimStyle.Render("  "+strings.Repeat("─", 100)) + "\n")
+	trends := buildSizeTrends(builds)
+	b.WriteString(ui.DimStyle.Render(fmt.Sprintf("  %-12s  %-30s  %-22s  %-12s  %-18s  %-18s  %-10s  %-6s",
+		"TIME", "BOARD", "GIT", "BUILD DIR", "FLASH", "RAM", "TREND", "STATUS")) + "\n")
+	b.WriteString(ui.DimStyle.Render("  "+strings.Repeat("─", 144)) + "\n")
 	count := 0
 	for i := len(builds) - 1; i >= 0; i-- {
 		r := builds[i]
 		if r.Timestamp.IsZero() {
 			continue
 		}
+		prefix := "  "
+		if count == p.buildCursor {
+			prefix = ui.BoldStyle.Render("> ")
+		}
 		count++
 		status := ui.SuccessBadge("OK")
-		if !r.Success {
+		if r.Cancelled {
+			status = ui.WarningBadge("CANCELLED")
+		} else if !r.Success {
 			status = ui.ErrorBadge("FAIL")
 		}
 
@@ -110,20 +304,238 @@ func (p *ArtifactsPage) renderBuilds(b *strings.Builder) {
 			dirCol = r.BuildDir
 		}
 
-		sizeCol := "—"
-		if r.BinarySize > 0 {
-			sizeCol = formatBytes(r.BinarySize)
+		flashCol, ramCol := "—", "—"
+		t := trends[i]
+		switch {
+		case r.Sizes != nil:
+			flashCol = formatBytes(r.Sizes.Flash)
+			ramCol = formatBytes(r.Sizes.RAM)
+			if t.prev != nil {
+				flashCol += " " + formatSizeDelta(r.Sizes.Flash-t.prev.Flash)
+				ramCol += " " + formatSizeDelta(r.Sizes.RAM-t.prev.RAM)
+			}
+		case r.BinarySize > 0:
+			flashCol = formatBytes(r.BinarySize)
+		}
+
+		if r.Errors+r.Warnings > 0 {
+			status += " " + diagnosticSummary(r.Errors, r.Warnings)
+		}
+		if r.ArchiveDir != "" {
+			status += ui.DimStyle.Render(" archived")
 		}
 
-		b.WriteString(fmt.Sprintf("  %s  %-30s  %-22s  %-12s  %-8s  %s\n",
+		b.WriteString(prefix + fmt.Sprintf("%s  %-30s  %-22s  %-12s  %s  %s  %s  %s\n",
 			r.Timestamp.Format("Jan 02 15:04"),
-			r.Board, gitCol, dirCol, sizeCol, status))
+			r.Board, gitCol, dirCol,
+			padRight(flashCol, 18), padRight(ramCol, 18), padRight(sparkline(t.flash), 10), status))
+		for _, d := range r.Domains {
+			b.WriteString(ui.DimStyle.Render(fmt.Sprintf("  %12s  └ %-28s  %-9s  %s",
+				"", d.Name, domainSize(d), artifactNames(d.Artifacts))) + "\n")
+		}
 	}
 	if count == 0 {
 		b.WriteString(ui.DimStyle.Render("No build records yet."))
 	}
 }
 
+// renderBuildDetails shows the provenance and timing of the selected build
+// and what changed since the previous build of the same project and board.
+func (p *ArtifactsPage) renderBuildDetails(b *strings.Builder) {
+	builds, err := p.store.Builds()
+	if err != nil {
+		return
+	}
+	rows := p.buildRows()
+	if p.buildCursor >= len(rows) {
+		return
+	}
+	r := rows[p.buildCursor]
+
+	var d strings.Builder
+	if r.Provenance == nil {
+		d.WriteString(ui.DimStyle.Render("No provenance recorded for this build."))
+	} else {
+		pv := r.Provenance
+		field := func(label, value string) {
+			if value == "" {
+				value = "—"
+			}
+			d.WriteString(fmt.Sprintf("%-10s %s\n", label, value))
+		}
+		field("Zephyr", pv.ZephyrVersion)
+		field("Toolchain", pv.Toolchain)
+		field("SDK", pv.SDKVersion)
+		field("CMake", pv.CMakeVersion)
+		field("Ninja", pv.NinjaVersion)
+		field("Manifest", plural(len(pv.Manifest), "project"))
+
+		if prev := previousBuild(builds, r); prev == nil {
+			d.WriteString(ui.DimStyle.Render("\nNo earlier build of this project and board to compare with."))
+		} else if changes := buildChanges(*prev, r); len(changes) == 0 {
+			d.WriteString("\n" + ui.DimStyle.Render("Same as last build ("+prev.Timestamp.Format("Jan 02 15:04")+")"))
+		} else {
+			d.WriteString("\n" + ui.BoldStyle.Render("Differs from last build ("+prev.Timestamp.Format("Jan 02 15:04")+"):"))
+			for _, c := range changes {
+				d.WriteString("\n  " + c)
+			}
+		}
+	}
+	if t := r.Timing; t != nil {
+		d.WriteString(fmt.Sprintf("\n\n%-10s %s CPU over %s, %s wall", "Build time",
+			formatMillis(t.CPU), plural(t.Steps, "step"), formatMillis(t.Wall)))
+		for i, m := range t.Modules {
+			if i == 3 {
+				break
+			}
+			d.WriteString(fmt.Sprintf("\n  %8s  %s", formatMillis(m.Time), m.Name))
+		}
+		if len(t.Slowest) > 0 {
+			d.WriteString(fmt.Sprintf("\n%-10s %s (%s)", "Slowest", t.Slowest[0].Name, formatMillis(t.Slowest[0].Time)))
+		}
+		if prev := previousTimedBuild(builds, r); prev != nil {
+			if c := timingChange(prev.Timing, t); c != "" && t.CPU > prev.Timing.CPU {
+				d.WriteString("\n" + ui.WarningBadge("SLOWER") + " " + c)
+			} else if c != "" {
+				d.WriteString("\n" + ui.DimStyle.Render(c))
+			}
+		}
+	}
+	title := fmt.Sprintf("Build %s %s", r.Timestamp.Format("Jan 02 15:04"), r.Board)
+	b.WriteString("\n\n" + ui.Panel(title, strings.TrimRight(d.String(), "\n"), p.width, 0, false))
+}
+
+// previousBuild returns the last build with provenance of the same project
+// and board made before r.
+func previousBuild(builds []store.BuildRecord, r store.BuildRecord) *store.BuildRecord {
+	for i := len(builds) - 1; i >= 0; i-- {
+		prev := builds[i]
+		if prev.Provenance == nil || !prev.Timestamp.Before(r.Timestamp) ||
+			prev.App != r.App || prev.Board != r.Board {
+			continue
+		}
+		return &builds[i]
+	}
+	return nil
+}
+
+// buildChanges lists what differs between two builds: the application's
+// commit, then the provenance.
+func buildChanges(prev, cur store.BuildRecord) []string {
+	var changes []string
+	if prev.GitCommit != "" && cur.GitCommit != "" && prev.GitCommit != cur.GitCommit {
+		changes = append(changes, fmt.Sprintf("App %s → %s", prev.GitCommit, cur.GitCommit))
+	}
+	if !prev.GitDirty && cur.GitDirty {
+		changes = append(changes, "App has uncommitted changes")
+	}
+	if prev.Provenance != nil && cur.Provenance != nil {
+		changes = append(changes, west.DiffProvenance(west.Provenance(*prev.Provenance), west.Provenance(*cur.Provenance))...)
+	}
+	return changes
+}
+
+// domainSize renders the flash footprint of a sysbuild domain image.
+func domainSize(d store.DomainRecord) string {
+	switch {
+	case d.Sizes != nil:
+		return formatBytes(d.Sizes.Flash)
+	case d.BinarySize > 0:
+		return formatBytes(d.BinarySize)
+	}
+	return "—"
+}
+
+// artifactNames joins the file names of artifact paths.
+func artifactNames(paths []string) string {
+	names := make([]string, len(paths))
+	for i, a := range paths {
+		names[i] = filepath.Base(a)
+	}
+	return strings.Join(names, " ")
+}
+
+// sizeTrend is the size history of a build's project and board up to and
+// including that build.
+type sizeTrend struct {
+	prev  *store.SectionSizes // previous sized build of the same project/board
+	flash []int64
+}
+
+// sparkWidth is the number of builds a trend sparkline covers.
+const sparkWidth = 10
+
+// buildSizeTrends returns, for each record, the sizes of the previous build
+// of the same project and board and the recent flash totals. Records are
+// oldest first, as stored.
+func buildSizeTrends(builds []store.BuildRecord) []sizeTrend {
+	trends := make([]sizeTrend, len(builds))
+	last := make(map[string]*store.SectionSizes)
+	history := make(map[string][]int64)
+	for i, r := range builds {
+		if r.Sizes == nil {
+			continue
+		}
+		k := r.App + "\x00" + r.Board
+		h := append(history[k], r.Sizes.Flash)
+		if len(h) > sparkWidth {
+			h = h[len(h)-sparkWidth:]
+		}
+		history[k] = h
+		trends[i] = sizeTrend{prev: last[k], flash: append([]int64(nil), h...)}
+		last[k] = r.Sizes
+	}
+	return trends
+}
+
+// formatSizeDelta renders a signed size change; growth is highlighted.
+func formatSizeDelta(d int64) string {
+	switch {
+	case d > 0:
+		return ui.WarningBadge("+" + formatBytes(d))
+	case d < 0:
+		return ui.SuccessBadge("-" + formatBytes(-d))
+	default:
+		return ui.DimStyle.Render("±0")
+	}
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws values scaled between their minimum and maximum. A single
+// value or a flat series is drawn at the lowest level.
+func sparkline(values []int64) string {
+	if len(values) < 2 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		idx := 0
+		if hi > lo {
+			idx = int((v - lo) * int64(len(sparkBlocks)-1) / (hi - lo))
+		}
+		b.WriteRune(sparkBlocks[idx])
+	}
+	return ui.AccentStyle.Render(b.String())
+}
+
+// padRight pads s, which may contain ANSI styling, to width cells.
+func padRight(s string, width int) string {
+	if w := lipgloss.Width(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
 func formatBytes(n int64) string {
 	switch {
 	case n >= 1<<20:
@@ -152,7 +564,9 @@ func (p *ArtifactsPage) renderFlashes(b *strings.Builder) {
 		}
 		count++
 		status := ui.SuccessBadge("OK")
-		if !r.Success {
+		if r.Cancelled {
+			status = ui.WarningBadge("CANCELLED")
+		} else if !r.Success {
 			status = ui.ErrorBadge("FAIL")
 		}
 		b.WriteString(fmt.Sprintf("  %s  %-30s  %s  %s\n",
@@ -181,7 +595,9 @@ func (p *ArtifactsPage) renderTests(b *strings.Builder) {
 		}
 		count++
 		status := ui.SuccessBadge("PASS")
-		if !r.Success {
+		if r.Cancelled {
+			status = ui.WarningBadge("CANCELLED")
+		} else if !r.Success {
 			status = ui.ErrorBadge("FAIL")
 		}
 		b.WriteString(fmt.Sprintf("  %s  %-30s  %s  %s\n",
@@ -217,9 +633,22 @@ func (p *ArtifactsPage) renderSerialLogs(b *strings.Builder) {
 func (p *ArtifactsPage) Name() string { return "Artifacts" }
 
 func (p *ArtifactsPage) ShortHelp() []key.Binding {
-	return []key.Binding{
+	bindings := []key.Binding{
 		key.NewBinding(key.WithKeys("h/l"), key.WithHelp("h/l", "switch tab")),
 	}
+	if p.activeTab == tabBuilds {
+		bindings = append(bindings,
+			key.NewBinding(key.WithKeys("up", "down"), key.WithHelp("↑/↓", "select build")),
+			key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "flash this build")),
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "details")),
+			key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "reproduce")),
+			key.NewBinding(key.WithKeys("R"), key.WithHelp("R", "reproduce in new dir")),
+		)
+	}
+	if p.activeRequestID != "" {
+		bindings = append(bindings, key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "cancel")))
+	}
+	return bindings
 }
 
 func (p *ArtifactsPage) SetSize(w, h int) {
@@ -7,11 +7,13 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/buckleypaul/gust/internal/config"
 	"github.com/buckleypaul/gust/internal/store"
+	"github.com/buckleypaul/gust/internal/west"
 )
 
 func TestArtifactsTabSwitchRight(t *testing.T) {
-	p := NewArtifactsPage(store.New(t.TempDir()))
+	p := NewArtifactsPage(store.New(t.TempDir()), nil)
 
 	if p.activeTab != tabBuilds {
 		t.Fatalf("expected initial tab=tabBuilds(0), got %d", p.activeTab)
@@ -41,7 +43,7 @@ func TestArtifactsTabSwitchRight(t *testing.T) {
 }
 
 func TestArtifactsTabSwitchLeft(t *testing.T) {
-	p := NewArtifactsPage(store.New(t.TempDir()))
+	p := NewArtifactsPage(store.New(t.TempDir()), nil)
 
 	// Wrap at first tab
 	p.Update(tea.KeyMsg{Type: tea.KeyLeft})
@@ -67,7 +69,7 @@ func TestArtifactsBuildsTabRendersRecords(t *testing.T) {
 		t.Fatalf("AddBuild: %v", err)
 	}
 
-	p := NewArtifactsPage(st)
+	p := NewArtifactsPage(st, nil)
 	p.SetSize(120, 40)
 	output := p.View()
 
@@ -77,7 +79,7 @@ func TestArtifactsBuildsTabRendersRecords(t *testing.T) {
 }
 
 func TestArtifactsEmptyStore(t *testing.T) {
-	p := NewArtifactsPage(store.New(t.TempDir()))
+	p := NewArtifactsPage(store.New(t.TempDir()), nil)
 	p.SetSize(120, 40)
 
 	// Should not panic on any tab
@@ -86,3 +88,122 @@ func TestArtifactsEmptyStore(t *testing.T) {
 		p.Update(tea.KeyMsg{Type: tea.KeyRight})
 	}
 }
+
+func TestArtifactsBuildsTabShowsSizeDeltasPerProjectBoard(t *testing.T) {
+	st := store.New(t.TempDir())
+	now := time.Now()
+	records := []store.BuildRecord{
+		{Board: "nrf52840dk", App: "apps/blinky", Timestamp: now, Success: true,
+			Sizes: &store.SectionSizes{Flash: 20000, RAM: 8000}},
+		{Board: "native_sim", App: "apps/blinky", Timestamp: now, Success: true,
+			Sizes: &store.SectionSizes{Flash: 90000, RAM: 50000}},
+		{Board: "nrf52840dk", App: "apps/blinky", Timestamp: now, Success: true,
+			Sizes: &store.SectionSizes{Flash: 21024, RAM: 7000}},
+	}
+	for _, r := range records {
+		if err := st.AddBuild(r); err != nil {
+			t.Fatalf("AddBuild: %v", err)
+		}
+	}
+
+	trends := buildSizeTrends(records)
+	if trends[0].prev != nil || trends[1].prev != nil {
+		t.Fatal("expected no previous build for the first build of each board")
+	}
+	if trends[2].prev == nil || trends[2].prev.Flash != 20000 || len(trends[2].flash) != 2 {
+		t.Fatalf("expected nrf52840dk history only, got %+v", trends[2])
+	}
+
+	p := NewArtifactsPage(st, nil)
+	p.SetSize(200, 40)
+	output := p.View()
+	if !strings.Contains(output, "+1.0 KB") || !strings.Contains(output, "-1000 B") {
+		t.Fatalf("expected flash and RAM deltas in view, got:\n%s", output)
+	}
+	if !strings.Contains(output, "▁█") {
+		t.Fatalf("expected a rising sparkline, got:\n%s", output)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := sparkline([]int64{5}); got != "" {
+		t.Fatalf("expected no sparkline for one value, got %q", got)
+	}
+	if got := sparkline([]int64{0, 7, 14}); !strings.Contains(got, "▁▄█") {
+		t.Fatalf("expected scaled blocks, got %q", got)
+	}
+}
+
+func TestArtifactsFlashesArchivedBuild(t *testing.T) {
+	st := store.New(t.TempDir())
+	now := time.Now()
+	for _, r := range []store.BuildRecord{
+		{ID: "old", Board: "nrf52840dk", Timestamp: now, Success: true, ArchiveDir: ".gust/artifacts/old"},
+		{ID: "new", Board: "nrf52840dk", Timestamp: now, Success: true},
+	} {
+		if err := st.AddBuild(r); err != nil {
+			t.Fatalf("AddBuild: %v", err)
+		}
+	}
+	cfg := config.Defaults()
+	cfg.FlashRunner = "jlink"
+	fake := &fakeRunner{}
+	p := NewArtifactsPage(st, &cfg, fake)
+
+	// The newest build is selected first and has nothing archived.
+	if _, cmd := p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}}); cmd != nil || len(fake.runCalls) != 0 {
+		t.Fatal("expected no flash for a build without an archive")
+	}
+
+	p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	if cmd == nil || len(fake.runCalls) != 1 {
+		t.Fatal("expected flash of the archived build")
+	}
+	want := "flash -d .gust/artifacts/old --runner jlink --skip-rebuild"
+	if got := strings.Join(fake.runCalls[0].args, " "); got != want {
+		t.Fatalf("flash args = %q, want %q", got, want)
+	}
+
+	p.Update(west.CommandResultMsg{RequestID: p.activeRequestID, Duration: time.Second})
+	flashes, _ := st.Flashes()
+	if len(flashes) != 1 || flashes[0].BuildID != "old" || !flashes[0].Success {
+		t.Fatalf("expected a flash record for build old, got %+v", flashes)
+	}
+}
+
+func TestArtifactsBuildDetailsShowProvenanceDiff(t *testing.T) {
+	st := store.New(t.TempDir())
+	now := time.Now()
+	older := store.BuildRecord{
+		App: "app", Board: "qemu_x86", Timestamp: now.Add(-time.Hour), Success: true, GitCommit: "aaaa1111",
+		Provenance: &store.Provenance{ZephyrVersion: "3.6.0", SDKVersion: "0.16.5", Manifest: map[string]string{"hal_nordic": "1111"}},
+	}
+	newer := store.BuildRecord{
+		App: "app", Board: "qemu_x86", Timestamp: now, Success: true, GitCommit: "aaaa1111",
+		Provenance: &store.Provenance{ZephyrVersion: "3.7.0", SDKVersion: "0.16.5", Manifest: map[string]string{"hal_nordic": "2222"}},
+	}
+	for _, r := range []store.BuildRecord{older, newer} {
+		if err := st.AddBuild(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p := NewArtifactsPage(st, nil)
+	p.SetSize(160, 40)
+
+	p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	view := p.View()
+	for _, want := range []string{"0.16.5", "Differs from last build", "Zephyr 3.6.0 → 3.7.0", "hal_nordic 1111 → 2222"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected %q in details, got:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "App aaaa1111") {
+		t.Fatalf("expected unchanged app commit to be omitted, got:\n%s", view)
+	}
+
+	p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if view := p.View(); !strings.Contains(view, "No earlier build") {
+		t.Fatalf("expected oldest build to have nothing to compare, got:\n%s", view)
+	}
+}
@@ -1,10 +1,8 @@
 package pages
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -12,6 +10,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/buckleypaul/gust/internal/config"
 	"github.com/buckleypaul/gust/internal/store"
 	"github.com/buckleypaul/gust/internal/ui"
 	"github.com/buckleypaul/gust/internal/west"
@@ -45,6 +44,14 @@ const (
 type buildSection struct {
 	cmakeInput textinput.Model
 	pristine   bool
+	// pristineNext makes the next build pristine without changing the
+	// checkbox; ranPristine records whether the running build was.
+	pristineNext bool
+	ranPristine  bool
+	sysbuild     bool
+	profile      string // active build profile, recorded with each build
+	// Pickers for extra Kconfig fragments, overlays and snippets.
+	extras     [extraKindCount]multiSelect
 	state      buildState
 	buildStart time.Time
 	gitBranch  string
@@ -52,6 +59,11 @@ type buildSection struct {
 	gitDirty   bool
 	message    string
 	seq        int
+	diag       west.DiagnosticParser
+	progress   west.BuildProgress
+	// expected is how long the running build should take, judged from
+	// earlier builds of the same project and board. Zero when unknown.
+	expected time.Duration
 }
 
 func newBuildSection() buildSection {
@@ -67,8 +79,30 @@ func (b *buildSection) nextRequestID() string {
 	return fmt.Sprintf("build-%d", b.seq)
 }
 
+// applyProfile loads a profile's build options. An empty profile only
+// clears the profile name and keeps the current options.
+func (b *buildSection) applyProfile(p config.Profile) {
+	b.profile = p.Name
+	if p.Name == "" {
+		return
+	}
+	b.pristine = p.Pristine
+	b.sysbuild = p.Sysbuild
+	b.cmakeInput.SetValue(p.CMakeArgs)
+	b.extras[extraConf].setSelected(p.ExtraConf)
+	b.extras[extraOverlay].setSelected(p.ExtraOverlay)
+	b.extras[extraSnippet].setSelected(p.Snippets)
+}
+
+// setExtrasOptions offers the extras discovered in the project.
+func (b *buildSection) setExtrasOptions(found west.BuildExtras) {
+	b.extras[extraConf].setOptions(found.Conf)
+	b.extras[extraOverlay].setOptions(found.Overlays)
+	b.extras[extraSnippet].setOptions(found.Snippets)
+}
+
 // viewSection renders the Build section header and controls.
-func (b *buildSection) viewSection(width int, focusedPristine, focusedCMake bool) string {
+func (b *buildSection) viewSection(width int, focusedPristine, focusedSysbuild, focusedCMake bool, focusedExtra extraKind) string {
 	var sb strings.Builder
 	sectionLabel := lipgloss.NewStyle().Foreground(ui.Subtle).Bold(true)
 	separator := strings.Repeat("─", max(width-9, 10))
@@ -77,15 +111,22 @@ func (b *buildSection) viewSection(width int, focusedPristine, focusedCMake bool
 	focusedLabel := lipgloss.NewStyle().Foreground(ui.Primary).Bold(true)
 	normalLabel := lipgloss.NewStyle().Foreground(ui.Text)
 
-	check := "[ ]"
-	if b.pristine {
-		check = "[x]"
+	checkbox := func(label string, on, focused bool) {
+		check := "[ ]"
+		if on {
+			check = "[x]"
+		}
+		lbl := normalLabel.Render(fmt.Sprintf("%-9s", label))
+		if focused {
+			lbl = focusedLabel.Render(fmt.Sprintf("%-9s", label))
+		}
+		sb.WriteString("  " + lbl + " " + check + "\n")
 	}
-	pristineLbl := normalLabel.Render(fmt.Sprintf("%-9s", "Pristine"))
-	if focusedPristine {
-		pristineLbl = focusedLabel.Render(fmt.Sprintf("%-9s", "Pristine"))
+	checkbox("Pristine", b.pristine, focusedPristine)
+	checkbox("Sysbuild", b.sysbuild, focusedSysbuild)
+	for k := range b.extras {
+		sb.WriteString(b.extras[k].view(extraLabels[k], focusedExtra == extraKind(k), width))
 	}
-	sb.WriteString("  " + pristineLbl + " " + check + "\n")
 
 	inputWidth := width - labelWidth - 4
 	if inputWidth < 10 {
@@ -102,102 +143,213 @@ func (b *buildSection) viewSection(width int, focusedPristine, focusedCMake bool
 		sb.WriteString("  " + b.message + "\n")
 	}
 	if b.state == buildStateRunning {
-		sb.WriteString("  " + ui.DimStyle.Render("Building...") + "\n")
+		sb.WriteString("  " + b.viewProgress(width, time.Since(b.buildStart)) + "\n")
+	}
+	if errs, warns := b.diag.Counts(); errs+warns > 0 {
+		sb.WriteString("  " + diagnosticSummary(errs, warns) + ui.DimStyle.Render("  (ctrl+e: list)") + "\n")
+	}
+	return sb.String()
+}
+
+// viewProgress renders the phase, a bar of the ninja steps once they
+// stream, and the time elapsed and left.
+func (b *buildSection) viewProgress(width int, elapsed time.Duration) string {
+	p := b.progress
+	var sb strings.Builder
+	switch p.Phase {
+	case west.PhaseStarting:
+		sb.WriteString(ui.DimStyle.Render("Building..."))
+	case west.PhaseConfigure, west.PhaseGenerate:
+		sb.WriteString(ui.DimStyle.Render(string(p.Phase) + "..."))
+	default:
+		phase := string(p.Phase)
+		if p.Images > 1 {
+			phase += fmt.Sprintf(" (image %d)", p.Images)
+		}
+		barWidth := min(max(width-50, 10), 40)
+		filled := int(p.Fraction() * float64(barWidth))
+		bar := lipgloss.NewStyle().Foreground(ui.Primary).Render(strings.Repeat("█", filled)) +
+			ui.DimStyle.Render(strings.Repeat("░", barWidth-filled))
+		sb.WriteString(fmt.Sprintf("%-9s %s %d/%d", phase, bar, p.Done, p.Total))
 	}
+
+	timing := "  " + formatElapsed(elapsed)
+	if b.expected > 0 {
+		if left := b.expected - elapsed; left > 0 {
+			timing += ", ~" + formatElapsed(left) + " left"
+		} else {
+			timing += ", longer than usual"
+		}
+	}
+	sb.WriteString(ui.DimStyle.Render(timing))
 	return sb.String()
 }
 
+// formatElapsed rounds a duration to whole seconds for display.
+func formatElapsed(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
+
+// expectedBuildDuration averages the last few successful builds of app on
+// board, preferring those that were pristine (or not) like the next one.
+func expectedBuildDuration(s *store.Store, app, board string, pristine bool) time.Duration {
+	if s == nil {
+		return 0
+	}
+	builds, err := s.Builds()
+	if err != nil {
+		return 0
+	}
+	const samples = 5
+	var same, any []time.Duration
+	for i := len(builds) - 1; i >= 0 && len(same) < samples; i-- {
+		r := builds[i]
+		if !r.Success || r.Cancelled || r.App != app || r.Board != board {
+			continue
+		}
+		d, err := time.ParseDuration(r.Duration)
+		if err != nil {
+			continue
+		}
+		if r.Pristine == pristine {
+			same = append(same, d)
+		}
+		if len(any) < samples {
+			any = append(any, d)
+		}
+	}
+	if len(same) == 0 {
+		same = any
+	}
+	if len(same) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, d := range same {
+		total += d
+	}
+	return total / time.Duration(len(same))
+}
+
+// progressInterval is how often the elapsed time and ETA of a running build
+// are refreshed when it prints nothing. Tests shorten it.
+var progressInterval = time.Second
+
+// buildTickMsg refreshes the progress line of the build requestID.
+type buildTickMsg struct {
+	requestID string
+}
+
+func buildTick(requestID string) tea.Cmd {
+	return tea.Tick(progressInterval, func(time.Time) tea.Msg {
+		return buildTickMsg{requestID: requestID}
+	})
+}
+
 // start launches west build, writes the command header to out, and returns
-// the request ID and the tea.Cmd to execute.
-func (b *buildSection) start(wsRoot, project, board, shield, buildDir string, runner west.Runner, out *strings.Builder) (requestID string, cmd tea.Cmd) {
+// the request ID and the tea.Cmd to execute. Cancelling ctx stops the build.
+func (b *buildSection) start(ctx context.Context, wsRoot, project, board, shield, buildDir string, runner west.Runner, out *strings.Builder) (requestID string, cmd tea.Cmd) {
 	b.state = buildStateRunning
 	b.buildStart = time.Now()
 	b.message = ""
+	b.diag.Reset()
+	b.progress.Reset()
 	requestID = b.nextRequestID()
+	b.ranPristine = b.pristine || b.pristineNext
+	b.pristineNext = false
 
-	if !filepath.IsAbs(project) {
-		project = filepath.Join(wsRoot, project)
-	}
+	project = west.ProjectPath(wsRoot, project)
 
-	b.gitBranch, b.gitCommit, b.gitDirty = "", "", false
 	gitDir := project
 	if gitDir == "" {
 		gitDir = wsRoot
 	}
-	if o, err := gitCmd(gitDir, "branch", "--show-current"); err == nil {
-		b.gitBranch = strings.TrimSpace(o)
-	}
-	if o, err := gitCmd(gitDir, "rev-parse", "--short=8", "HEAD"); err == nil {
-		b.gitCommit = strings.TrimSpace(o)
-	}
-	if o, err := gitCmd(gitDir, "status", "--porcelain"); err == nil {
-		b.gitDirty = strings.TrimSpace(o) != ""
-	}
+	git := west.ReadGitState(gitDir)
+	b.gitBranch, b.gitCommit, b.gitDirty = git.Branch, git.Commit, git.Dirty
 
-	args := []string{"build", "-b", board}
-	if buildDir != "" {
-		args = append(args, "-d", buildDir)
-	}
-	if b.pristine {
-		args = append(args, "-p", "always")
-	}
-	if shield != "" {
-		args = append(args, "--shield", shield)
-	}
-	if cmake := b.cmakeInput.Value(); cmake != "" {
-		args = append(args, "--")
-		args = append(args, strings.Fields(cmake)...)
-	}
-	args = append(args, project)
+	args := west.BuildArgs(west.BuildOptions{
+		Board:        board,
+		Shield:       shield,
+		BuildDir:     buildDir,
+		Project:      project,
+		Pristine:     b.ranPristine,
+		Sysbuild:     b.sysbuild,
+		CMakeArgs:    b.cmakeInput.Value(),
+		ExtraConf:    b.extras[extraConf].selected,
+		ExtraOverlay: b.extras[extraOverlay].selected,
+		Snippets:     b.extras[extraSnippet].selected,
+	})
 
 	out.WriteString("$ west " + strings.Join(args, " ") + "\n\n")
-	return requestID, west.WithRequestID(requestID, runner.Run("west", args...))
+	return requestID, west.WithRequestID(requestID, runner.Run(ctx, "west", args...))
 }
 
-// complete finalises build state and records to store.
-func (b *buildSection) complete(result west.CommandResultMsg, board, app, shield, buildDir string, s *store.Store, wsRoot string, out *strings.Builder) {
+// complete finalises build state and records to store. It returns the ID
+// of the record saved for a successful build, for details gathered later.
+func (b *buildSection) complete(result west.CommandResultMsg, board, app, shield, buildDir string, s *store.Store, wsRoot string, out *strings.Builder) (recordID string) {
 	b.state = buildStateDone
-	success := result.ExitCode == 0
-	out.WriteString(result.Output)
+	success := result.ExitCode == 0 && !result.Cancelled
+	if !result.Streamed {
+		out.WriteString(result.Output)
+		for _, line := range strings.Split(result.Output, "\n") {
+			b.diag.Feed(line)
+		}
+	}
 	status := "success"
-	if !success {
+	if result.Cancelled {
+		status = "cancelled"
+	} else if !success {
 		status = fmt.Sprintf("failed (exit code: %d)", result.ExitCode)
 	}
+	errs, warns := b.diag.Counts()
+	if errs+warns > 0 {
+		status += fmt.Sprintf(" with %s, %s", plural(errs, "error"), plural(warns, "warning"))
+	}
 	out.WriteString(fmt.Sprintf("\nBuild %s in %s\n", status, result.Duration))
 
-	var binarySize int64
-	if success {
-		dir := buildDir
-		if dir == "" {
-			dir = "build"
-		}
-		if fi, err := os.Stat(filepath.Join(wsRoot, dir, "zephyr", "zephyr.bin")); err == nil {
-			binarySize = fi.Size()
-		}
+	record := west.NewBuildRecord(west.BuildOptions{
+		Board:        board,
+		Shield:       shield,
+		BuildDir:     buildDir,
+		Pristine:     b.ranPristine,
+		Sysbuild:     b.sysbuild,
+		CMakeArgs:    b.cmakeInput.Value(),
+		ExtraConf:    b.extras[extraConf].selected,
+		ExtraOverlay: b.extras[extraOverlay].selected,
+		Snippets:     b.extras[extraSnippet].selected,
+	}, app, b.profile, west.GitState{Branch: b.gitBranch, Commit: b.gitCommit, Dirty: b.gitDirty}, b.buildStart, result, &b.diag)
+	archiveErr := west.CompleteBuildRecord(&record, s, wsRoot)
+	writeBuildTiming(out, record.Timing)
+	if archiveErr != nil {
+		out.WriteString(fmt.Sprintf("Archiving outputs failed: %v\n", archiveErr))
 	}
+
 	if s != nil {
-		_ = s.AddBuild(store.BuildRecord{
-			Board:      board,
-			App:        app,
-			Timestamp:  b.buildStart,
-			Success:    success,
-			Duration:   result.Duration.String(),
-			Shield:     shield,
-			Pristine:   b.pristine,
-			CMakeArgs:  b.cmakeInput.Value(),
-			GitBranch:  b.gitBranch,
-			GitCommit:  b.gitCommit,
-			GitDirty:   b.gitDirty,
-			BuildDir:   buildDir,
-			BinarySize: binarySize,
-		})
+		if err := s.AddBuild(record); err == nil && success {
+			recordID = record.ID
+		}
 	}
+	return recordID
+}
+
+// provenanceRecordedMsg reports that a build's provenance was added to its
+// history record.
+type provenanceRecordedMsg struct {
+	id  string
+	err error
 }
 
-// gitCmd runs a git subcommand in dir and returns stdout.
-func gitCmd(dir string, args ...string) (string, error) {
-	cmd := exec.Command("git", args...)
-	cmd.Dir = dir
-	out, err := cmd.Output()
-	return string(out), err
+// recordProvenance adds what the build recorded as id was made with to its
+// record. The build's tools run through runner, in the container for
+// container builds, so this is a command rather than part of Update.
+func recordProvenance(s *store.Store, runner west.Runner, cfg *config.Config, wsRoot, id, buildDir string) tea.Cmd {
+	if s == nil || id == "" {
+		return nil
+	}
+	mount := containerMount(cfg)
+	return func() tea.Msg {
+		p := west.CollectProvenance(context.Background(), runner, wsRoot, buildDir, mount)
+		err := s.UpdateBuild(id, func(r *store.BuildRecord) { r.Provenance = (*store.Provenance)(&p) })
+		return provenanceRecordedMsg{id: id, err: err}
+	}
 }
@@ -1,6 +1,9 @@
 package pages
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -15,7 +18,7 @@ func TestBuildSectionStartPassesBuildDir(t *testing.T) {
 	fake := &fakeRunner{nextMsg: west.CommandResultMsg{Output: "ok", ExitCode: 0, Duration: time.Second}}
 
 	b := newBuildSection()
-	requestID, cmd := b.start(wsRoot, ".", "nrf52840dk", "", "build-custom", fake, &out)
+	requestID, cmd := b.start(context.Background(), wsRoot, ".", "nrf52840dk", "", "build-custom", fake, &out)
 	if requestID == "" {
 		t.Fatal("expected non-empty requestID")
 	}
@@ -45,7 +48,7 @@ func TestBuildSectionStartOmitsBuildDirWhenEmpty(t *testing.T) {
 	fake := &fakeRunner{nextMsg: west.CommandResultMsg{Output: "ok", ExitCode: 0, Duration: time.Second}}
 
 	b := newBuildSection()
-	_, cmd := b.start(t.TempDir(), ".", "nrf52840dk", "", "", fake, &out)
+	_, cmd := b.start(context.Background(), t.TempDir(), ".", "nrf52840dk", "", "", fake, &out)
 	_ = cmd()
 
 	for _, a := range fake.runCalls[0].args {
@@ -82,3 +85,61 @@ func TestBuildSectionCompleteRecordsToStore(t *testing.T) {
 		t.Fatalf("expected BuildDir build-custom, got %q", builds[0].BuildDir)
 	}
 }
+
+func TestBuildSectionSysbuildRecordsDomains(t *testing.T) {
+	wsRoot := t.TempDir()
+	st := store.New(t.TempDir())
+	buildDir := filepath.Join(wsRoot, "build")
+	for _, domain := range []string{"app", "mcuboot"} {
+		dir := filepath.Join(buildDir, domain, "zephyr")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "zephyr.bin"), make([]byte, 100), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	domains := "default: app\ndomains:\n- name: app\n  build_dir: /x/build/app\n- name: mcuboot\n  build_dir: /x/build/mcuboot\n"
+	if err := os.WriteFile(filepath.Join(buildDir, "domains.yaml"), []byte(domains), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	b := newBuildSection()
+	b.sysbuild = true
+	var out strings.Builder
+	fake := &fakeRunner{nextMsg: west.CommandResultMsg{ExitCode: 0}}
+	_, cmd := b.start(context.Background(), wsRoot, "app", "nrf5340dk/nrf5340/cpuapp", "", "build", fake, &out)
+	_ = cmd()
+	if !strings.Contains(strings.Join(fake.runCalls[0].args, " "), "--sysbuild") {
+		t.Fatalf("expected --sysbuild in args, got %v", fake.runCalls[0].args)
+	}
+
+	b.complete(west.CommandResultMsg{ExitCode: 0, Duration: time.Second}, "nrf5340dk/nrf5340/cpuapp", "app", "", "build", st, wsRoot, &out)
+	builds, _ := st.Builds()
+	r := builds[0]
+	if !r.Sysbuild || len(r.Domains) != 2 || r.Domains[0].Name != "app" || r.Domains[1].Name != "mcuboot" {
+		t.Fatalf("expected app and mcuboot domains, got %+v", r.Domains)
+	}
+	if r.BinarySize != 100 || r.Domains[1].BuildDir != filepath.Join("build", "mcuboot") {
+		t.Fatalf("expected default-domain size and domain build dirs, got %+v", r)
+	}
+
+	var f flashSection
+	f.refreshLastBuild(st, "build")
+	f.cycleDomain()
+	if f.targetDomain() != "app" {
+		t.Fatalf("expected first cycle to pick app, got %q", f.targetDomain())
+	}
+	f.cycleDomain()
+	f.cycleDomain()
+	if f.targetDomain() != "" {
+		t.Fatalf("expected cycling past the last domain to flash all, got %q", f.targetDomain())
+	}
+	f.cycleDomain()
+	fake = &fakeRunner{nextMsg: west.CommandResultMsg{ExitCode: 0}}
+	_, cmd = f.start(context.Background(), "build", "", fake, &out)
+	_ = cmd()
+	if got := strings.Join(fake.runCalls[0].args, " "); got != "flash -d build --domain app" {
+		t.Fatalf("unexpected flash args %q", got)
+	}
+}
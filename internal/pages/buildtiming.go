@@ -0,0 +1,98 @@
+package pages
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/buckleypaul/gust/internal/store"
+)
+
+// timingShown is how many steps and modules the build output lists.
+const timingShown = 5
+
+// writeBuildTiming appends the slowest steps and modules of a build to its
+// output.
+func writeBuildTiming(out *strings.Builder, t *store.BuildTiming) {
+	if t == nil {
+		return
+	}
+	out.WriteString(fmt.Sprintf("\nBuild time: %s CPU over %s, %s wall\n",
+		formatMillis(t.CPU), plural(t.Steps, "step"), formatMillis(t.Wall)))
+	if len(t.Slowest) > 0 {
+		out.WriteString("Slowest steps:\n")
+		for i, s := range t.Slowest {
+			if i == timingShown {
+				break
+			}
+			kind := "compile"
+			if s.Link {
+				kind = "link"
+			}
+			out.WriteString(fmt.Sprintf("  %8s  %-7s  %s\n", formatMillis(s.Time), kind, s.Name))
+		}
+	}
+	out.WriteString("By module:\n")
+	for i, m := range t.Modules {
+		if i == timingShown {
+			break
+		}
+		out.WriteString(fmt.Sprintf("  %8s  %-24s  %s\n", formatMillis(m.Time), m.Name, plural(m.Steps, "step")))
+	}
+}
+
+// timingChange describes how much more or less CPU time cur took than
+// prev, naming the module that changed most, or "" when the change is small.
+func timingChange(prev, cur *store.BuildTiming) string {
+	if prev == nil || cur == nil || prev.CPU == 0 {
+		return ""
+	}
+	delta := cur.CPU - prev.CPU
+	pct := float64(delta) * 100 / float64(prev.CPU)
+	if pct > -10 && pct < 10 {
+		return ""
+	}
+	change := fmt.Sprintf("CPU time %s → %s (%+.0f%%)", formatMillis(prev.CPU), formatMillis(cur.CPU), pct)
+
+	before := map[string]int64{}
+	for _, m := range prev.Modules {
+		before[m.Name] = m.Time
+	}
+	var worst string
+	var worstDelta, worstAbs int64
+	for _, m := range cur.Modules {
+		d := m.Time - before[m.Name]
+		abs := d
+		if abs < 0 {
+			abs = -abs
+		}
+		if abs > worstAbs {
+			worst, worstDelta, worstAbs = m.Name, d, abs
+		}
+	}
+	if worst != "" {
+		sign := "+"
+		if worstDelta < 0 {
+			sign = "-"
+		}
+		change += fmt.Sprintf(", mostly %s (%s%s)", worst, sign, formatMillis(worstAbs))
+	}
+	return change
+}
+
+// previousTimedBuild returns the last build with timing of the same
+// project and board made before r.
+func previousTimedBuild(builds []store.BuildRecord, r store.BuildRecord) *store.BuildRecord {
+	for i := len(builds) - 1; i >= 0; i-- {
+		prev := builds[i]
+		if prev.Timing != nil && prev.Timestamp.Before(r.Timestamp) && prev.App == r.App && prev.Board == r.Board {
+			return &builds[i]
+		}
+	}
+	return nil
+}
+
+// formatMillis renders a step time such as 12.3s.
+func formatMillis(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).Round(100 * time.Millisecond).String()
+}
@@ -0,0 +1,93 @@
+package pages
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/buckleypaul/gust/internal/store"
+	"github.com/buckleypaul/gust/internal/west"
+)
+
+func TestBuildCompleteReportsAndRecordsTiming(t *testing.T) {
+	wsRoot := t.TempDir()
+	st := store.New(filepath.Join(wsRoot, ".gust"))
+	buildDir := filepath.Join(wsRoot, "build")
+	if err := os.MkdirAll(buildDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	b := newBuildSection()
+	var out strings.Builder
+	fake := &fakeRunner{nextMsg: west.CommandResultMsg{ExitCode: 0}}
+	_, cmd := b.start(context.Background(), wsRoot, "app", "qemu_x86", "", "build", fake, &out)
+	_ = cmd()
+
+	log := "# ninja log v5\n" +
+		"0\t1500\t0\tCMakeFiles/app.dir/src/main.c.obj\taa\n" +
+		"0\t4000\t0\tzephyr/kernel/CMakeFiles/kernel.dir/sched.c.obj\tbb\n" +
+		"4000\t6500\t0\tzephyr/zephyr.elf\tcc\n"
+	if err := os.WriteFile(filepath.Join(buildDir, ".ninja_log"), []byte(log), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	b.complete(west.CommandResultMsg{ExitCode: 0, Duration: 7 * time.Second}, "qemu_x86", "app", "", "build", st, wsRoot, &out)
+	for _, want := range []string{"Build time: 8s CPU over 3 steps, 6.5s wall", "2.5s  link     zephyr/zephyr.elf", "4s  zephyr/kernel"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, out.String())
+		}
+	}
+
+	builds, _ := st.Builds()
+	timing := builds[0].Timing
+	if timing == nil || timing.Steps != 3 || timing.CPU != 8000 || timing.Slowest[0].Name != "zephyr/kernel/CMakeFiles/kernel.dir/sched.c.obj" {
+		t.Fatalf("recorded timing = %+v", timing)
+	}
+}
+
+func TestArtifactsBuildDetailsShowTimingRegression(t *testing.T) {
+	st := store.New(t.TempDir())
+	now := time.Now()
+	older := store.BuildRecord{
+		App: "app", Board: "qemu_x86", Timestamp: now.Add(-time.Hour), Success: true,
+		Timing: &store.BuildTiming{Steps: 100, CPU: 60000, Wall: 20000, Modules: []store.StepTiming{
+			{Name: "zephyr/kernel", Time: 30000}, {Name: "app", Time: 10000},
+		}},
+	}
+	newer := store.BuildRecord{
+		App: "app", Board: "qemu_x86", Timestamp: now, Success: true,
+		Timing: &store.BuildTiming{Steps: 110, CPU: 90000, Wall: 30000, Modules: []store.StepTiming{
+			{Name: "zephyr/kernel", Time: 32000}, {Name: "app", Time: 38000},
+		}, Slowest: []store.StepTiming{{Name: "CMakeFiles/app.dir/src/big.c.obj", Time: 25000}}},
+	}
+	for _, r := range []store.BuildRecord{older, newer} {
+		if err := st.AddBuild(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p := NewArtifactsPage(st, nil)
+	p.SetSize(160, 40)
+
+	p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	view := p.View()
+	for _, want := range []string{"1m30s CPU over 110 steps", "src/big.c.obj (25s)", "CPU time 1m0s → 1m30s (+50%), mostly app (+28s)"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected %q in details, got:\n%s", want, view)
+		}
+	}
+}
+
+func TestTimingChangeIgnoresSmallDifferences(t *testing.T) {
+	prev := &store.BuildTiming{CPU: 60000}
+	if c := timingChange(prev, &store.BuildTiming{CPU: 63000}); c != "" {
+		t.Fatalf("expected a 5%% change to be ignored, got %q", c)
+	}
+	if c := timingChange(prev, &store.BuildTiming{CPU: 30000}); !strings.Contains(c, "-50%") {
+		t.Fatalf("expected a faster build to be reported, got %q", c)
+	}
+}
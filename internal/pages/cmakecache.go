@@ -0,0 +1,397 @@
+package pages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/reflow/truncate"
+
+	"github.com/buckleypaul/gust/internal/app"
+	"github.com/buckleypaul/gust/internal/config"
+	"github.com/buckleypaul/gust/internal/ui"
+	"github.com/buckleypaul/gust/internal/west"
+)
+
+// cmakeCacheLoadedMsg carries the CMakeCache.txt entries of a build dir.
+type cmakeCacheLoadedMsg struct {
+	buildDir string
+	entries  []west.CacheEntry
+	err      error
+}
+
+// CMakeCachePage lists the variables in the build directory's
+// CMakeCache.txt and reconfigures the build when one is edited.
+type CMakeCachePage struct {
+	cfg             *config.Config
+	wsRoot          string
+	runner          west.Runner
+	buildDir        string // Build Dir setting, possibly a template
+	project         string
+	board           string
+	profile         string
+	entries         []west.CacheEntry
+	err             error
+	showInternal    bool
+	cursor          int
+	search          textinput.Model
+	searching       bool
+	edit            textinput.Model
+	editing         string // name of the variable being edited
+	running         bool
+	requestSeq      int
+	activeRequestID string
+	cancel          context.CancelFunc
+	width, height   int
+	message         string
+}
+
+func NewCMakeCachePage(cfg *config.Config, wsRoot string, runners ...west.Runner) *CMakeCachePage {
+	runner := west.RealRunner()
+	if len(runners) > 0 && runners[0] != nil {
+		runner = runners[0]
+	}
+	search := textinput.New()
+	search.Placeholder = "variable, value or help"
+	search.Prompt = "/ "
+	search.CharLimit = 128
+	edit := textinput.New()
+	edit.Prompt = ""
+	edit.CharLimit = 1024
+	return &CMakeCachePage{
+		cfg:      cfg,
+		wsRoot:   wsRoot,
+		runner:   runner,
+		buildDir: cfg.BuildDir,
+		project:  cfg.LastProject,
+		board:    cfg.DefaultBoard,
+		profile:  cfg.ActiveProfile,
+		search:   search,
+		edit:     edit,
+	}
+}
+
+func (p *CMakeCachePage) Init() tea.Cmd {
+	return p.load()
+}
+
+// dir returns the build directory whose cache is shown.
+func (p *CMakeCachePage) dir() string {
+	return west.ExpandBuildDir(p.buildDir, p.project, p.board, p.profile)
+}
+
+func (p *CMakeCachePage) load() tea.Cmd {
+	wsRoot, dir := p.wsRoot, p.dir()
+	return func() tea.Msg {
+		entries, err := west.ReadCMakeCache(west.CMakeCachePath(wsRoot, dir))
+		return cmakeCacheLoadedMsg{buildDir: dir, entries: entries, err: err}
+	}
+}
+
+// retarget applies a selection change and reloads if it moved the build dir.
+func (p *CMakeCachePage) retarget(change func()) tea.Cmd {
+	before := p.dir()
+	change()
+	if p.dir() == before {
+		return nil
+	}
+	p.entries, p.err, p.cursor = nil, nil, 0
+	return p.load()
+}
+
+func (p *CMakeCachePage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case app.BuildDirChangedMsg:
+		return p, p.retarget(func() { p.buildDir = msg.Dir })
+
+	case app.ProjectSelectedMsg:
+		return p, p.retarget(func() { p.project = msg.Path })
+
+	case app.BoardSelectedMsg:
+		return p, p.retarget(func() { p.board = msg.Board })
+
+	case app.ProfileSelectedMsg:
+		return p, p.retarget(func() { p.profile = msg.Profile.Name })
+
+	case cmakeCacheLoadedMsg:
+		if msg.buildDir != p.dir() {
+			return p, nil
+		}
+		p.entries, p.err = msg.entries, msg.err
+		p.clampCursor()
+		return p, nil
+
+	case west.CommandOutputMsg:
+		if !p.running || msg.RequestID != p.activeRequestID {
+			return p, nil
+		}
+		if line := strings.TrimSpace(msg.Line); line != "" {
+			p.message = line
+		}
+		return p, msg.Next
+
+	case west.CommandResultMsg:
+		if !p.running || msg.RequestID != p.activeRequestID {
+			return p, nil
+		}
+		p.running = false
+		p.activeRequestID = ""
+		if p.cancel != nil {
+			p.cancel()
+			p.cancel = nil
+		}
+		switch {
+		case msg.Cancelled:
+			p.message = "Configuration cancelled"
+		case msg.ExitCode != 0:
+			p.message = fmt.Sprintf("Configuration failed (exit code: %d)", msg.ExitCode)
+		default:
+			p.message = fmt.Sprintf("Reconfigured in %s", msg.Duration)
+		}
+		return p, p.load()
+
+	case tea.KeyMsg:
+		if p.searching {
+			return p.handleSearchKey(msg)
+		}
+		if p.editing != "" {
+			return p.handleEditKey(msg)
+		}
+		if p.running {
+			if msg.String() == "ctrl+x" && p.cancel != nil {
+				p.cancel()
+				p.message = "Cancelling configuration..."
+			}
+			return p, nil
+		}
+
+		rows := p.rows()
+		switch msg.String() {
+		case "up":
+			if p.cursor > 0 {
+				p.cursor--
+			}
+		case "down":
+			if p.cursor < len(rows)-1 {
+				p.cursor++
+			}
+		case "enter", "e":
+			if p.cursor < len(rows) {
+				p.editing = rows[p.cursor].Name
+				p.edit.SetValue(rows[p.cursor].Value)
+				p.edit.CursorEnd()
+				return p, p.edit.Focus()
+			}
+		case " ":
+			// BOOL entries toggle in place.
+			if p.cursor < len(rows) && rows[p.cursor].Type == "BOOL" {
+				return p, p.set(rows[p.cursor].Name, toggleCMakeBool(rows[p.cursor].Value))
+			}
+		case "/":
+			p.searching = true
+			p.search.Focus()
+			return p, textinput.Blink
+		case "esc":
+			p.search.SetValue("")
+			p.clampCursor()
+		case "i":
+			p.showInternal = !p.showInternal
+			p.clampCursor()
+		case "r":
+			return p, p.load()
+		}
+	}
+	return p, nil
+}
+
+func (p *CMakeCachePage) handleSearchKey(msg tea.KeyMsg) (app.Page, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		p.searching = false
+		p.search.Blur()
+		return p, nil
+	case "esc":
+		p.searching = false
+		p.search.Blur()
+		p.search.SetValue("")
+		p.clampCursor()
+		return p, nil
+	}
+	var cmd tea.Cmd
+	p.search, cmd = p.search.Update(msg)
+	p.cursor = 0
+	return p, cmd
+}
+
+func (p *CMakeCachePage) handleEditKey(msg tea.KeyMsg) (app.Page, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		name := p.editing
+		p.editing = ""
+		p.edit.Blur()
+		return p, p.set(name, p.edit.Value())
+	case "esc":
+		p.editing = ""
+		p.edit.Blur()
+		return p, nil
+	}
+	var cmd tea.Cmd
+	p.edit, cmd = p.edit.Update(msg)
+	return p, cmd
+}
+
+// set reconfigures the build dir with name=value.
+func (p *CMakeCachePage) set(name, value string) tea.Cmd {
+	p.running = true
+	p.requestSeq++
+	p.activeRequestID = fmt.Sprintf("cmake-cache-%d", p.requestSeq)
+	args := west.CMakeCacheSetArgs(p.dir(), name, value)
+	p.message = "$ west " + strings.Join(args, " ")
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	return west.WithRequestID(p.activeRequestID, p.runner.Run(ctx, "west", args...))
+}
+
+// toggleCMakeBool flips a CMake boolean, keeping the ON/OFF spelling.
+func toggleCMakeBool(v string) string {
+	switch strings.ToUpper(v) {
+	case "ON", "1", "YES", "TRUE", "Y":
+		return "OFF"
+	}
+	return "ON"
+}
+
+func (p *CMakeCachePage) clampCursor() {
+	if n := len(p.rows()); p.cursor >= n {
+		p.cursor = max(n-1, 0)
+	}
+}
+
+// rows returns the entries shown: INTERNAL and STATIC ones only on request,
+// narrowed by the search.
+func (p *CMakeCachePage) rows() []west.CacheEntry {
+	query := strings.ToLower(strings.TrimSpace(p.search.Value()))
+	var rows []west.CacheEntry
+	for _, e := range p.entries {
+		if !p.showInternal && (e.Type == "INTERNAL" || e.Type == "STATIC") {
+			continue
+		}
+		if query != "" &&
+			!strings.Contains(strings.ToLower(e.Name), query) &&
+			!strings.Contains(strings.ToLower(e.Value), query) &&
+			!strings.Contains(strings.ToLower(e.Help), query) {
+			continue
+		}
+		rows = append(rows, e)
+	}
+	return rows
+}
+
+func (p *CMakeCachePage) View() string {
+	var hdr strings.Builder
+	dir := p.dir()
+	if dir == "" {
+		dir = config.DefaultBuildDir
+	}
+	rows := p.rows()
+	hdr.WriteString(ui.BoldStyle.Render(" CMakeCache.txt") + ui.DimStyle.Render("  "+dir))
+	if p.entries != nil {
+		hdr.WriteString(fmt.Sprintf("  %d/%d variables", len(rows), len(p.entries)))
+	}
+	if p.showInternal {
+		hdr.WriteString(ui.DimStyle.Render("  (showing internal)"))
+	}
+	hdr.WriteString("\n")
+	if p.searching || p.search.Value() != "" {
+		hdr.WriteString(p.search.View() + "\n")
+	}
+	if p.message != "" {
+		hdr.WriteString("  " + p.message + "\n")
+	}
+
+	var b strings.Builder
+	b.WriteString(hdr.String() + "\n")
+	if p.err != nil {
+		b.WriteString(ui.DimStyle.Render(fmt.Sprintf("  No CMakeCache.txt in %s. Build once to configure it.", dir)))
+		return b.String()
+	}
+	if len(rows) == 0 {
+		b.WriteString(ui.DimStyle.Render("  No matching variables."))
+		return b.String()
+	}
+
+	footer := ""
+	if p.cursor < len(rows) {
+		if p.editing != "" {
+			footer = "\n  " + ui.DimStyle.Render("Set "+p.editing+": ") + p.edit.View()
+		} else if help := rows[p.cursor].Help; help != "" {
+			footer = "\n  " + ui.DimStyle.Render(truncate.StringWithTail(help, uint(max(p.width-4, 10)), "…"))
+		}
+	}
+	height := max(p.height-strings.Count(hdr.String(), "\n")-strings.Count(footer, "\n")-3, 1)
+	b.WriteString(renderCacheRows(rows, p.cursor, p.width, height))
+	b.WriteString(footer)
+	return b.String()
+}
+
+// renderCacheRows renders a scrolling window of entries around cursor.
+func renderCacheRows(rows []west.CacheEntry, cursor, width, height int) string {
+	start, end := scrollWindow(cursor, len(rows), height)
+	nameWidth := 40
+	valueWidth := max(width-nameWidth-16, 10)
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		e := rows[i]
+		prefix := "  "
+		if i == cursor {
+			prefix = ui.BoldStyle.Render("> ")
+		}
+		name := truncate.StringWithTail(e.Name, uint(nameWidth), "…")
+		value := truncate.StringWithTail(e.Value, uint(valueWidth), "…")
+		b.WriteString(fmt.Sprintf("%s%-*s %s %s\n", prefix, nameWidth, name, ui.DimStyle.Render(fmt.Sprintf("%-8s", e.Type)), value))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (p *CMakeCachePage) Name() string { return "CMake Cache" }
+
+func (p *CMakeCachePage) ShortHelp() []key.Binding {
+	if p.searching {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "done")),
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "clear")),
+		}
+	}
+	if p.editing != "" {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "reconfigure")),
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
+		}
+	}
+	if p.running {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "cancel")),
+		}
+	}
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "edit")),
+		key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "toggle bool")),
+		key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
+		key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "internal")),
+		key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "reload")),
+	}
+}
+
+func (p *CMakeCachePage) InputCaptured() bool {
+	return p.searching || p.editing != ""
+}
+
+func (p *CMakeCachePage) SetSize(w, h int) {
+	p.width = w
+	p.height = h
+	p.search.Width = max(w-6, 10)
+	p.edit.Width = max(w-30, 10)
+}
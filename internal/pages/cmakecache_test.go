@@ -0,0 +1,105 @@
+package pages
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/buckleypaul/gust/internal/config"
+	"github.com/buckleypaul/gust/internal/west"
+)
+
+const testCMakeCache = `# This is the CMakeCache file.
+
+//Board to build for
+BOARD:STRING=nrf52840dk/nrf52840
+
+//Enable verbose output
+CMAKE_VERBOSE_MAKEFILE:BOOL=OFF
+
+//Path to the toolchain
+ZEPHYR_SDK_INSTALL_DIR:PATH=/opt/zephyr-sdk
+
+//INTERNAL cache entry
+CMAKE_COMMAND:INTERNAL=/usr/bin/cmake
+`
+
+func newCMakeCacheTestPage(t *testing.T, fake *fakeRunner) *CMakeCachePage {
+	t.Helper()
+	wsRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(wsRoot, "build"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wsRoot, "build", "CMakeCache.txt"), []byte(testCMakeCache), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Defaults()
+	p := NewCMakeCachePage(&cfg, wsRoot, fake)
+	p.SetSize(120, 30)
+	p.Update(p.load()())
+	return p
+}
+
+func TestCMakeCachePageHidesInternalAndSearches(t *testing.T) {
+	p := newCMakeCacheTestPage(t, &fakeRunner{})
+
+	view := p.View()
+	if !strings.Contains(view, "BOARD") || !strings.Contains(view, "ZEPHYR_SDK_INSTALL_DIR") || strings.Contains(view, "CMAKE_COMMAND") {
+		t.Fatalf("expected INTERNAL entries hidden, got:\n%s", view)
+	}
+
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	if view := p.View(); !strings.Contains(view, "CMAKE_COMMAND") {
+		t.Fatalf("expected INTERNAL entries after i, got:\n%s", view)
+	}
+
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	if !p.InputCaptured() {
+		t.Fatal("expected search to capture input")
+	}
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("toolchain")})
+	rows := p.rows()
+	if len(rows) != 1 || rows[0].Name != "ZEPHYR_SDK_INSTALL_DIR" {
+		t.Fatalf("expected search to match help text, got %+v", rows)
+	}
+}
+
+func TestCMakeCachePageEditReconfigures(t *testing.T) {
+	fake := &fakeRunner{}
+	p := newCMakeCacheTestPage(t, fake)
+
+	p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !p.InputCaptured() || p.edit.Value() != "nrf52840dk/nrf52840" {
+		t.Fatalf("expected edit prefilled with current value, got %q", p.edit.Value())
+	}
+	p.edit.SetValue("qemu_x86")
+	_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil || !p.running {
+		t.Fatal("expected reconfigure command")
+	}
+	want := []string{"build", "-d", "build", "--cmake-only", "--", "-DBOARD=qemu_x86"}
+	if len(fake.runCalls) != 1 || !reflect.DeepEqual(fake.runCalls[0].args, want) {
+		t.Fatalf("run calls = %+v, want args %v", fake.runCalls, want)
+	}
+
+	p.Update(west.CommandResultMsg{RequestID: p.activeRequestID, ExitCode: 0})
+	if p.running || !strings.Contains(p.message, "Reconfigured") {
+		t.Fatalf("expected reconfigure to finish, got running=%v message=%q", p.running, p.message)
+	}
+}
+
+func TestCMakeCachePageTogglesBool(t *testing.T) {
+	fake := &fakeRunner{}
+	p := newCMakeCacheTestPage(t, fake)
+
+	p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	p.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	want := []string{"build", "-d", "build", "--cmake-only", "--", "-DCMAKE_VERBOSE_MAKEFILE=ON"}
+	if len(fake.runCalls) != 1 || !reflect.DeepEqual(fake.runCalls[0].args, want) {
+		t.Fatalf("run calls = %+v, want args %v", fake.runCalls, want)
+	}
+}
@@ -0,0 +1,36 @@
+package pages
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/buckleypaul/gust/internal/config"
+	"github.com/buckleypaul/gust/internal/west"
+)
+
+// compileCommandsSyncedMsg reports the result of pointing the project's
+// compile_commands.json at the selected build.
+type compileCommandsSyncedMsg struct {
+	target string
+	err    error
+}
+
+// syncCompileCommands links compile_commands.json at the project root to
+// the database of the selected build dir, as configured by
+// Config.CompileCommands.
+func (p *ProjectPage) syncCompileCommands() tea.Cmd {
+	mode := p.cfg.CompileCommands
+	if mode == config.CompileCommandsOff {
+		return nil
+	}
+	projectDir := p.projectAbsPath()
+	if projectDir == "" {
+		projectDir = p.wsRoot
+	}
+	wsRoot, buildDir := p.wsRoot, p.buildDir()
+	filter := mode == config.CompileCommandsFiltered
+	mount := containerMount(p.cfg)
+	return func() tea.Msg {
+		target, err := west.SyncCompileCommands(wsRoot, buildDir, projectDir, filter, mount)
+		return compileCommandsSyncedMsg{target: target, err: err}
+	}
+}
@@ -0,0 +1,158 @@
+package pages
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/reflow/truncate"
+
+	"github.com/buckleypaul/gust/internal/ui"
+	"github.com/buckleypaul/gust/internal/west"
+)
+
+// editorClosedMsg is sent when the editor launched from the diagnostics list
+// exits and the TUI resumes.
+type editorClosedMsg struct {
+	err error
+}
+
+// diagnosticSummary renders "2 errors, 1 warning" with severity colors.
+func diagnosticSummary(errs, warns int) string {
+	var parts []string
+	if errs > 0 {
+		parts = append(parts, ui.ErrorBadge(plural(errs, "error")))
+	}
+	if warns > 0 {
+		parts = append(parts, ui.WarningBadge(plural(warns, "warning")))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+// renderDiagnostics renders a scrolling list of diagnostics with the cursor
+// row highlighted, fitting into height lines.
+func renderDiagnostics(diags []west.Diagnostic, cursor, width, height int) string {
+	if len(diags) == 0 {
+		return ui.DimStyle.Render("  No errors or warnings.")
+	}
+	height = max(height, 1)
+	start := max(cursor-height/2, 0)
+	end := min(start+height, len(diags))
+	start = max(end-height, 0)
+
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		d := diags[i]
+		prefix := "  "
+		if i == cursor {
+			prefix = ui.BoldStyle.Render("> ")
+		}
+		sev := ui.WarningBadge("W")
+		if d.Severity == west.SeverityError {
+			sev = ui.ErrorBadge("E")
+		}
+		line := fmt.Sprintf("%s %-10s %s  %s", sev, d.Source, diagnosticLocation(d), d.Message)
+		if w := width - 4; w > 0 {
+			line = truncate.String(line, uint(w))
+		}
+		b.WriteString(prefix + line + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// diagnosticLocation formats file:line:col, omitting unknown parts.
+func diagnosticLocation(d west.Diagnostic) string {
+	if d.File == "" {
+		return ui.DimStyle.Render("(no location)")
+	}
+	loc := d.File
+	if d.Line > 0 {
+		loc += ":" + strconv.Itoa(d.Line)
+		if d.Column > 0 {
+			loc += ":" + strconv.Itoa(d.Column)
+		}
+	}
+	return loc
+}
+
+// resolveDiagnosticFile finds the file a diagnostic names. Tools report paths
+// relative to the build directory, the project, or ZEPHYR_BASE, so each is
+// tried in turn; an empty string means the file could not be found.
+func resolveDiagnosticFile(file string, dirs ...string) string {
+	if file == "" {
+		return ""
+	}
+	if filepath.IsAbs(file) {
+		if _, err := os.Stat(file); err == nil {
+			return file
+		}
+		return ""
+	}
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		candidate := filepath.Join(dir, file)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// editorCommand builds the command that opens file at line and column in the
+// user's editor ($VISUAL, then $EDITOR, then vi). Editors that take a
+// file:line:col argument get one; the rest get the common +line form.
+func editorCommand(editor, file string, line, col int) *exec.Cmd {
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		fields = []string{"vi"}
+	}
+	args := fields[1:]
+	switch filepath.Base(fields[0]) {
+	case "code", "codium", "cursor":
+		args = append(args, "-g", fmt.Sprintf("%s:%d:%d", file, max(line, 1), max(col, 1)))
+	case "subl", "hx", "helix", "zed":
+		args = append(args, fmt.Sprintf("%s:%d:%d", file, max(line, 1), max(col, 1)))
+	default:
+		if line > 0 {
+			args = append(args, "+"+strconv.Itoa(line))
+		}
+		args = append(args, file)
+	}
+	return exec.Command(fields[0], args...)
+}
+
+// userEditor returns the configured editor command line.
+func userEditor() string {
+	if v := os.Getenv("VISUAL"); v != "" {
+		return v
+	}
+	return os.Getenv("EDITOR")
+}
+
+// openInEditor suspends the TUI and opens the diagnostic's location.
+func openInEditor(d west.Diagnostic, dirs ...string) (tea.Cmd, error) {
+	file := resolveDiagnosticFile(d.File, dirs...)
+	if file == "" {
+		if d.File == "" {
+			return nil, fmt.Errorf("diagnostic has no source location")
+		}
+		return nil, fmt.Errorf("cannot find %s", d.File)
+	}
+	cmd := editorCommand(userEditor(), file, d.Line, d.Column)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorClosedMsg{err: err}
+	}), nil
+}
@@ -0,0 +1,52 @@
+package pages
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEditorCommandLineArguments(t *testing.T) {
+	cases := []struct {
+		editor string
+		want   string
+	}{
+		{"", "vi +12 /ws/main.c"},
+		{"nvim", "nvim +12 /ws/main.c"},
+		{"code --wait", "code --wait -g /ws/main.c:12:5"},
+		{"/usr/local/bin/hx", "/usr/local/bin/hx /ws/main.c:12:5"},
+	}
+	for _, c := range cases {
+		cmd := editorCommand(c.editor, "/ws/main.c", 12, 5)
+		got := strings.Join(cmd.Args, " ")
+		if got != c.want {
+			t.Errorf("editorCommand(%q) = %q, want %q", c.editor, got, c.want)
+		}
+	}
+}
+
+func TestResolveDiagnosticFileTriesEachDir(t *testing.T) {
+	root := t.TempDir()
+	build := filepath.Join(root, "build")
+	zephyr := filepath.Join(root, "zephyr")
+	for _, dir := range []string{build, filepath.Join(zephyr, "drivers")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	kconfig := filepath.Join(zephyr, "drivers", "Kconfig")
+	if err := os.WriteFile(kconfig, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := resolveDiagnosticFile("drivers/Kconfig", build, zephyr); got != kconfig {
+		t.Errorf("expected %s, got %q", kconfig, got)
+	}
+	if got := resolveDiagnosticFile(kconfig); got != kconfig {
+		t.Errorf("expected absolute path to resolve to itself, got %q", got)
+	}
+	if got := resolveDiagnosticFile("missing.c", build, zephyr); got != "" {
+		t.Errorf("expected missing file to resolve to empty, got %q", got)
+	}
+}
@@ -0,0 +1,153 @@
+package pages
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/buckleypaul/gust/internal/ui"
+)
+
+// extraKind identifies one of the build extras pickers.
+type extraKind int
+
+const (
+	extraNone    extraKind = iota - 1
+	extraConf              // EXTRA_CONF_FILE fragments
+	extraOverlay           // EXTRA_DTC_OVERLAY_FILE overlays
+	extraSnippet           // -S snippets
+	extraKindCount
+)
+
+var extraLabels = [extraKindCount]string{"Conf", "Overlay", "Snippets"}
+
+// multiSelect is a list of options with any number selected, opened as a
+// dropdown under its label.
+type multiSelect struct {
+	options  []string
+	selected []string
+	cursor   int
+	open     bool
+}
+
+// setOptions replaces the offered options. Selected values that are no
+// longer offered are kept at the end so a profile's choices stay visible.
+func (m *multiSelect) setOptions(opts []string) {
+	m.options = append([]string(nil), opts...)
+	for _, s := range m.selected {
+		if !contains(m.options, s) {
+			m.options = append(m.options, s)
+		}
+	}
+	if m.cursor >= len(m.options) {
+		m.cursor = max(len(m.options)-1, 0)
+	}
+	if len(m.options) == 0 {
+		m.open = false
+	}
+}
+
+// setSelected replaces the selection, adding any unknown values as options.
+func (m *multiSelect) setSelected(values []string) {
+	m.selected = append([]string(nil), values...)
+	m.setOptions(m.options)
+}
+
+// toggle flips the option under the cursor, keeping selections in option
+// order so the generated arguments are stable.
+func (m *multiSelect) toggle() {
+	if m.cursor >= len(m.options) {
+		return
+	}
+	opt := m.options[m.cursor]
+	var next []string
+	for _, o := range m.options {
+		on := contains(m.selected, o)
+		if o == opt {
+			on = !on
+		}
+		if on {
+			next = append(next, o)
+		}
+	}
+	m.selected = next
+}
+
+// handleKey navigates an open list. It reports whether the key was used.
+func (m *multiSelect) handleKey(keyStr string) bool {
+	switch keyStr {
+	case "up":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down":
+		if m.cursor < len(m.options)-1 {
+			m.cursor++
+		}
+	case " ":
+		m.toggle()
+	case "enter", "esc":
+		m.open = false
+	default:
+		return false
+	}
+	return true
+}
+
+// view renders the label row and, when open, the option list.
+func (m *multiSelect) view(label string, focused bool, width int) string {
+	focusedLabel := lipgloss.NewStyle().Foreground(ui.Primary).Bold(true)
+	normalLabel := lipgloss.NewStyle().Foreground(ui.Text)
+	lbl := normalLabel.Render(fmt.Sprintf("%-9s", label))
+	if focused {
+		lbl = focusedLabel.Render(fmt.Sprintf("%-9s", label))
+	}
+
+	value := strings.Join(m.selected, ", ")
+	switch {
+	case value != "":
+	case len(m.options) == 0:
+		value = ui.DimStyle.Render("none found")
+	default:
+		value = ui.DimStyle.Render(fmt.Sprintf("none (%d available, enter to choose)", len(m.options)))
+	}
+	var b strings.Builder
+	b.WriteString("  " + lbl + " " + value + "\n")
+	if !m.open {
+		return b.String()
+	}
+
+	padding := strings.Repeat(" ", 9+3) // label width + "  " prefix + " " after label
+	selectedStyle := lipgloss.NewStyle().Foreground(ui.Primary).Bold(true)
+	start, end := scrollWindow(m.cursor, len(m.options), maxDropdownItems)
+	for i := start; i < end; i++ {
+		check := "[ ] "
+		if contains(m.selected, m.options[i]) {
+			check = "[x] "
+		}
+		name := check + m.options[i]
+		if w := width - len(padding) - 4; w > 0 && len(name) > w {
+			name = name[:w]
+		}
+		prefix := "  "
+		if i == m.cursor {
+			prefix = selectedStyle.Render("> ")
+			name = selectedStyle.Render(name)
+		} else {
+			name = ui.DimStyle.Render(name)
+		}
+		b.WriteString(padding + prefix + name + "\n")
+	}
+	b.WriteString(padding + "  " + ui.DimStyle.Render("space: toggle  enter: done") + "\n")
+	return b.String()
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -1,13 +1,16 @@
 package pages
 
 import (
+	"context"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/buckleypaul/gust/internal/config"
 	"github.com/buckleypaul/gust/internal/store"
 	"github.com/buckleypaul/gust/internal/ui"
 	"github.com/buckleypaul/gust/internal/west"
@@ -19,6 +22,7 @@ type flashSection struct {
 	flashing   bool
 	flashStart time.Time
 	lastBuild  *store.BuildRecord
+	domain     string // sysbuild domain to flash; empty flashes all
 	message    string
 	seq        int
 }
@@ -28,18 +32,74 @@ func (f *flashSection) nextRequestID() string {
 	return fmt.Sprintf("flash-%d", f.seq)
 }
 
-func (f *flashSection) refreshLastBuild(s *store.Store) {
+// refreshLastBuild loads the most recent build made in buildDir, the
+// expanded build directory a flash would use.
+func (f *flashSection) refreshLastBuild(s *store.Store, buildDir string) {
+	f.lastBuild = nil
 	if s == nil {
-		f.lastBuild = nil
 		return
 	}
 	builds, err := s.Builds()
-	if err != nil || len(builds) == 0 {
-		f.lastBuild = nil
+	if err != nil {
 		return
 	}
-	last := builds[len(builds)-1]
-	f.lastBuild = &last
+	for i := len(builds) - 1; i >= 0; i-- {
+		if sameBuildDir(builds[i].BuildDir, buildDir) {
+			last := builds[i]
+			f.lastBuild = &last
+			return
+		}
+	}
+}
+
+// sameBuildDir compares build directories, treating empty as west's default.
+func sameBuildDir(a, b string) bool {
+	if a == "" {
+		a = config.DefaultBuildDir
+	}
+	if b == "" {
+		b = config.DefaultBuildDir
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
+
+// domains returns the sysbuild domains of the last build, if any.
+func (f *flashSection) domains() []store.DomainRecord {
+	if f.lastBuild == nil || !f.lastBuild.Success {
+		return nil
+	}
+	return f.lastBuild.Domains
+}
+
+// cycleDomain steps the flash target through all domains, then each one.
+func (f *flashSection) cycleDomain() {
+	domains := f.domains()
+	if len(domains) == 0 {
+		f.domain = ""
+		return
+	}
+	next := ""
+	if f.domain == "" {
+		next = domains[0].Name
+	} else {
+		for i, d := range domains {
+			if d.Name == f.domain && i+1 < len(domains) {
+				next = domains[i+1].Name
+			}
+		}
+	}
+	f.domain = next
+}
+
+// targetDomain returns the domain to pass to --domain, dropping a choice
+// the last build no longer has.
+func (f *flashSection) targetDomain() string {
+	for _, d := range f.domains() {
+		if d.Name == f.domain {
+			return f.domain
+		}
+	}
+	return ""
 }
 
 // viewSection renders the Flash section header and status.
@@ -59,6 +119,16 @@ func (f *flashSection) viewSection(width int) string {
 	} else {
 		sb.WriteString("  " + ui.DimStyle.Render("No recent builds. Run a build first.") + "\n")
 	}
+	if domains := f.domains(); len(domains) > 0 {
+		target := "all domains"
+		if d := f.targetDomain(); d != "" {
+			target = d
+		}
+		sb.WriteString("  Target: " + ui.BoldStyle.Render(target) + ui.DimStyle.Render("  (ctrl+o: change)") + "\n")
+		for _, d := range domains {
+			sb.WriteString(fmt.Sprintf("    %-12s %9s  %s\n", d.Name, domainSize(d), ui.DimStyle.Render(artifactNames(d.Artifacts))))
+		}
+	}
 	if f.message != "" {
 		sb.WriteString("  " + f.message + "\n")
 	}
@@ -68,31 +138,30 @@ func (f *flashSection) viewSection(width int) string {
 	return sb.String()
 }
 
-// start launches west flash, writes the command header to out.
-func (f *flashSection) start(buildDir, flashRunner string, runner west.Runner, out *strings.Builder) (requestID string, cmd tea.Cmd) {
+// start launches west flash, writes the command header to out. Cancelling ctx
+// stops the flash.
+func (f *flashSection) start(ctx context.Context, buildDir, flashRunner string, runner west.Runner, out *strings.Builder) (requestID string, cmd tea.Cmd) {
 	f.flashing = true
 	f.flashStart = time.Now()
 	f.message = ""
 	requestID = f.nextRequestID()
 
-	args := []string{"flash"}
-	if buildDir != "" {
-		args = append(args, "-d", buildDir)
-	}
-	if flashRunner != "" {
-		args = append(args, "--runner", flashRunner)
-	}
+	args := west.FlashArgs(buildDir, flashRunner, f.targetDomain())
 	out.WriteString("$ west " + strings.Join(args, " ") + "\n\n")
-	return requestID, west.WithRequestID(requestID, runner.Run("west", args...))
+	return requestID, west.WithRequestID(requestID, runner.Run(ctx, "west", args...))
 }
 
 // complete finalises flash state and records to store.
 func (f *flashSection) complete(result west.CommandResultMsg, board string, s *store.Store, out *strings.Builder) {
 	f.flashing = false
-	success := result.ExitCode == 0
-	out.WriteString(result.Output)
+	success := result.ExitCode == 0 && !result.Cancelled
+	if !result.Streamed {
+		out.WriteString(result.Output)
+	}
 	status := "success"
-	if !success {
+	if result.Cancelled {
+		status = "cancelled"
+	} else if !success {
 		status = fmt.Sprintf("failed (exit code: %d)", result.ExitCode)
 	}
 	out.WriteString(fmt.Sprintf("\nFlash %s in %s\n", status, result.Duration))
@@ -102,6 +171,8 @@ func (f *flashSection) complete(result west.CommandResultMsg, board string, s *s
 			Timestamp: f.flashStart,
 			Success:   success,
 			Duration:  result.Duration.String(),
+			Cancelled: result.Cancelled,
+			Domain:    f.targetDomain(),
 		})
 	}
 }
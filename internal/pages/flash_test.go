@@ -1,10 +1,12 @@
 package pages
 
 import (
+	"context"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/buckleypaul/gust/internal/store"
 	"github.com/buckleypaul/gust/internal/west"
 )
 
@@ -13,7 +15,7 @@ func TestFlashSectionStartPassesFlags(t *testing.T) {
 	fake := &fakeRunner{nextMsg: west.CommandResultMsg{Output: "ok", ExitCode: 0, Duration: time.Second}}
 
 	f := flashSection{}
-	requestID, cmd := f.start("build-custom", "jlink", fake, &out)
+	requestID, cmd := f.start(context.Background(), "build-custom", "jlink", fake, &out)
 	if requestID == "" {
 		t.Fatal("expected non-empty requestID")
 	}
@@ -34,7 +36,7 @@ func TestFlashSectionStartOmitsEmptyFlags(t *testing.T) {
 	fake := &fakeRunner{nextMsg: west.CommandResultMsg{Output: "ok", ExitCode: 0, Duration: time.Second}}
 
 	f := flashSection{}
-	_, cmd := f.start("", "", fake, &out)
+	_, cmd := f.start(context.Background(), "", "", fake, &out)
 	_ = cmd()
 
 	args := fake.runCalls[0].args
@@ -42,3 +44,25 @@ func TestFlashSectionStartOmitsEmptyFlags(t *testing.T) {
 		t.Fatalf("expected bare [flash] args, got %v", args)
 	}
 }
+
+func TestFlashSectionRefreshLastBuildMatchesBuildDir(t *testing.T) {
+	st := store.New(t.TempDir())
+	for _, r := range []store.BuildRecord{
+		{Board: "nrf52840dk", BuildDir: "build/nrf52840dk", Timestamp: time.Now(), Success: true},
+		{Board: "native_sim", BuildDir: "build/native_sim", Timestamp: time.Now()},
+	} {
+		if err := st.AddBuild(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var f flashSection
+	f.refreshLastBuild(st, "build/nrf52840dk")
+	if f.lastBuild == nil || f.lastBuild.Board != "nrf52840dk" {
+		t.Fatalf("expected the nrf52840dk build, got %+v", f.lastBuild)
+	}
+	f.refreshLastBuild(st, "build/other")
+	if f.lastBuild != nil {
+		t.Fatalf("expected no build for an unused dir, got %+v", f.lastBuild)
+	}
+}
@@ -0,0 +1,444 @@
+package pages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/reflow/truncate"
+
+	"github.com/buckleypaul/gust/internal/app"
+	"github.com/buckleypaul/gust/internal/config"
+	"github.com/buckleypaul/gust/internal/ui"
+	"github.com/buckleypaul/gust/internal/west"
+)
+
+// footprintLoadedMsg carries a rom.json or ram.json report read from disk.
+type footprintLoadedMsg struct {
+	kind     west.FootprintKind
+	buildDir string
+	report   *west.Footprint
+	err      error
+}
+
+// footprintRow is one visible line of the footprint tree.
+type footprintRow struct {
+	node     *west.FootprintNode
+	depth    int
+	key      string
+	expanded bool
+}
+
+// FootprintPage browses the ROM and RAM reports Zephyr writes for a build
+// directory as an expandable tree.
+type FootprintPage struct {
+	cfg             *config.Config
+	wsRoot          string
+	runner          west.Runner
+	buildDir        string // Build Dir setting, possibly a template
+	project         string
+	board           string
+	profile         string
+	kind            west.FootprintKind
+	reports         map[west.FootprintKind]*west.Footprint
+	errs            map[west.FootprintKind]error
+	expanded        map[string]bool
+	cursor          int
+	search          textinput.Model
+	searching       bool
+	running         bool
+	requestSeq      int
+	activeRequestID string
+	cancel          context.CancelFunc
+	width, height   int
+	message         string
+}
+
+func NewFootprintPage(cfg *config.Config, wsRoot string, runners ...west.Runner) *FootprintPage {
+	runner := west.RealRunner()
+	if len(runners) > 0 && runners[0] != nil {
+		runner = runners[0]
+	}
+	ti := textinput.New()
+	ti.Placeholder = "symbol, file or directory"
+	ti.Prompt = "/ "
+	ti.CharLimit = 128
+	return &FootprintPage{
+		cfg:      cfg,
+		wsRoot:   wsRoot,
+		runner:   runner,
+		buildDir: cfg.BuildDir,
+		project:  cfg.LastProject,
+		board:    cfg.DefaultBoard,
+		profile:  cfg.ActiveProfile,
+		kind:     west.FootprintROM,
+		reports:  make(map[west.FootprintKind]*west.Footprint),
+		errs:     make(map[west.FootprintKind]error),
+		expanded: make(map[string]bool),
+		search:   ti,
+	}
+}
+
+func (p *FootprintPage) Init() tea.Cmd {
+	return p.loadReports()
+}
+
+// loadReports reads both reports for the current build directory.
+func (p *FootprintPage) loadReports() tea.Cmd {
+	return tea.Batch(
+		loadFootprint(p.wsRoot, p.dir(), west.FootprintROM),
+		loadFootprint(p.wsRoot, p.dir(), west.FootprintRAM),
+	)
+}
+
+// dir returns the build directory the reports are read from.
+func (p *FootprintPage) dir() string {
+	return west.ExpandBuildDir(p.buildDir, p.project, p.board, p.profile)
+}
+
+// retarget applies a selection change and reloads the reports if it moved
+// the build directory.
+func (p *FootprintPage) retarget(change func()) tea.Cmd {
+	before := p.dir()
+	change()
+	if p.dir() == before {
+		return nil
+	}
+	p.reports = make(map[west.FootprintKind]*west.Footprint)
+	p.errs = make(map[west.FootprintKind]error)
+	p.expanded = make(map[string]bool)
+	p.cursor = 0
+	return p.loadReports()
+}
+
+func loadFootprint(wsRoot, buildDir string, kind west.FootprintKind) tea.Cmd {
+	return func() tea.Msg {
+		report, err := west.LoadFootprint(west.FootprintPath(wsRoot, buildDir, kind))
+		return footprintLoadedMsg{kind: kind, buildDir: buildDir, report: report, err: err}
+	}
+}
+
+func (p *FootprintPage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case app.BuildDirChangedMsg:
+		return p, p.retarget(func() { p.buildDir = msg.Dir })
+
+	case app.ProjectSelectedMsg:
+		return p, p.retarget(func() { p.project = msg.Path })
+
+	case app.BoardSelectedMsg:
+		return p, p.retarget(func() { p.board = msg.Board })
+
+	case app.ProfileSelectedMsg:
+		return p, p.retarget(func() { p.profile = msg.Profile.Name })
+
+	case footprintLoadedMsg:
+		if msg.buildDir != p.dir() {
+			return p, nil
+		}
+		p.reports[msg.kind] = msg.report
+		p.errs[msg.kind] = msg.err
+		if msg.kind == p.kind {
+			p.clampCursor()
+		}
+		return p, nil
+
+	case west.CommandOutputMsg:
+		if !p.running || msg.RequestID != p.activeRequestID {
+			return p, nil
+		}
+		if line := strings.TrimSpace(msg.Line); line != "" {
+			p.message = line
+		}
+		return p, msg.Next
+
+	case west.CommandResultMsg:
+		if !p.running || msg.RequestID != p.activeRequestID {
+			return p, nil
+		}
+		p.running = false
+		p.activeRequestID = ""
+		if p.cancel != nil {
+			p.cancel()
+			p.cancel = nil
+		}
+		switch {
+		case msg.Cancelled:
+			p.message = "Report cancelled"
+			return p, nil
+		case msg.ExitCode != 0:
+			p.message = fmt.Sprintf("%s report failed (exit code: %d)", strings.ToUpper(string(p.kind)), msg.ExitCode)
+			return p, nil
+		}
+		p.message = fmt.Sprintf("%s report generated in %s", strings.ToUpper(string(p.kind)), msg.Duration)
+		return p, loadFootprint(p.wsRoot, p.dir(), p.kind)
+
+	case tea.KeyMsg:
+		if p.searching {
+			return p.handleSearchKey(msg)
+		}
+		if p.running {
+			if msg.String() == "ctrl+x" && p.cancel != nil {
+				p.cancel()
+				p.message = "Cancelling report..."
+			}
+			return p, nil
+		}
+
+		rows := p.rows()
+		switch msg.String() {
+		case "up":
+			if p.cursor > 0 {
+				p.cursor--
+			}
+		case "down":
+			if p.cursor < len(rows)-1 {
+				p.cursor++
+			}
+		case "enter", " ":
+			if p.cursor < len(rows) && len(rows[p.cursor].node.Children) > 0 {
+				p.expanded[rows[p.cursor].key] = !rows[p.cursor].expanded
+			}
+		case "right":
+			if p.cursor < len(rows) && len(rows[p.cursor].node.Children) > 0 {
+				p.expanded[rows[p.cursor].key] = true
+			}
+		case "left":
+			if p.cursor < len(rows) {
+				p.collapseOrParent(rows)
+			}
+		case "/":
+			p.searching = true
+			p.search.Focus()
+			return p, textinput.Blink
+		case "esc":
+			p.search.SetValue("")
+			p.clampCursor()
+		case "t":
+			if p.kind == west.FootprintROM {
+				p.kind = west.FootprintRAM
+			} else {
+				p.kind = west.FootprintROM
+			}
+			p.cursor = 0
+		case "r":
+			return p, p.runReport()
+		}
+	}
+	return p, nil
+}
+
+func (p *FootprintPage) handleSearchKey(msg tea.KeyMsg) (app.Page, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		p.searching = false
+		p.search.Blur()
+		return p, nil
+	case "esc":
+		p.searching = false
+		p.search.Blur()
+		p.search.SetValue("")
+		p.clampCursor()
+		return p, nil
+	}
+	var cmd tea.Cmd
+	p.search, cmd = p.search.Update(msg)
+	p.cursor = 0
+	return p, cmd
+}
+
+// collapseOrParent collapses the row under the cursor, or moves to its
+// parent if it is already collapsed.
+func (p *FootprintPage) collapseOrParent(rows []footprintRow) {
+	row := rows[p.cursor]
+	if row.expanded && len(row.node.Children) > 0 {
+		p.expanded[row.key] = false
+		return
+	}
+	for i := p.cursor - 1; i >= 0; i-- {
+		if rows[i].depth < row.depth {
+			p.cursor = i
+			return
+		}
+	}
+}
+
+func (p *FootprintPage) runReport() tea.Cmd {
+	p.running = true
+	p.requestSeq++
+	p.activeRequestID = fmt.Sprintf("footprint-%d", p.requestSeq)
+	args := west.FootprintArgs(p.dir(), p.kind)
+	p.message = "$ west " + strings.Join(args, " ")
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	return west.WithRequestID(p.activeRequestID, p.runner.Run(ctx, "west", args...))
+}
+
+func (p *FootprintPage) clampCursor() {
+	if n := len(p.rows()); p.cursor >= n {
+		p.cursor = max(n-1, 0)
+	}
+}
+
+// rows flattens the visible part of the current report. While a search is
+// active, only matching nodes and their ancestors are shown, fully expanded.
+func (p *FootprintPage) rows() []footprintRow {
+	report := p.reports[p.kind]
+	if report == nil {
+		return nil
+	}
+	query := strings.ToLower(strings.TrimSpace(p.search.Value()))
+	var matches map[*west.FootprintNode]bool
+	if query != "" {
+		matches = make(map[*west.FootprintNode]bool)
+		markFootprintMatches(report.Root, query, matches)
+	}
+
+	var rows []footprintRow
+	var walk func(n *west.FootprintNode, depth int, parentKey string)
+	walk = func(n *west.FootprintNode, depth int, parentKey string) {
+		for _, c := range n.Children {
+			if matches != nil && !matches[c] {
+				continue
+			}
+			k := c.Identifier
+			if k == "" {
+				k = parentKey + "/" + c.Name
+			}
+			open := p.expanded[k] || (matches != nil && len(c.Children) > 0)
+			rows = append(rows, footprintRow{node: c, depth: depth, key: k, expanded: open})
+			if open {
+				walk(c, depth+1, k)
+			}
+		}
+	}
+	walk(report.Root, 0, "")
+	return rows
+}
+
+// markFootprintMatches records every node whose name contains query or that
+// has such a descendant.
+func markFootprintMatches(n *west.FootprintNode, query string, matches map[*west.FootprintNode]bool) bool {
+	found := strings.Contains(strings.ToLower(n.Name), query)
+	for _, c := range n.Children {
+		if markFootprintMatches(c, query, matches) {
+			found = true
+		}
+	}
+	if found {
+		matches[n] = true
+	}
+	return found
+}
+
+func (p *FootprintPage) View() string {
+	var b strings.Builder
+
+	var hdr strings.Builder
+	for _, k := range []west.FootprintKind{west.FootprintROM, west.FootprintRAM} {
+		name := strings.ToUpper(string(k))
+		if k == p.kind {
+			hdr.WriteString(ui.BoldStyle.Render(" [" + name + "] "))
+		} else {
+			hdr.WriteString(ui.DimStyle.Render("  " + name + "  "))
+		}
+	}
+	dir := p.dir()
+	if dir == "" {
+		dir = "build"
+	}
+	hdr.WriteString(ui.DimStyle.Render("  " + dir))
+	if report := p.reports[p.kind]; report != nil {
+		hdr.WriteString(fmt.Sprintf("  total %s", formatBytes(report.TotalSize)))
+	}
+	hdr.WriteString("\n")
+	if p.searching || p.search.Value() != "" {
+		hdr.WriteString(p.search.View() + "\n")
+	}
+	if p.message != "" {
+		hdr.WriteString("  " + p.message + "\n")
+	}
+	b.WriteString(hdr.String() + "\n")
+
+	report := p.reports[p.kind]
+	if report == nil {
+		msg := fmt.Sprintf("  No %s report in %s. Press r to run %s_report.", strings.ToUpper(string(p.kind)), dir, p.kind)
+		b.WriteString(ui.DimStyle.Render(msg))
+		return b.String()
+	}
+
+	rows := p.rows()
+	if len(rows) == 0 {
+		b.WriteString(ui.DimStyle.Render("  No matching symbols."))
+		return b.String()
+	}
+	height := max(p.height-strings.Count(hdr.String(), "\n")-3, 1)
+	b.WriteString(renderFootprintRows(rows, p.cursor, report.TotalSize, p.width, height))
+	return b.String()
+}
+
+// renderFootprintRows renders a scrolling window of the tree around cursor.
+func renderFootprintRows(rows []footprintRow, cursor int, total int64, width, height int) string {
+	start := max(cursor-height/2, 0)
+	end := min(start+height, len(rows))
+	start = max(end-height, 0)
+
+	nameWidth := max(width-24, 10)
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		r := rows[i]
+		prefix := "  "
+		if i == cursor {
+			prefix = ui.BoldStyle.Render("> ")
+		}
+		marker := "  "
+		if len(r.node.Children) > 0 {
+			marker = "▸ "
+			if r.expanded {
+				marker = "▾ "
+			}
+		}
+		name := truncate.StringWithTail(strings.Repeat("  ", r.depth)+marker+r.node.Name, uint(nameWidth), "…")
+		pct := 0.0
+		if total > 0 {
+			pct = float64(r.node.Size) * 100 / float64(total)
+		}
+		b.WriteString(fmt.Sprintf("%s%-*s %9s %5.1f%%\n", prefix, nameWidth, name, formatBytes(r.node.Size), pct))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (p *FootprintPage) Name() string { return "Footprint" }
+
+func (p *FootprintPage) ShortHelp() []key.Binding {
+	if p.searching {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "done")),
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "clear")),
+		}
+	}
+	if p.running {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "cancel")),
+		}
+	}
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "expand/collapse")),
+		key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
+		key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "rom/ram")),
+		key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "run report")),
+	}
+}
+
+func (p *FootprintPage) InputCaptured() bool {
+	return p.searching
+}
+
+func (p *FootprintPage) SetSize(w, h int) {
+	p.width = w
+	p.height = h
+	p.search.Width = max(w-6, 10)
+}
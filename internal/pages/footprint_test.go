@@ -0,0 +1,113 @@
+package pages
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/buckleypaul/gust/internal/config"
+	"github.com/buckleypaul/gust/internal/west"
+)
+
+const testROMReport = `{
+  "symbols": {
+    "name": "Root", "identifier": "root", "size": 1000,
+    "children": [
+      {"name": "ZEPHYR_BASE", "identifier": ":/ZEPHYR_BASE", "size": 700, "children": [
+        {"name": "kernel", "identifier": ":/ZEPHYR_BASE/kernel", "size": 400, "children": [
+          {"name": "z_impl_k_sleep", "identifier": ":/ZEPHYR_BASE/kernel/z_impl_k_sleep", "size": 40}
+        ]},
+        {"name": "drivers", "identifier": ":/ZEPHYR_BASE/drivers", "size": 300}
+      ]},
+      {"name": "WORKSPACE", "identifier": ":/WORKSPACE", "size": 300}
+    ]
+  },
+  "total_size": 1000
+}`
+
+func newFootprintTestPage(t *testing.T, fake *fakeRunner) *FootprintPage {
+	t.Helper()
+	wsRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(wsRoot, "build"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wsRoot, "build", "rom.json"), []byte(testROMReport), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Defaults()
+	p := NewFootprintPage(&cfg, wsRoot, fake)
+	p.SetSize(100, 30)
+	p.Update(loadFootprint(wsRoot, p.buildDir, west.FootprintROM)())
+	return p
+}
+
+func TestFootprintPageExpandsAndSearchesTree(t *testing.T) {
+	p := newFootprintTestPage(t, &fakeRunner{})
+
+	view := p.View()
+	if !strings.Contains(view, "ZEPHYR_BASE") || !strings.Contains(view, "70.0%") || strings.Contains(view, "kernel") {
+		t.Fatalf("expected collapsed top level, got:\n%s", view)
+	}
+
+	p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if view := p.View(); !strings.Contains(view, "▾ ZEPHYR_BASE") || !strings.Contains(view, "kernel") {
+		t.Fatalf("expected ZEPHYR_BASE expanded, got:\n%s", view)
+	}
+	p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	p.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if p.cursor != 0 {
+		t.Fatalf("expected left on a collapsed node to move to its parent, cursor=%d", p.cursor)
+	}
+
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	if !p.InputCaptured() {
+		t.Fatal("expected search to capture input")
+	}
+	for _, r := range "sleep" {
+		p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	rows := p.rows()
+	if len(rows) != 3 || rows[2].node.Name != "z_impl_k_sleep" {
+		t.Fatalf("expected match with its ancestors, got %d rows", len(rows))
+	}
+	if strings.Contains(p.View(), "WORKSPACE") {
+		t.Fatal("expected non-matching nodes to be hidden")
+	}
+
+	p.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if p.InputCaptured() || len(p.rows()) != 4 {
+		t.Fatalf("expected esc to clear the search, got %d rows", len(p.rows()))
+	}
+}
+
+func TestFootprintPageRunsReportAndReloads(t *testing.T) {
+	fake := &fakeRunner{nextMsg: west.CommandResultMsg{ExitCode: 0, Duration: time.Second}}
+	p := newFootprintTestPage(t, fake)
+
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	if !strings.Contains(p.View(), "No RAM report") {
+		t.Fatalf("expected missing RAM report hint, got:\n%s", p.View())
+	}
+
+	_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	if len(fake.runCalls) != 1 {
+		t.Fatalf("expected one west call, got %d", len(fake.runCalls))
+	}
+	want := []string{"build", "-d", "build", "-t", "ram_report"}
+	if !reflect.DeepEqual(fake.runCalls[0].args, want) {
+		t.Fatalf("expected args %v, got %v", want, fake.runCalls[0].args)
+	}
+
+	_, cmd = p.Update(cmd())
+	if cmd == nil {
+		t.Fatal("expected the report to be reloaded after a successful run")
+	}
+	if msg := cmd().(footprintLoadedMsg); msg.kind != west.FootprintRAM {
+		t.Fatalf("expected RAM report reload, got %s", msg.kind)
+	}
+}
@@ -0,0 +1,184 @@
+package pages
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/buckleypaul/gust/internal/app"
+	"github.com/buckleypaul/gust/internal/ui"
+	"github.com/buckleypaul/gust/internal/west"
+)
+
+// JobsPage lists every command launched through the job manager and shows
+// the output of any of them.
+type JobsPage struct {
+	jobs          *west.JobManager
+	cursor        int
+	viewing       string // ID of the job whose output is open
+	viewport      viewport.Model
+	width, height int
+	message       string
+}
+
+func NewJobsPage(m *west.JobManager) *JobsPage {
+	return &JobsPage{
+		jobs:     m,
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (p *JobsPage) Init() tea.Cmd { return nil }
+
+func (p *JobsPage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case west.CommandOutputMsg, west.CommandResultMsg:
+		// Output for tracked jobs is recorded by the manager; only refresh
+		// the open view. The owning page keeps driving the stream.
+		if p.viewing != "" {
+			p.refreshOutput()
+		}
+		return p, nil
+
+	case tea.KeyMsg:
+		if p.viewing != "" {
+			switch msg.String() {
+			case "esc":
+				p.viewing = ""
+				p.viewport.SetContent("")
+				return p, nil
+			case "ctrl+x":
+				p.cancelJob(p.viewing)
+				return p, nil
+			}
+			var cmd tea.Cmd
+			p.viewport, cmd = p.viewport.Update(msg)
+			return p, cmd
+		}
+
+		jobs := p.jobs.Jobs()
+		switch msg.String() {
+		case "down":
+			if p.cursor < len(jobs)-1 {
+				p.cursor++
+			}
+		case "up":
+			if p.cursor > 0 {
+				p.cursor--
+			}
+		case "enter":
+			if p.cursor < len(jobs) {
+				p.viewing = jobs[p.cursor].ID
+				p.refreshOutput()
+			}
+		case "ctrl+x":
+			if p.cursor < len(jobs) {
+				p.cancelJob(jobs[p.cursor].ID)
+			}
+		case "c":
+			p.jobs.ClearFinished()
+			p.cursor = 0
+			p.message = ""
+		}
+	}
+	return p, nil
+}
+
+func (p *JobsPage) cancelJob(id string) {
+	if p.jobs.Cancel(id) {
+		p.message = "Cancelling " + id + "..."
+	} else {
+		p.message = id + " is not running"
+	}
+}
+
+func (p *JobsPage) refreshOutput() {
+	atBottom := p.viewport.AtBottom()
+	p.viewport.SetContent(p.jobs.Output(p.viewing))
+	if atBottom {
+		p.viewport.GotoBottom()
+	}
+}
+
+func (p *JobsPage) View() string {
+	jobs := p.jobs.Jobs()
+	if p.cursor >= len(jobs) {
+		p.cursor = max(len(jobs)-1, 0)
+	}
+
+	var b strings.Builder
+	var listB strings.Builder
+	if p.message != "" {
+		listB.WriteString("  " + p.message + "\n\n")
+	}
+	if len(jobs) == 0 {
+		listB.WriteString(ui.DimStyle.Render("  No jobs yet. Builds, flashes, tests and west commands appear here."))
+		listB.WriteString("\n")
+	} else {
+		listB.WriteString(ui.DimStyle.Render(fmt.Sprintf("  %-8s  %-10s  %-8s  %-9s  %s",
+			"ID", "STATE", "STARTED", "DURATION", "COMMAND")) + "\n")
+		for i, j := range jobs {
+			cursor := "  "
+			if i == p.cursor {
+				cursor = ui.BoldStyle.Render("> ")
+			}
+			listB.WriteString(fmt.Sprintf("%s%-8s  %s  %-8s  %-9s  %s\n",
+				cursor, j.ID, jobStateBadge(j.State),
+				j.Start.Format("15:04:05"),
+				j.Duration().Round(time.Second), j.Command))
+		}
+	}
+	b.WriteString(ui.Panel("Jobs", listB.String(), p.width, 0, false))
+
+	if p.viewing != "" {
+		b.WriteString("\n")
+		b.WriteString(ui.Panel(p.viewing+" Output", p.viewport.View(), p.width, 0, false))
+	}
+	return b.String()
+}
+
+// jobStateBadge renders a fixed-width state column.
+func jobStateBadge(s west.JobState) string {
+	label := fmt.Sprintf("%-8s", strings.ToUpper(s.String()))
+	switch s {
+	case west.JobRunning:
+		return ui.AccentStyle.Render(label)
+	case west.JobSucceeded:
+		return ui.SuccessBadge(label)
+	case west.JobCancelled:
+		return ui.WarningBadge(label)
+	default:
+		return ui.ErrorBadge(label)
+	}
+}
+
+func (p *JobsPage) Name() string { return "Jobs" }
+
+func (p *JobsPage) ShortHelp() []key.Binding {
+	if p.viewing != "" {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
+			key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "cancel")),
+		}
+	}
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "view output")),
+		key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "cancel")),
+		key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "clear finished")),
+	}
+}
+
+func (p *JobsPage) SetSize(w, h int) {
+	p.width = w
+	p.height = h
+	vpHeight := h - 16
+	if vpHeight < 5 {
+		vpHeight = 5
+	}
+	p.viewport.Width = w - 4
+	p.viewport.Height = vpHeight
+}
@@ -0,0 +1,49 @@
+package pages
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/buckleypaul/gust/internal/west"
+)
+
+func TestJobsPageListsAndOpensJobOutput(t *testing.T) {
+	m := west.NewJobManager()
+	fake := &fakeRunner{nextMsg: west.CommandResultMsg{Output: "built ok\n", ExitCode: 0, Duration: time.Second}}
+	r := west.TrackJobs(fake, m)
+	west.Drain(r.Run(context.Background(), "west", "build", "-b", "nrf52840dk"), nil)
+
+	p := NewJobsPage(m)
+	p.SetSize(120, 40)
+	view := p.View()
+	if !strings.Contains(view, "west build -b nrf52840dk") {
+		t.Fatalf("expected job command in view, got:\n%s", view)
+	}
+
+	page, _ := p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	p = page.(*JobsPage)
+	if p.viewing == "" {
+		t.Fatal("expected job output to be open")
+	}
+	if !strings.Contains(p.View(), "built ok") {
+		t.Fatalf("expected job output in view, got:\n%s", p.View())
+	}
+
+	page, _ = p.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	p = page.(*JobsPage)
+	if p.viewing != "" {
+		t.Fatal("expected esc to close job output")
+	}
+}
+
+func TestJobsPageDoesNotDriveStreams(t *testing.T) {
+	p := NewJobsPage(west.NewJobManager())
+	_, cmd := p.Update(west.CommandOutputMsg{RequestID: "build-1", Line: "x", Next: func() tea.Msg { return nil }})
+	if cmd != nil {
+		t.Fatal("expected jobs page not to continue another page's stream")
+	}
+}
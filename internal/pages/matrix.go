@@ -0,0 +1,586 @@
+package pages
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/reflow/truncate"
+
+	"github.com/buckleypaul/gust/internal/app"
+	"github.com/buckleypaul/gust/internal/config"
+	"github.com/buckleypaul/gust/internal/store"
+	"github.com/buckleypaul/gust/internal/ui"
+	"github.com/buckleypaul/gust/internal/west"
+)
+
+type matrixCellState int
+
+const (
+	matrixPending matrixCellState = iota
+	matrixRunning
+	matrixPassed
+	matrixFailed
+	matrixCancelled
+)
+
+// matrixCell is one project/board build of a matrix run.
+type matrixCell struct {
+	project   string
+	board     string
+	buildDir  string
+	state     matrixCellState
+	requestID string
+	start     time.Time
+	duration  time.Duration
+	size      int64
+	exitCode  int
+	diag      west.DiagnosticParser
+	cancel    context.CancelFunc
+}
+
+// MatrixPage builds every combination of a set of projects and boards, each
+// into its own build directory, a few at a time.
+type MatrixPage struct {
+	store         *store.Store
+	cfg           *config.Config
+	wsRoot        string
+	runner        west.Runner
+	projects      []west.Project
+	boards        []west.Board
+	selProjects   map[string]bool
+	selBoards     map[string]bool
+	column        int // 0: projects, 1: boards
+	projectCursor int
+	boardCursor   int
+	filter        textinput.Model
+	filtering     bool
+	cells         []*matrixCell
+	showGrid      bool
+	runSeq        int
+	width, height int
+	message       string
+}
+
+func NewMatrixPage(s *store.Store, cfg *config.Config, wsRoot string, runners ...west.Runner) *MatrixPage {
+	runner := west.RealRunner()
+	if len(runners) > 0 && runners[0] != nil {
+		runner = runners[0]
+	}
+	ti := textinput.New()
+	ti.Placeholder = "filter boards"
+	ti.Prompt = "/ "
+	ti.CharLimit = 64
+	p := &MatrixPage{
+		store:       s,
+		cfg:         cfg,
+		wsRoot:      wsRoot,
+		runner:      runner,
+		selProjects: make(map[string]bool),
+		selBoards:   make(map[string]bool),
+		filter:      ti,
+	}
+	if cfg.LastProject != "" {
+		p.selProjects[cfg.LastProject] = true
+	}
+	if cfg.DefaultBoard != "" {
+		p.selBoards[cfg.DefaultBoard] = true
+	}
+	return p
+}
+
+// Init loads nothing itself: the project and board lists requested by the
+// Project page are broadcast to every page.
+func (p *MatrixPage) Init() tea.Cmd { return nil }
+
+func (p *MatrixPage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case west.ProjectsLoadedMsg:
+		if msg.Err == nil {
+			p.projects = msg.Projects
+		}
+		return p, nil
+
+	case west.BoardsLoadedMsg:
+		if msg.Err == nil {
+			p.boards = msg.Boards
+		}
+		return p, nil
+
+	case west.CommandOutputMsg:
+		c := p.cellFor(msg.RequestID)
+		if c == nil || c.state != matrixRunning {
+			return p, nil
+		}
+		c.diag.Feed(msg.Line)
+		return p, msg.Next
+
+	case west.CommandResultMsg:
+		c := p.cellFor(msg.RequestID)
+		if c == nil || c.state != matrixRunning {
+			return p, nil
+		}
+		return p, tea.Batch(p.completeCell(c, msg), p.launchPending())
+
+	case buildTickMsg:
+		// Receiving the tick redraws the elapsed time of running cells.
+		if msg.requestID != p.tickID() || !p.running() {
+			return p, nil
+		}
+		return p, buildTick(msg.requestID)
+
+	case tea.KeyMsg:
+		if p.filtering {
+			return p.handleFilterKey(msg)
+		}
+		if p.showGrid {
+			return p.handleGridKey(msg)
+		}
+		return p.handleSelectKey(msg)
+	}
+	return p, nil
+}
+
+func (p *MatrixPage) handleFilterKey(msg tea.KeyMsg) (app.Page, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		p.filtering = false
+		p.filter.Blur()
+		return p, nil
+	case "esc":
+		p.filtering = false
+		p.filter.Blur()
+		p.filter.SetValue("")
+		p.boardCursor = 0
+		return p, nil
+	}
+	var cmd tea.Cmd
+	p.filter, cmd = p.filter.Update(msg)
+	p.boardCursor = 0
+	return p, cmd
+}
+
+func (p *MatrixPage) handleSelectKey(msg tea.KeyMsg) (app.Page, tea.Cmd) {
+	boards := p.filteredBoards()
+	switch msg.String() {
+	case "left":
+		p.column = 0
+	case "right":
+		p.column = 1
+	case "up":
+		if p.column == 0 && p.projectCursor > 0 {
+			p.projectCursor--
+		} else if p.column == 1 && p.boardCursor > 0 {
+			p.boardCursor--
+		}
+	case "down":
+		if p.column == 0 && p.projectCursor < len(p.projects)-1 {
+			p.projectCursor++
+		} else if p.column == 1 && p.boardCursor < len(boards)-1 {
+			p.boardCursor++
+		}
+	case " ", "enter":
+		if p.column == 0 && p.projectCursor < len(p.projects) {
+			path := p.projects[p.projectCursor].Path
+			p.selProjects[path] = !p.selProjects[path]
+		} else if p.column == 1 && p.boardCursor < len(boards) {
+			name := boards[p.boardCursor].Name
+			p.selBoards[name] = !p.selBoards[name]
+		}
+	case "/":
+		p.column = 1
+		p.filtering = true
+		return p, p.filter.Focus()
+	case "g":
+		if len(p.cells) > 0 {
+			p.showGrid = true
+		}
+	case "b":
+		return p, p.start()
+	}
+	return p, nil
+}
+
+func (p *MatrixPage) handleGridKey(msg tea.KeyMsg) (app.Page, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+x":
+		if p.running() {
+			p.cancelAll()
+		}
+	case "esc":
+		if !p.running() {
+			p.showGrid = false
+		}
+	case "b":
+		if !p.running() {
+			return p, p.start()
+		}
+	}
+	return p, nil
+}
+
+// start creates a cell for every selected project/board pair and launches
+// the first batch.
+func (p *MatrixPage) start() tea.Cmd {
+	projects := selected(p.selProjects)
+	boards := selected(p.selBoards)
+	if len(projects) == 0 || len(boards) == 0 {
+		p.message = "Select at least one project and one board (space)"
+		return nil
+	}
+
+	p.runSeq++
+	p.cells = nil
+	for _, proj := range projects {
+		for _, board := range boards {
+			p.cells = append(p.cells, &matrixCell{
+				project:  proj,
+				board:    board,
+				buildDir: west.MatrixBuildDir(p.cfg.BuildDir, proj, board, p.cfg.ActiveProfile),
+			})
+		}
+	}
+	p.showGrid = true
+	p.message = fmt.Sprintf("Building %d combinations, %d at a time", len(p.cells), p.jobs())
+	return tea.Batch(p.launchPending(), buildTick(p.tickID()))
+}
+
+// tickID identifies the progress ticks of the current run.
+func (p *MatrixPage) tickID() string {
+	return fmt.Sprintf("matrix-%d", p.runSeq)
+}
+
+// launchPending starts pending cells until the parallelism limit is reached.
+func (p *MatrixPage) launchPending() tea.Cmd {
+	running := 0
+	for _, c := range p.cells {
+		if c.state == matrixRunning {
+			running++
+		}
+	}
+	var cmds []tea.Cmd
+	for i, c := range p.cells {
+		if running >= p.jobs() {
+			break
+		}
+		if c.state != matrixPending {
+			continue
+		}
+		running++
+		c.state = matrixRunning
+		c.requestID = fmt.Sprintf("matrix-%d-%d", p.runSeq, i)
+		c.start = time.Now()
+		ctx, cancel := context.WithCancel(context.Background())
+		c.cancel = cancel
+		args := west.BuildArgs(west.BuildOptions{
+			Board:    c.board,
+			BuildDir: c.buildDir,
+			Project:  west.ProjectPath(p.wsRoot, c.project),
+		})
+		cmds = append(cmds, west.WithRequestID(c.requestID, p.runner.Run(ctx, "west", args...)))
+	}
+	if running == 0 && len(p.cells) > 0 {
+		p.message = p.summary()
+	}
+	return tea.Batch(cmds...)
+}
+
+// completeCell records a finished cell in build history, returning the
+// command that adds a successful build's provenance to its record.
+func (p *MatrixPage) completeCell(c *matrixCell, msg west.CommandResultMsg) tea.Cmd {
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+	if !msg.Streamed {
+		for _, line := range strings.Split(msg.Output, "\n") {
+			c.diag.Feed(line)
+		}
+	}
+	c.duration = msg.Duration
+	c.exitCode = msg.ExitCode
+	success := msg.ExitCode == 0 && !msg.Cancelled
+	switch {
+	case msg.Cancelled:
+		c.state = matrixCancelled
+	case success:
+		c.state = matrixPassed
+	default:
+		c.state = matrixFailed
+	}
+
+	git := west.ReadGitState(west.ProjectPath(p.wsRoot, c.project))
+	record := west.NewBuildRecord(west.BuildOptions{Board: c.board, BuildDir: c.buildDir}, c.project, "", git, c.start, msg, &c.diag)
+	if err := west.CompleteBuildRecord(&record, p.store, p.wsRoot); err != nil {
+		p.message = fmt.Sprintf("Archiving %s failed: %v", c.board, err)
+	}
+	if success {
+		c.size = record.BinarySize
+		if record.Sizes != nil {
+			c.size = record.Sizes.Flash
+		}
+	}
+	if p.store != nil {
+		if err := p.store.AddBuild(record); err != nil {
+			p.message = fmt.Sprintf("History save failed: %v", err)
+		} else if success {
+			return recordProvenance(p.store, p.runner, p.cfg, p.wsRoot, record.ID, record.BuildDir)
+		}
+	}
+	return nil
+}
+
+func (p *MatrixPage) cancelAll() {
+	for _, c := range p.cells {
+		switch c.state {
+		case matrixPending:
+			c.state = matrixCancelled
+		case matrixRunning:
+			if c.cancel != nil {
+				c.cancel()
+			}
+		}
+	}
+	p.message = "Cancelling matrix..."
+}
+
+func (p *MatrixPage) cellFor(requestID string) *matrixCell {
+	if requestID == "" {
+		return nil
+	}
+	for _, c := range p.cells {
+		if c.requestID == requestID {
+			return c
+		}
+	}
+	return nil
+}
+
+func (p *MatrixPage) running() bool {
+	for _, c := range p.cells {
+		if c.state == matrixPending || c.state == matrixRunning {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *MatrixPage) jobs() int {
+	if p.cfg.MatrixJobs > 0 {
+		return p.cfg.MatrixJobs
+	}
+	return config.DefaultMatrixJobs
+}
+
+func (p *MatrixPage) summary() string {
+	var passed, failed, cancelled int
+	for _, c := range p.cells {
+		switch c.state {
+		case matrixPassed:
+			passed++
+		case matrixFailed:
+			failed++
+		case matrixCancelled:
+			cancelled++
+		}
+	}
+	s := fmt.Sprintf("Matrix done: %d passed, %d failed", passed, failed)
+	if cancelled > 0 {
+		s += fmt.Sprintf(", %d cancelled", cancelled)
+	}
+	return s
+}
+
+func (p *MatrixPage) filteredBoards() []west.Board {
+	q := strings.ToLower(strings.TrimSpace(p.filter.Value()))
+	if q == "" {
+		return p.boards
+	}
+	var out []west.Board
+	for _, b := range p.boards {
+		if strings.Contains(strings.ToLower(b.Name), q) {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// selected returns the keys set in m, sorted.
+func selected(m map[string]bool) []string {
+	var out []string
+	for k, on := range m {
+		if on {
+			out = append(out, k)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (p *MatrixPage) View() string {
+	var b strings.Builder
+	if p.message != "" {
+		b.WriteString("  " + p.message + "\n\n")
+	}
+	if p.showGrid {
+		b.WriteString(p.renderGrid())
+		return b.String()
+	}
+
+	listHeight := max((p.height-10)/2, 3)
+	colWidth := max(p.width-4, 20)
+
+	var projB strings.Builder
+	if len(p.projects) == 0 {
+		projB.WriteString(ui.DimStyle.Render("  Loading projects..."))
+	}
+	start, end := scrollWindow(p.projectCursor, len(p.projects), listHeight)
+	for i := start; i < end; i++ {
+		proj := p.projects[i]
+		projB.WriteString(matrixChoice(proj.Path, p.selProjects[proj.Path], p.column == 0 && i == p.projectCursor, colWidth) + "\n")
+	}
+
+	var boardB strings.Builder
+	if p.filtering || p.filter.Value() != "" {
+		boardB.WriteString(p.filter.View() + "\n")
+	}
+	boards := p.filteredBoards()
+	if len(p.boards) == 0 {
+		boardB.WriteString(ui.DimStyle.Render("  Loading boards..."))
+	}
+	start, end = scrollWindow(p.boardCursor, len(boards), listHeight)
+	for i := start; i < end; i++ {
+		name := boards[i].Name
+		boardB.WriteString(matrixChoice(name, p.selBoards[name], p.column == 1 && i == p.boardCursor, colWidth) + "\n")
+	}
+
+	projTitle := fmt.Sprintf("Projects (%d selected)", len(selected(p.selProjects)))
+	boardTitle := fmt.Sprintf("Boards (%d selected)", len(selected(p.selBoards)))
+	b.WriteString(ui.Panel(projTitle, projB.String(), p.width, 0, p.column == 0))
+	b.WriteString("\n")
+	b.WriteString(ui.Panel(boardTitle, boardB.String(), p.width, 0, p.column == 1))
+	return b.String()
+}
+
+func matrixChoice(label string, on, cursor bool, width int) string {
+	prefix := "  "
+	if cursor {
+		prefix = ui.BoldStyle.Render("> ")
+	}
+	box := "[ ] "
+	if on {
+		box = ui.SuccessBadge("[x]") + " "
+	}
+	return prefix + box + truncate.StringWithTail(label, uint(max(width-8, 8)), "…")
+}
+
+// scrollWindow returns the range of n items to show around cursor.
+func scrollWindow(cursor, n, height int) (int, int) {
+	start := max(cursor-height/2, 0)
+	end := min(start+height, n)
+	start = max(end-height, 0)
+	return start, end
+}
+
+// renderGrid draws projects as rows and boards as columns.
+func (p *MatrixPage) renderGrid() string {
+	var projects, boards []string
+	seenP, seenB := map[string]bool{}, map[string]bool{}
+	cells := make(map[string]*matrixCell)
+	for _, c := range p.cells {
+		if !seenP[c.project] {
+			seenP[c.project] = true
+			projects = append(projects, c.project)
+		}
+		if !seenB[c.board] {
+			seenB[c.board] = true
+			boards = append(boards, c.board)
+		}
+		cells[c.project+"\x00"+c.board] = c
+	}
+
+	const cellWidth = 22
+	projWidth := 24
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("  %-*s", projWidth, ""))
+	for _, board := range boards {
+		b.WriteString(ui.DimStyle.Render(fmt.Sprintf("  %-*s", cellWidth, truncate.StringWithTail(board, cellWidth, "…"))))
+	}
+	b.WriteString("\n")
+	for _, proj := range projects {
+		b.WriteString(fmt.Sprintf("  %-*s", projWidth, truncate.StringWithTail(proj, uint(projWidth), "…")))
+		for _, board := range boards {
+			b.WriteString("  " + padRight(renderMatrixCell(cells[proj+"\x00"+board]), cellWidth))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func renderMatrixCell(c *matrixCell) string {
+	if c == nil {
+		return ""
+	}
+	switch c.state {
+	case matrixPending:
+		return ui.DimStyle.Render("· pending")
+	case matrixRunning:
+		return ui.AccentStyle.Render(fmt.Sprintf("… %s", time.Since(c.start).Round(time.Second)))
+	case matrixPassed:
+		s := "✓ " + c.duration.Round(time.Second).String()
+		if c.size > 0 {
+			s += " " + formatBytes(c.size)
+		}
+		return ui.SuccessBadge(s)
+	case matrixCancelled:
+		return ui.WarningBadge("– cancelled")
+	default:
+		return ui.ErrorBadge(fmt.Sprintf("✗ %s exit %d", c.duration.Round(time.Second), c.exitCode))
+	}
+}
+
+func (p *MatrixPage) Name() string { return "Matrix" }
+
+func (p *MatrixPage) ShortHelp() []key.Binding {
+	switch {
+	case p.filtering:
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "done")),
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "clear")),
+		}
+	case p.showGrid && p.running():
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "cancel all")),
+		}
+	case p.showGrid:
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "rebuild")),
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "selection")),
+		}
+	}
+	help := []key.Binding{
+		key.NewBinding(key.WithKeys("space"), key.WithHelp("space", "select")),
+		key.NewBinding(key.WithKeys("left", "right"), key.WithHelp("←/→", "projects/boards")),
+		key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter boards")),
+		key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "build matrix")),
+	}
+	if len(p.cells) > 0 {
+		help = append(help, key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "last results")))
+	}
+	return help
+}
+
+func (p *MatrixPage) InputCaptured() bool {
+	return p.filtering
+}
+
+func (p *MatrixPage) SetSize(w, h int) {
+	p.width = w
+	p.height = h
+	p.filter.Width = max(w-8, 10)
+}
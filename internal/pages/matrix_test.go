@@ -0,0 +1,147 @@
+package pages
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/buckleypaul/gust/internal/config"
+	"github.com/buckleypaul/gust/internal/store"
+	"github.com/buckleypaul/gust/internal/west"
+)
+
+func init() {
+	// runMatrixCmd runs the progress ticks of a matrix run too.
+	progressInterval = time.Millisecond
+}
+
+// runMatrixCmd executes cmd, expanding batches, and feeds every message back
+// into the page until no commands remain.
+func runMatrixCmd(p *MatrixPage, cmd tea.Cmd) {
+	queue := []tea.Cmd{cmd}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		if c == nil {
+			continue
+		}
+		msg := c()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			queue = append(queue, batch...)
+			continue
+		}
+		_, next := p.Update(msg)
+		queue = append(queue, next)
+	}
+}
+
+func TestMatrixPageBuildsEveryCombinationWithBoundedParallelism(t *testing.T) {
+	st := store.New(t.TempDir())
+	cfg := config.Defaults()
+	cfg.MatrixJobs = 2
+	fake := &fakeRunner{nextMsg: west.CommandResultMsg{ExitCode: 0, Duration: 3 * time.Second}}
+	p := NewMatrixPage(st, &cfg, "/ws", fake)
+	p.SetSize(120, 40)
+	p.Update(west.ProjectsLoadedMsg{Projects: []west.Project{{Name: "a", Path: "apps/a"}, {Name: "b", Path: "apps/b"}}})
+	p.Update(west.BoardsLoadedMsg{Boards: []west.Board{{Name: "native_sim"}, {Name: "nrf52840dk"}, {Name: "qemu_x86"}}})
+
+	// Select both projects and two boards.
+	p.Update(tea.KeyMsg{Type: tea.KeySpace})
+	p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	p.Update(tea.KeyMsg{Type: tea.KeySpace})
+	p.Update(tea.KeyMsg{Type: tea.KeyRight})
+	p.Update(tea.KeyMsg{Type: tea.KeySpace})
+	p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	p.Update(tea.KeyMsg{Type: tea.KeySpace})
+
+	_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	if len(p.cells) != 4 {
+		t.Fatalf("expected 4 cells, got %d", len(p.cells))
+	}
+	if len(fake.runCalls) != 2 {
+		t.Fatalf("expected 2 builds started at once, got %d", len(fake.runCalls))
+	}
+	wantDir := west.MatrixBuildDir("build", "apps/a", "native_sim", "")
+	if got := strings.Join(fake.runCalls[0].args, " "); got != "build -b native_sim -d "+wantDir+" "+filepath.Join("/ws", "apps/a") {
+		t.Fatalf("unexpected first build args %q", got)
+	}
+
+	runMatrixCmd(p, cmd)
+	var buildCalls, manifestCalls int
+	for _, c := range fake.runCalls {
+		switch {
+		case c.name == "west" && c.args[0] == "build":
+			buildCalls++
+		case c.name == "west" && c.args[0] == "manifest":
+			manifestCalls++
+		}
+	}
+	if buildCalls != 4 || p.running() {
+		t.Fatalf("expected all 4 builds to run, got %d", buildCalls)
+	}
+	if manifestCalls != 4 {
+		t.Fatalf("expected provenance collected for each build, got %d manifest calls", manifestCalls)
+	}
+	view := p.View()
+	if !strings.Contains(view, "Matrix done: 4 passed, 0 failed") || !strings.Contains(view, "✓ 3s") {
+		t.Fatalf("expected passing grid, got:\n%s", view)
+	}
+
+	builds, err := st.Builds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(builds) != 4 || builds[3].App != "apps/b" || builds[3].Board != "nrf52840dk" || builds[3].BuildDir != west.MatrixBuildDir("build", "apps/b", "nrf52840dk", "") {
+		t.Fatalf("expected a build record per cell, got %+v", builds)
+	}
+	ids := map[string]bool{}
+	for _, b := range builds {
+		if b.ID == "" || ids[b.ID] {
+			t.Fatalf("expected cells started together to get distinct IDs, got %+v", builds)
+		}
+		ids[b.ID] = true
+	}
+}
+
+func TestMatrixPageCancelAllMarksPendingCells(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.MatrixJobs = 1
+	cfg.LastProject = "apps/a"
+	fake := &fakeRunner{nextMsg: west.CommandResultMsg{ExitCode: 0, Cancelled: true}}
+	p := NewMatrixPage(nil, &cfg, "/ws", fake)
+	p.selBoards = map[string]bool{"native_sim": true, "qemu_x86": true}
+
+	_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	p.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	if p.cells[1].state != matrixCancelled {
+		t.Fatal("expected pending cell to be cancelled")
+	}
+	if fake.runCalls[0].ctx.Err() == nil {
+		t.Fatal("expected running build context to be cancelled")
+	}
+	runMatrixCmd(p, cmd)
+	if len(fake.runCalls) != 1 || !strings.Contains(p.message, "2 cancelled") {
+		t.Fatalf("expected no further builds, got %d calls and message %q", len(fake.runCalls), p.message)
+	}
+}
+
+func TestMatrixPageTicksWhileCellsRun(t *testing.T) {
+	cfg := config.Defaults()
+	p := NewMatrixPage(nil, &cfg, "/ws", &fakeRunner{})
+	p.runSeq = 1
+	p.cells = []*matrixCell{{state: matrixRunning, start: time.Now()}}
+
+	if _, cmd := p.Update(buildTickMsg{requestID: p.tickID()}); cmd == nil {
+		t.Fatal("expected the tick to continue while a cell runs")
+	}
+	if _, cmd := p.Update(buildTickMsg{requestID: "matrix-0"}); cmd != nil {
+		t.Fatal("expected a tick of an earlier run to stop")
+	}
+	p.cells[0].state = matrixPassed
+	if _, cmd := p.Update(buildTickMsg{requestID: p.tickID()}); cmd != nil {
+		t.Fatal("expected the tick to stop once every cell finished")
+	}
+}
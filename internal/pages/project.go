@@ -1,10 +1,12 @@
 package pages
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -33,6 +35,10 @@ const (
 	projFieldRunner
 	projFieldKconfig
 	projFieldPristine // pristine checkbox in build section
+	projFieldSysbuild // sysbuild checkbox in build section
+	projFieldConf     // extra Kconfig fragments picker
+	projFieldOverlay  // extra devicetree overlays picker
+	projFieldSnippets // snippets picker
 	projFieldCMake    // cmake args input in build section
 	projFieldCount
 )
@@ -65,6 +71,12 @@ type ProjectPage struct {
 	boardCursor    int
 	boardListOpen  bool
 
+	// Runner type-ahead, from the build dir's runners.yaml
+	flashRunners    *west.FlashRunners
+	filteredRunners []string
+	runnerCursor    int
+	runnerListOpen  bool
+
 	// Focused field
 	focusedField projField
 
@@ -91,6 +103,7 @@ type ProjectPage struct {
 
 	// Build/flash sub-components
 	build  buildSection
+	watch  watchState
 	flash  flashSection
 	store  *store.Store
 	runner west.Runner
@@ -100,6 +113,17 @@ type ProjectPage struct {
 	viewport        viewport.Model
 	activeOp        string // "Build" or "Flash"
 	activeRequestID string
+	cancel          context.CancelFunc
+
+	// Diagnostics list shown in place of the output panel
+	diagOpen   bool
+	diagCursor int
+
+	// Stale build dir prompt, shown when the build dir's CMakeCache.txt
+	// was configured for a different selection
+	stale            []west.ConfigDiff
+	staleAlt         string // suggested build dir to switch to
+	staleAltExisting bool
 
 	// Metadata
 	width, height int
@@ -142,7 +166,7 @@ func NewProjectPage(s *store.Store, cfg *config.Config, wsRoot string, manifestP
 	}
 
 	runnerIn := textinput.New()
-	runnerIn.Placeholder = "e.g. jlink, openocd"
+	runnerIn.Placeholder = "board default"
 	runnerIn.CharLimit = 128
 	runnerIn.Prompt = ""
 	if cfg.FlashRunner != "" {
@@ -188,16 +212,21 @@ func NewProjectPage(s *store.Store, cfg *config.Config, wsRoot string, manifestP
 		build:         newBuildSection(),
 		viewport:      viewport.New(0, 0),
 	}
+	if prof, ok := cfg.Profile(cfg.ActiveProfile); ok {
+		p.build.applyProfile(prof)
+	}
 
 	return p
 }
 
 func (p *ProjectPage) Init() tea.Cmd {
 	p.loading = true
+	p.loadFlashRunners()
 	return tea.Batch(
-		west.ListBoards(),
+		west.ListBoards(p.runner),
 		west.ListProjects(p.wsRoot, p.manifestPath),
 		p.loadKconfig,
+		west.LoadBuildExtras(p.wsRoot, p.projectAbsPath()),
 	)
 }
 
@@ -212,7 +241,7 @@ func (p *ProjectPage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
 			p.filterProjects()
 			p.cfg.LastProject = msg.Path
 			p.kconfigLoaded = false
-			return p, p.loadKconfig
+			return p, tea.Batch(p.loadKconfig, west.LoadBuildExtras(p.wsRoot, p.projectAbsPath()), p.syncCompileCommands())
 		}
 		return p, nil
 
@@ -220,7 +249,8 @@ func (p *ProjectPage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
 		p.boardInput.SetValue(msg.Board)
 		p.filterBoards()
 		p.loadOverlay()
-		return p, nil
+		p.loadFlashRunners()
+		return p, p.syncCompileCommands()
 
 	case app.ShieldSelectedMsg:
 		p.shieldInput.SetValue(msg.Shield)
@@ -228,10 +258,47 @@ func (p *ProjectPage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
 
 	case app.BuildDirChangedMsg:
 		p.buildDirInput.SetValue(msg.Dir)
+		p.loadFlashRunners()
+		return p, p.syncCompileCommands()
+
+	case watchTickMsg:
+		return p, p.handleWatchTick(msg, time.Now())
+
+	case buildTickMsg:
+		// Receiving the tick redraws the progress line; keep ticking until
+		// the build finishes.
+		if msg.requestID != p.activeRequestID || p.build.state != buildStateRunning {
+			return p, nil
+		}
+		return p, buildTick(msg.requestID)
+
+	case provenanceRecordedMsg:
+		if msg.err != nil {
+			p.message = fmt.Sprintf("Recording build provenance failed: %v", msg.err)
+		}
+		return p, nil
+
+	case compileCommandsSyncedMsg:
+		if msg.err != nil {
+			p.message = fmt.Sprintf("compile_commands.json: %v", msg.err)
+		}
 		return p, nil
 
 	case app.FlashRunnerChangedMsg:
 		p.runnerInput.SetValue(msg.Runner)
+		p.filterRunners()
+		return p, nil
+
+	case reproduceBuildMsg:
+		return p, p.reproduce(msg)
+
+	case app.ProfileSelectedMsg:
+		p.build.applyProfile(msg.Profile)
+		if msg.Profile.Name != "" {
+			p.message = "Switched to profile " + msg.Profile.Name
+		} else {
+			p.message = "No profile"
+		}
 		return p, nil
 
 	case west.BoardsLoadedMsg:
@@ -251,6 +318,12 @@ func (p *ProjectPage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
 		}
 		return p, nil
 
+	case west.BuildExtrasLoadedMsg:
+		if msg.Project == p.projectAbsPath() {
+			p.build.setExtrasOptions(msg.Extras)
+		}
+		return p, nil
+
 	case kconfigLoadedMsg:
 		p.kconfigLoaded = true
 		if msg.err != nil {
@@ -264,25 +337,51 @@ func (p *ProjectPage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
 		p.loadOverlay()
 		return p, nil
 
+	case west.CommandOutputMsg:
+		if p.activeRequestID == "" || msg.RequestID != p.activeRequestID {
+			return p, nil
+		}
+		p.output.WriteString(msg.Line + "\n")
+		if p.activeOp == "Build" {
+			p.build.diag.Feed(msg.Line)
+			p.build.progress.Feed(msg.Line)
+		}
+		p.updateViewportContent()
+		p.viewport.GotoBottom()
+		return p, msg.Next
+
+	case editorClosedMsg:
+		if msg.err != nil {
+			p.message = fmt.Sprintf("Editor failed: %v", msg.err)
+		}
+		return p, nil
+
 	case west.CommandResultMsg:
 		if p.activeRequestID == "" || msg.RequestID != p.activeRequestID {
 			return p, nil
 		}
 		p.activeRequestID = ""
+		p.releaseCancel()
 		board := p.boardInput.Value()
+		var cmd tea.Cmd
 		switch p.activeOp {
 		case "Build":
-			p.build.complete(msg, board, p.projectValue(), p.shieldInput.Value(), p.buildDirInput.Value(), p.store, p.wsRoot, &p.output)
+			id := p.build.complete(msg, board, p.projectValue(), p.shieldInput.Value(), p.buildDir(), p.store, p.wsRoot, &p.output)
 			if msg.ExitCode == 0 {
 				p.cfg.DefaultBoard = board
 				_ = config.Save(*p.cfg, p.wsRoot, false)
 			}
+			// A failed build may still have configured, writing the database
+			// and runners.yaml.
+			p.loadFlashRunners()
+			cmd = tea.Batch(p.syncCompileCommands(), p.completeWatchBuild(msg),
+				recordProvenance(p.store, p.runner, p.cfg, p.wsRoot, id, p.buildDir()))
 		case "Flash":
 			p.flash.complete(msg, board, p.store, &p.output)
 		}
 		p.updateViewportContent()
 		p.viewport.GotoBottom()
-		return p, nil
+		return p, cmd
 
 	case tea.KeyMsg:
 		return p.handleKey(msg)
@@ -294,6 +393,14 @@ func (p *ProjectPage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
 func (p *ProjectPage) handleKey(msg tea.KeyMsg) (app.Page, tea.Cmd) {
 	keyStr := msg.String()
 
+	if p.diagOpen {
+		return p.handleDiagnosticsKey(keyStr)
+	}
+
+	if p.stale != nil {
+		return p.handleStaleKey(keyStr)
+	}
+
 	// Add mode: forward to addInput, intercept enter/esc
 	if p.adding {
 		switch keyStr {
@@ -437,16 +544,42 @@ func (p *ProjectPage) handleKey(msg tea.KeyMsg) (app.Page, tea.Cmd) {
 		}
 	}
 
+	// Extras picker navigation when open
+	if k := p.focusedField.extraKind(); k != extraNone && p.build.extras[k].open {
+		if p.build.extras[k].handleKey(keyStr) {
+			return p, nil
+		}
+	}
+
 	// Global form keys
 	switch keyStr {
 	case "ctrl+b":
 		return p, p.triggerBuild()
+	case "ctrl+e":
+		if p.output.Len() > 0 && len(p.build.diag.Diagnostics()) > 0 {
+			p.diagOpen = true
+			p.diagCursor = 0
+		}
+		return p, nil
+	case "ctrl+o":
+		p.flash.cycleDomain()
+		return p, nil
+	case "ctrl+t":
+		return p, p.toggleWatch()
+	case "ctrl+x":
+		if p.activeRequestID != "" && p.cancel != nil {
+			p.cancel()
+			p.output.WriteString("\nCancelling " + strings.ToLower(p.activeOp) + "...\n")
+			p.updateViewportContent()
+			p.viewport.GotoBottom()
+		}
+		return p, nil
 	case "f":
 		if !p.InputCaptured() {
 			return p, p.triggerFlash()
 		}
 	case "esc":
-		if p.output.Len() > 0 && !p.adding && !p.editing && !p.searchInput.Focused() {
+		if p.output.Len() > 0 && p.activeRequestID == "" && !p.adding && !p.editing && !p.searchInput.Focused() {
 			p.output.Reset()
 			p.viewport.SetContent("")
 			p.activeOp = ""
@@ -560,7 +693,24 @@ func (p *ProjectPage) handleKey(msg tea.KeyMsg) (app.Page, tea.Cmd) {
 	case projFieldRunner:
 		switch keyStr {
 		case "enter":
-			runner := p.runnerInput.Value()
+			runner := strings.TrimSpace(p.runnerInput.Value())
+			if p.flashRunners != nil {
+				switch {
+				case p.runnerListOpen && len(p.filteredRunners) > 0:
+					runner = p.filteredRunners[p.runnerCursor]
+				case runner == "" || p.flashRunners.Supports(runner):
+				case len(p.filteredRunners) == 0:
+					p.message = p.flashRunners.Validate(runner).Error()
+					return p, nil
+				default:
+					p.runnerListOpen = true
+					p.message = "Choose a runner with ↑/↓ and enter."
+					return p, nil
+				}
+			}
+			p.runnerInput.SetValue(runner)
+			p.runnerListOpen = false
+			p.filterRunners()
 			p.cfg.FlashRunner = runner
 			if err := config.Save(*p.cfg, p.wsRoot, false); err != nil {
 				p.message = fmt.Sprintf("Runner set, but config save failed: %v", err)
@@ -569,14 +719,29 @@ func (p *ProjectPage) handleKey(msg tea.KeyMsg) (app.Page, tea.Cmd) {
 				return app.FlashRunnerChangedMsg{Runner: runner}
 			}
 		case "up":
-			p.advanceField(-1)
+			if !p.runnerListOpen {
+				p.advanceField(-1)
+			} else if p.runnerCursor > 0 {
+				p.runnerCursor--
+			} else {
+				p.runnerListOpen = false
+			}
 			return p, nil
 		case "down":
-			p.advanceField(1)
+			if p.runnerListOpen && p.runnerCursor < len(p.filteredRunners)-1 {
+				p.runnerCursor++
+			} else if !p.runnerListOpen && len(p.filteredRunners) > 0 {
+				p.runnerListOpen = true
+				p.runnerCursor = 0
+			} else {
+				p.advanceField(1)
+			}
 			return p, nil
 		}
 		var cmd tea.Cmd
 		p.runnerInput, cmd = p.runnerInput.Update(msg)
+		p.filterRunners()
+		p.runnerListOpen = p.runnerInput.Value() != "" && len(p.filteredRunners) > 0
 		return p, cmd
 
 	case projFieldKconfig:
@@ -640,6 +805,33 @@ func (p *ProjectPage) handleKey(msg tea.KeyMsg) (app.Page, tea.Cmd) {
 			return p, nil
 		}
 
+	case projFieldSysbuild:
+		switch keyStr {
+		case "up":
+			p.advanceField(-1)
+			return p, nil
+		case "down":
+			p.advanceField(1)
+			return p, nil
+		case " ", "enter":
+			p.build.sysbuild = !p.build.sysbuild
+			return p, nil
+		}
+
+	case projFieldConf, projFieldOverlay, projFieldSnippets:
+		switch keyStr {
+		case "up":
+			p.advanceField(-1)
+			return p, nil
+		case "down":
+			p.advanceField(1)
+			return p, nil
+		case " ", "enter":
+			picker := &p.build.extras[p.focusedField.extraKind()]
+			picker.open = len(picker.options) > 0
+			return p, nil
+		}
+
 	case projFieldCMake:
 		switch keyStr {
 		case "enter":
@@ -665,6 +857,50 @@ func (p *ProjectPage) handleKey(msg tea.KeyMsg) (app.Page, tea.Cmd) {
 	return p, nil
 }
 
+// handleDiagnosticsKey navigates the diagnostics list and opens entries in
+// the user's editor.
+func (p *ProjectPage) handleDiagnosticsKey(keyStr string) (app.Page, tea.Cmd) {
+	diags := p.build.diag.Diagnostics()
+	switch keyStr {
+	case "up":
+		if p.diagCursor > 0 {
+			p.diagCursor--
+		}
+	case "down":
+		if p.diagCursor < len(diags)-1 {
+			p.diagCursor++
+		}
+	case "enter":
+		if p.diagCursor < len(diags) {
+			cmd, err := openInEditor(diags[p.diagCursor], p.diagnosticDirs()...)
+			if err != nil {
+				p.message = err.Error()
+				return p, nil
+			}
+			p.message = ""
+			return p, cmd
+		}
+	case "esc", "ctrl+e":
+		p.diagOpen = false
+	}
+	return p, nil
+}
+
+// diagnosticDirs lists the directories relative diagnostic paths are
+// resolved against: the build dir, the project, the workspace and Zephyr.
+func (p *ProjectPage) diagnosticDirs() []string {
+	buildDir := p.buildDir()
+	if buildDir == "" {
+		buildDir = config.DefaultBuildDir
+	}
+	return []string{
+		west.ProjectPath(p.wsRoot, buildDir),
+		p.projectAbsPath(),
+		p.wsRoot,
+		filepath.Join(p.wsRoot, "zephyr"),
+	}
+}
+
 // selectProject confirms a project selection, saves config, reloads kconfig,
 // and broadcasts ProjectSelectedMsg.
 func (p *ProjectPage) selectProject(path string) tea.Cmd {
@@ -679,10 +915,25 @@ func (p *ProjectPage) selectProject(path string) tea.Cmd {
 	p.kconfigLoaded = false
 	return tea.Batch(
 		p.loadKconfig,
+		west.LoadBuildExtras(p.wsRoot, p.projectAbsPath()),
+		p.syncCompileCommands(),
 		func() tea.Msg { return app.ProjectSelectedMsg{Path: path} },
 	)
 }
 
+// extraKind maps a picker field to the build extras it selects.
+func (f projField) extraKind() extraKind {
+	switch f {
+	case projFieldConf:
+		return extraConf
+	case projFieldOverlay:
+		return extraOverlay
+	case projFieldSnippets:
+		return extraSnippet
+	}
+	return extraNone
+}
+
 func (p *ProjectPage) advanceField(dir int) {
 	p.blurCurrent()
 	p.focusedField = projField((int(p.focusedField) + int(projFieldCount) + dir) % int(projFieldCount))
@@ -692,6 +943,9 @@ func (p *ProjectPage) advanceField(dir int) {
 	if p.focusedField != projFieldBoard {
 		p.boardListOpen = false
 	}
+	if p.focusedField != projFieldRunner {
+		p.runnerListOpen = false
+	}
 	p.focusCurrent()
 }
 
@@ -704,6 +958,7 @@ func (p *ProjectPage) blurAll() {
 	p.build.cmakeInput.Blur()
 	p.projectListOpen = false
 	p.boardListOpen = false
+	p.runnerListOpen = false
 }
 
 func (p *ProjectPage) blurCurrent() {
@@ -735,6 +990,7 @@ func (p *ProjectPage) focusCurrent() {
 		p.buildDirInput.Focus()
 	case projFieldRunner:
 		p.runnerInput.Focus()
+		p.loadFlashRunners()
 	case projFieldCMake:
 		p.build.cmakeInput.Focus()
 	}
@@ -747,30 +1003,69 @@ func (p *ProjectPage) projectValue() string {
 	return p.projectPath
 }
 
+// buildDir expands the Build Dir setting for the current project, board and
+// profile.
+func (p *ProjectPage) buildDir() string {
+	return west.ExpandBuildDir(p.buildDirInput.Value(), p.projectValue(), p.boardInput.Value(), p.build.profile)
+}
+
+// triggerBuild starts a build, first asking what to do if the build dir is
+// configured for a different selection.
 func (p *ProjectPage) triggerBuild() tea.Cmd {
-	board := p.boardInput.Value()
-	if board == "" {
+	if p.busy() {
+		return nil
+	}
+	if p.boardInput.Value() == "" {
 		p.message = "Board is required. Set a board above."
 		return nil
 	}
+	if !p.build.pristine {
+		if diffs := p.staleConfig(p.buildDir()); len(diffs) > 0 {
+			p.stale = diffs
+			p.staleAlt, p.staleAltExisting = p.alternateBuildDir(p.buildDir())
+			return nil
+		}
+	}
+	return p.startBuild()
+}
+
+func (p *ProjectPage) startBuild() tea.Cmd {
+	board := p.boardInput.Value()
+	// Any build covers the changes watch mode is waiting on.
+	p.watch.pending, p.watch.blocked = nil, ""
 	p.output.Reset()
+	p.diagOpen = false
 	p.activeOp = "Build"
+	ctx := p.newContext()
 	requestID, cmd := p.build.start(
-		p.wsRoot, p.projectValue(), board,
-		p.shieldInput.Value(), p.buildDirInput.Value(),
+		ctx, p.wsRoot, p.projectValue(), board,
+		p.shieldInput.Value(), p.buildDir(),
 		p.runner, &p.output,
 	)
+	p.build.expected = expectedBuildDuration(p.store, p.projectValue(), board, p.build.ranPristine)
 	p.activeRequestID = requestID
 	p.updateViewportContent()
-	return cmd
+	return tea.Batch(cmd, buildTick(requestID))
 }
 
 func (p *ProjectPage) triggerFlash() tea.Cmd {
-	p.flash.refreshLastBuild(p.store)
+	if p.busy() {
+		return nil
+	}
+	p.loadFlashRunners()
+	if p.flashRunners != nil {
+		if err := p.flashRunners.Validate(p.runnerInput.Value()); err != nil {
+			p.message = err.Error()
+			return nil
+		}
+	}
+	p.flash.refreshLastBuild(p.store, p.buildDir())
 	p.output.Reset()
+	p.diagOpen = false
 	p.activeOp = "Flash"
+	ctx := p.newContext()
 	requestID, cmd := p.flash.start(
-		p.buildDirInput.Value(), p.runnerInput.Value(),
+		ctx, p.buildDir(), p.runnerInput.Value(),
 		p.runner, &p.output,
 	)
 	p.activeRequestID = requestID
@@ -778,6 +1073,36 @@ func (p *ProjectPage) triggerFlash() tea.Cmd {
 	return cmd
 }
 
+// busy reports whether a build or flash is still running, telling the user
+// so. Starting another would share its build dir, and cancelling it
+// silently would lose its result.
+func (p *ProjectPage) busy() bool {
+	if p.activeRequestID == "" {
+		return false
+	}
+	p.message = fmt.Sprintf("%s still running; press ctrl+x to cancel it first.", p.activeOp)
+	return true
+}
+
+// newContext returns a context for the next build or flash, releasing the
+// previous one.
+func (p *ProjectPage) newContext() context.Context {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	return ctx
+}
+
+// releaseCancel frees the context of a finished operation.
+func (p *ProjectPage) releaseCancel() {
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+}
+
 func (p *ProjectPage) updateViewportContent() {
 	if p.viewport.Width > 0 {
 		content := p.output.String()
@@ -795,7 +1120,7 @@ func (p *ProjectPage) updateViewportContent() {
 }
 
 func (p *ProjectPage) viewConfig(width, height int) string {
-	p.flash.refreshLastBuild(p.store)
+	p.flash.refreshLastBuild(p.store, p.buildDir())
 
 	var b strings.Builder
 
@@ -850,10 +1175,24 @@ func (p *ProjectPage) viewConfig(width, height int) string {
 
 	// Build Dir input
 	b.WriteString("  " + renderLabel("Build Dir", projFieldBuildDir) + " " + p.buildDirInput.View() + "\n")
+	if dir := p.buildDir(); dir != p.buildDirInput.Value() {
+		b.WriteString(strings.Repeat(" ", lw+3) + ui.DimStyle.Render("→ "+dir) + "\n")
+	}
 
 	// Flash Runner input
 	b.WriteString("  " + renderLabel("Runner", projFieldRunner) + " " + p.runnerInput.View() + "\n")
 
+	// Runner dropdown
+	if p.focusedField == projFieldRunner && len(p.filteredRunners) > 0 {
+		b.WriteString(p.renderRunnerDropdown(inputWidth))
+	} else if p.flashRunners != nil {
+		if err := p.flashRunners.Validate(p.runnerInput.Value()); err != nil {
+			b.WriteString(strings.Repeat(" ", lw+3) + ui.WarningBadge("!") + " " + err.Error() + "\n")
+		} else if p.runnerInput.Value() == "" && p.flashRunners.FlashRunner != "" {
+			b.WriteString(strings.Repeat(" ", lw+3) + ui.DimStyle.Render("→ "+p.flashRunners.FlashRunner) + "\n")
+		}
+	}
+
 	b.WriteString("\n")
 
 	// -- Kconfig section --
@@ -957,7 +1296,12 @@ func (p *ProjectPage) viewConfig(width, height int) string {
 	b.WriteString("\n")
 
 	// Build section
-	b.WriteString(p.build.viewSection(width, p.focusedField == projFieldPristine, p.focusedField == projFieldCMake))
+	if p.stale != nil {
+		b.WriteString(p.viewStale(width))
+		b.WriteString("\n")
+	}
+	b.WriteString(p.build.viewSection(width, p.focusedField == projFieldPristine, p.focusedField == projFieldSysbuild, p.focusedField == projFieldCMake, p.focusedField.extraKind()))
+	b.WriteString(p.watch.view())
 	b.WriteString("\n")
 
 	// Flash section
@@ -965,7 +1309,7 @@ func (p *ProjectPage) viewConfig(width, height int) string {
 	b.WriteString("\n")
 
 	// Help bar
-	b.WriteString(ui.DimStyle.Render("  ↑/↓: navigate  /: search  e: edit  a: add  d: delete  space: toggle pristine  ctrl+b: build  f: flash"))
+	b.WriteString(ui.DimStyle.Render("  ↑/↓: navigate  /: search  e: edit  a: add  d: delete  space: toggle pristine  ctrl+b: build  f: flash  ctrl+x: cancel"))
 
 	return b.String()
 }
@@ -991,7 +1335,13 @@ func (p *ProjectPage) View() string {
 		if p.activeOp != "" {
 			label = p.activeOp + " Output"
 		}
-		outputPanel := ui.Panel(label, p.viewport.View(), p.width, outputHeight, false)
+		content := p.viewport.View()
+		if p.diagOpen {
+			diags := p.build.diag.Diagnostics()
+			label = fmt.Sprintf("Diagnostics (%d)", len(diags))
+			content = renderDiagnostics(diags, p.diagCursor, p.width, p.viewport.Height)
+		}
+		outputPanel := ui.Panel(label, content, p.width, outputHeight, false)
 		return lipgloss.JoinVertical(lipgloss.Left,
 			p.viewConfig(p.width, configHeight),
 			outputPanel,
@@ -1092,6 +1442,57 @@ func (p *ProjectPage) renderBoardDropdown(width int) string {
 	return b.String()
 }
 
+// renderRunnerDropdown lists the board's runners matching the Runner input,
+// marking the default flash and debug runners.
+func (p *ProjectPage) renderRunnerDropdown(width int) string {
+	var b strings.Builder
+	padding := strings.Repeat(" ", 9+3)
+
+	count := len(p.filteredRunners)
+	visible := count
+	if visible > maxDropdownItems {
+		visible = maxDropdownItems
+	}
+
+	start := 0
+	if p.runnerListOpen && p.runnerCursor >= visible {
+		start = p.runnerCursor - visible + 1
+	}
+	end := start + visible
+
+	selectedStyle := lipgloss.NewStyle().Foreground(ui.Primary).Bold(true)
+
+	for i := start; i < end; i++ {
+		name := p.filteredRunners[i]
+		var tags []string
+		if name == p.flashRunners.FlashRunner {
+			tags = append(tags, "flash default")
+		}
+		if name == p.flashRunners.DebugRunner {
+			tags = append(tags, "debug default")
+		}
+		if len(name) > width {
+			name = name[:width]
+		}
+		prefix := "  "
+		if p.runnerListOpen && i == p.runnerCursor {
+			prefix = selectedStyle.Render("> ")
+			name = selectedStyle.Render(name)
+		} else {
+			name = ui.DimStyle.Render(name)
+		}
+		if len(tags) > 0 {
+			name += " " + ui.DimStyle.Render("("+strings.Join(tags, ", ")+")")
+		}
+		b.WriteString(padding + prefix + name + "\n")
+	}
+
+	countStr := fmt.Sprintf("(%d/%d runners)", visible, count)
+	b.WriteString(padding + "  " + ui.DimStyle.Render(countStr) + "\n")
+
+	return b.String()
+}
+
 func (p *ProjectPage) Name() string { return "Project" }
 
 func (p *ProjectPage) ShortHelp() []key.Binding {
@@ -1113,7 +1514,31 @@ func (p *ProjectPage) ShortHelp() []key.Binding {
 			key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
 		}
 	}
-	return []key.Binding{
+	if k := p.focusedField.extraKind(); k != extraNone && p.build.extras[k].open {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("up", "down"), key.WithHelp("↑/↓", "navigate")),
+			key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "toggle")),
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "done")),
+		}
+	}
+	if p.stale != nil {
+		bindings := []key.Binding{key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "build pristine"))}
+		if p.staleAlt != "" {
+			bindings = append(bindings, key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "switch dir")))
+		}
+		return append(bindings,
+			key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "build anyway")),
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
+		)
+	}
+	if p.diagOpen {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("up", "down"), key.WithHelp("↑/↓", "navigate")),
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "open in editor")),
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back to output")),
+		}
+	}
+	bindings := []key.Binding{
 		key.NewBinding(key.WithKeys("up", "down"), key.WithHelp("↑/↓", "navigate")),
 		key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
 		key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit")),
@@ -1121,13 +1546,22 @@ func (p *ProjectPage) ShortHelp() []key.Binding {
 		key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete")),
 		key.NewBinding(key.WithKeys("ctrl+b"), key.WithHelp("ctrl+b", "build")),
 		key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "flash")),
+		key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "watch")),
+	}
+	if len(p.flash.domains()) > 0 {
+		bindings = append(bindings, key.NewBinding(key.WithKeys("ctrl+o"), key.WithHelp("ctrl+o", "flash domain")))
+	}
+	if p.activeRequestID != "" {
+		bindings = append(bindings, key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "cancel")))
 	}
+	return bindings
 }
 
 func (p *ProjectPage) InputCaptured() bool {
 	return p.projectInput.Focused() || p.boardInput.Focused() || p.shieldInput.Focused() ||
 		p.buildDirInput.Focused() || p.runnerInput.Focused() ||
-		p.editing || p.adding || p.searchInput.Focused() || p.build.cmakeInput.Focused()
+		p.editing || p.adding || p.searchInput.Focused() || p.build.cmakeInput.Focused() ||
+		p.diagOpen || p.stale != nil || (p.focusedField.extraKind() != extraNone && p.build.extras[p.focusedField.extraKind()].open)
 }
 
 func (p *ProjectPage) SetSize(w, h int) {
@@ -1207,6 +1641,36 @@ func (p *ProjectPage) filterBoards() {
 	}
 }
 
+// loadFlashRunners reads the runners the selected build dir's board
+// supports. They are unknown until the build dir has been configured.
+func (p *ProjectPage) loadFlashRunners() {
+	p.flashRunners = nil
+	if runners, err := west.ReadFlashRunners(p.wsRoot, p.buildDir()); err == nil {
+		p.flashRunners = runners
+	}
+	p.filterRunners()
+}
+
+// filterRunners narrows the runner list based on the current input.
+func (p *ProjectPage) filterRunners() {
+	p.filteredRunners = nil
+	if p.flashRunners == nil {
+		return
+	}
+	query := strings.ToLower(strings.TrimSpace(p.runnerInput.Value()))
+	for _, r := range p.flashRunners.Runners {
+		if strings.Contains(strings.ToLower(r), query) {
+			p.filteredRunners = append(p.filteredRunners, r)
+		}
+	}
+	if p.runnerCursor >= len(p.filteredRunners) {
+		p.runnerCursor = len(p.filteredRunners) - 1
+	}
+	if p.runnerCursor < 0 {
+		p.runnerCursor = 0
+	}
+}
+
 // filterKconfig narrows the Kconfig list based on the search input.
 func (p *ProjectPage) filterKconfig() {
 	query := strings.ToLower(p.searchInput.Value())
@@ -9,6 +9,7 @@ import (
 
 	"github.com/buckleypaul/gust/internal/app"
 	"github.com/buckleypaul/gust/internal/config"
+	"github.com/buckleypaul/gust/internal/store"
 	"github.com/buckleypaul/gust/internal/west"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -268,6 +269,47 @@ func TestProjectPageCtrlBTriggersBuild(t *testing.T) {
 	}
 }
 
+func TestProjectPageRefusesToStartWhileRunning(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.DefaultBoard = "nrf52840dk"
+	fake := &fakeRunner{}
+	p := NewProjectPage(nil, &cfg, t.TempDir(), "", fake)
+
+	if cmd := p.triggerBuild(); cmd == nil || p.activeRequestID == "" {
+		t.Fatal("expected the first build to start")
+	}
+	running := p.activeRequestID
+	if cmd := p.triggerFlash(); cmd != nil {
+		t.Fatal("expected flash to be refused while the build runs")
+	}
+	if cmd := p.triggerBuild(); cmd != nil {
+		t.Fatal("expected a second build to be refused while the first runs")
+	}
+	if len(fake.runCalls) != 1 || p.activeRequestID != running || !strings.Contains(p.message, "Build still running") {
+		t.Fatalf("expected the running build kept, calls=%d message=%q", len(fake.runCalls), p.message)
+	}
+}
+
+func TestProjectPageTicksWhileBuildRuns(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.DefaultBoard = "nrf52840dk"
+	cfg.CompileCommands = config.CompileCommandsOff
+	p := NewProjectPage(nil, &cfg, t.TempDir(), "", &fakeRunner{})
+	p.triggerBuild()
+	id := p.activeRequestID
+
+	if _, cmd := p.Update(buildTickMsg{requestID: id}); cmd == nil {
+		t.Fatal("expected the progress tick to continue while the build runs")
+	}
+	if _, cmd := p.Update(buildTickMsg{requestID: "stale"}); cmd != nil {
+		t.Fatal("expected a tick of another build to stop")
+	}
+	p.Update(west.CommandResultMsg{RequestID: id})
+	if _, cmd := p.Update(buildTickMsg{requestID: id}); cmd != nil {
+		t.Fatal("expected the progress tick to stop once the build finished")
+	}
+}
+
 func TestProjectPageFTriggerFlash(t *testing.T) {
 	cfg := config.Defaults()
 	cfg.BuildDir = "build-custom"
@@ -295,6 +337,104 @@ func TestProjectPageFTriggerFlash(t *testing.T) {
 	}
 }
 
+func writeRunnersYAML(t *testing.T, wsRoot string) {
+	t.Helper()
+	path := filepath.Join(wsRoot, "build", "zephyr", "runners.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	yaml := "runners:\n- nrfjprog\n- jlink\nflash-runner: nrfjprog\ndebug-runner: jlink\n"
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProjectPageRunnerTypeAheadPicksSupportedRunner(t *testing.T) {
+	wsRoot := t.TempDir()
+	writeRunnersYAML(t, wsRoot)
+	cfg := config.Defaults()
+	p := NewProjectPage(nil, &cfg, wsRoot, "")
+	p.buildDirInput.SetValue("build")
+	p.focusedField = projFieldRunner
+	p.focusCurrent()
+
+	if view := p.View(); !strings.Contains(view, "nrfjprog (flash default)") || !strings.Contains(view, "jlink (debug default)") {
+		t.Fatalf("expected runners from runners.yaml in the dropdown, got:\n%s", view)
+	}
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("jl")})
+	if len(p.filteredRunners) != 1 {
+		t.Fatalf("expected typing to filter the runners, got %v", p.filteredRunners)
+	}
+	_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil || p.runnerInput.Value() != "jlink" || cfg.FlashRunner != "jlink" {
+		t.Fatalf("expected enter to pick jlink, got input %q, config %q", p.runnerInput.Value(), cfg.FlashRunner)
+	}
+
+	p.runnerInput.SetValue("stlink")
+	p.filterRunners()
+	if _, cmd := p.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || cfg.FlashRunner != "jlink" {
+		t.Fatalf("expected an unsupported runner to be refused, config %q", cfg.FlashRunner)
+	}
+	if !strings.Contains(p.message, "not supported") {
+		t.Fatalf("expected a message about the runner, got %q", p.message)
+	}
+}
+
+func TestProjectPageRunnerDropdownCursor(t *testing.T) {
+	wsRoot := t.TempDir()
+	writeRunnersYAML(t, wsRoot)
+	cfg := config.Defaults()
+	p := NewProjectPage(nil, &cfg, wsRoot, "")
+	p.buildDirInput.SetValue("build")
+	p.focusedField = projFieldRunner
+	p.focusCurrent()
+
+	// A partial match is not replaced silently; enter opens the list.
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	p.runnerListOpen = false
+	if _, cmd := p.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || cfg.FlashRunner != "" || !p.runnerListOpen {
+		t.Fatalf("expected enter on a partial runner to open the list, config %q", cfg.FlashRunner)
+	}
+
+	p.runnerInput.SetValue("")
+	p.filterRunners()
+	p.runnerListOpen = false
+	p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if !p.runnerListOpen || p.runnerCursor != 1 || p.focusedField != projFieldRunner {
+		t.Fatalf("expected down to move through the runners, open=%v cursor=%d", p.runnerListOpen, p.runnerCursor)
+	}
+	if view := p.View(); !strings.Contains(view, "> jlink") {
+		t.Fatalf("expected the cursor on jlink, got:\n%s", view)
+	}
+	p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if p.runnerInput.Value() != "jlink" || cfg.FlashRunner != "jlink" || p.runnerListOpen {
+		t.Fatalf("expected enter to pick the highlighted runner, got input %q, config %q", p.runnerInput.Value(), cfg.FlashRunner)
+	}
+}
+
+func TestProjectPageFlashRejectsUnsupportedRunner(t *testing.T) {
+	wsRoot := t.TempDir()
+	writeRunnersYAML(t, wsRoot)
+	cfg := config.Defaults()
+	cfg.FlashRunner = "nrfjprg"
+	fake := &fakeRunner{nextMsg: west.CommandResultMsg{ExitCode: 0}}
+	p := NewProjectPage(nil, &cfg, wsRoot, "", fake)
+	p.buildDirInput.SetValue("build")
+
+	if _, cmd := p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}}); cmd != nil {
+		t.Fatal("expected no flash with an unsupported runner")
+	}
+	if len(fake.runCalls) != 0 || !strings.Contains(p.message, "use one of nrfjprog, jlink") {
+		t.Fatalf("expected the flash to be refused with the supported runners, got %q", p.message)
+	}
+
+	p.runnerInput.SetValue("")
+	if _, cmd := p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}}); cmd == nil {
+		t.Fatal("expected the board default runner to flash")
+	}
+}
+
 func TestProjectPageBuildDirChangedMsgUpdatesInput(t *testing.T) {
 	wsRoot := t.TempDir()
 	cfg := config.Defaults()
@@ -342,3 +482,553 @@ func TestProjectPageIgnoresForeignCommandResult(t *testing.T) {
 		t.Fatal("expected no output for foreign result")
 	}
 }
+
+func TestProjectPageAppendsStreamedOutput(t *testing.T) {
+	cfg := config.Defaults()
+	p := NewProjectPage(nil, &cfg, t.TempDir(), "")
+	p.activeRequestID = "build-1"
+	p.activeOp = "Build"
+
+	next := func() tea.Msg { return nil }
+	page, cmd := p.Update(west.CommandOutputMsg{RequestID: "build-1", Line: "[1/10] Building C object", Next: next})
+	p = page.(*ProjectPage)
+	if cmd == nil {
+		t.Fatal("expected continuation command for owned output")
+	}
+	if !strings.Contains(p.output.String(), "[1/10] Building C object") {
+		t.Fatalf("expected streamed line in output, got %q", p.output.String())
+	}
+
+	page, cmd = p.Update(west.CommandOutputMsg{RequestID: "build-2", Line: "foreign", Next: next})
+	p = page.(*ProjectPage)
+	if cmd != nil {
+		t.Fatal("expected no continuation for foreign output")
+	}
+	if strings.Contains(p.output.String(), "foreign") {
+		t.Fatal("expected foreign output to be ignored")
+	}
+
+	page, _ = p.Update(west.CommandResultMsg{RequestID: "build-1", Output: "[1/10] Building C object\n", Streamed: true})
+	p = page.(*ProjectPage)
+	if strings.Count(p.output.String(), "[1/10] Building C object") != 1 {
+		t.Fatalf("expected streamed output not to be repeated, got %q", p.output.String())
+	}
+}
+
+func TestProjectPageCtrlXCancelsBuildAndRecordsCancelled(t *testing.T) {
+	cfg := config.Defaults()
+	st := store.New(t.TempDir())
+	fake := &fakeRunner{nextMsg: west.CommandResultMsg{}}
+
+	p := NewProjectPage(st, &cfg, t.TempDir(), "", fake)
+	p.boardInput.SetValue("nrf52840dk")
+	p = updateProjectPage(p, tea.KeyMsg{Type: tea.KeyCtrlB})
+	if len(fake.runCalls) != 1 {
+		t.Fatalf("expected 1 run call, got %d", len(fake.runCalls))
+	}
+	ctx := fake.runCalls[0].ctx
+	requestID := p.activeRequestID
+
+	p = updateProjectPage(p, tea.KeyMsg{Type: tea.KeyCtrlX})
+	if ctx.Err() == nil {
+		t.Fatal("expected build context to be cancelled")
+	}
+
+	p = updateProjectPage(p, west.CommandResultMsg{RequestID: requestID, ExitCode: -1, Cancelled: true})
+	if !strings.Contains(p.output.String(), "Build cancelled") {
+		t.Fatalf("expected cancelled status in output, got %q", p.output.String())
+	}
+	builds, err := st.Builds()
+	if err != nil {
+		t.Fatalf("Builds() error: %v", err)
+	}
+	if len(builds) != 1 || !builds[0].Cancelled || builds[0].Success {
+		t.Fatalf("expected one cancelled build record, got %+v", builds)
+	}
+}
+
+func TestProjectPageReplaysBoardsBuildAndFlash(t *testing.T) {
+	wsRoot := t.TempDir()
+	cfg := config.Defaults()
+	st := store.New(filepath.Join(wsRoot, ".gust"))
+	replay := newReplayRunner(t, "project_blinky", wsRoot)
+
+	p := NewProjectPage(st, &cfg, wsRoot, "", replay)
+	p = updateProjectPage(p, west.ListBoards(replay)())
+	if len(p.boards) != 4 || p.boards[0].Name != "nrf52840dk" {
+		t.Fatalf("expected 4 replayed boards, got %+v", p.boards)
+	}
+
+	p.projectPath = filepath.Join("apps", "blinky")
+	p.boardInput.SetValue("nrf52840dk")
+	p.buildDirInput.SetValue("build")
+	page, cmd := p.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	p = followCommand(t, page, cmd).(*ProjectPage)
+
+	out := p.output.String()
+	if !strings.Contains(out, "-- Application: "+filepath.Join(wsRoot, "apps", "blinky")) {
+		t.Fatalf("expected workspace path restored in output, got %q", out)
+	}
+	if strings.Count(out, "[131/132] Linking C executable zephyr/zephyr.elf") != 1 {
+		t.Fatalf("expected each streamed line once, got %q", out)
+	}
+	if !strings.Contains(out, "Build success in 18.734s") {
+		t.Fatalf("expected replayed exit status and duration, got %q", out)
+	}
+	builds, err := st.Builds()
+	if err != nil {
+		t.Fatalf("Builds() error: %v", err)
+	}
+	if len(builds) != 1 || !builds[0].Success || builds[0].Duration != "18.734s" {
+		t.Fatalf("expected one successful 18.734s build record, got %+v", builds)
+	}
+
+	page, cmd = p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	p = followCommand(t, page, cmd).(*ProjectPage)
+	if !strings.Contains(p.output.String(), "Programmed") {
+		t.Fatalf("expected replayed flash output, got %q", p.output.String())
+	}
+	flashes, err := st.Flashes()
+	if err != nil {
+		t.Fatalf("Flashes() error: %v", err)
+	}
+	if len(flashes) != 1 || !flashes[0].Success {
+		t.Fatalf("expected one successful flash record, got %+v", flashes)
+	}
+
+	if unused := replay.Unused(); len(unused) != 0 {
+		t.Fatalf("expected whole transcript to be replayed, %d entries left", len(unused))
+	}
+}
+
+func TestProjectPageCollectsDiagnosticsAndOpensList(t *testing.T) {
+	wsRoot := t.TempDir()
+	src := filepath.Join(wsRoot, "apps", "blinky", "src")
+	if err := os.MkdirAll(src, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "main.c"), []byte("int main(void) {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "nano")
+
+	cfg := config.Defaults()
+	st := store.New(filepath.Join(wsRoot, ".gust"))
+	fake := &fakeRunner{nextMsg: west.CommandResultMsg{
+		Output: "../apps/blinky/src/main.c:1:16: warning: unused variable 'x' [-Wunused-variable]\n" +
+			"../apps/blinky/src/main.c:1:17: error: expected ';' before '}' token\n",
+		ExitCode: 1,
+	}}
+	p := NewProjectPage(st, &cfg, wsRoot, "", fake)
+	p.boardInput.SetValue("nrf52840dk")
+	p.buildDirInput.SetValue("build")
+	if err := os.MkdirAll(filepath.Join(wsRoot, "build"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	page, cmd := p.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	p = followCommand(t, page, cmd).(*ProjectPage)
+	if !strings.Contains(p.output.String(), "with 1 error, 1 warning") {
+		t.Fatalf("expected diagnostic counts in status, got %q", p.output.String())
+	}
+	builds, _ := st.Builds()
+	if len(builds) != 1 || builds[0].Errors != 1 || builds[0].Warnings != 1 {
+		t.Fatalf("expected counts in build record, got %+v", builds)
+	}
+
+	p = updateProjectPage(p, tea.KeyMsg{Type: tea.KeyCtrlE})
+	if !p.diagOpen || !p.InputCaptured() {
+		t.Fatal("expected ctrl+e to open the diagnostics list and capture input")
+	}
+	if !strings.Contains(p.View(), "Diagnostics (2)") {
+		t.Fatal("expected diagnostics panel in view")
+	}
+	p = updateProjectPage(p, tea.KeyMsg{Type: tea.KeyDown})
+	page, cmd = p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	p = page.(*ProjectPage)
+	if cmd == nil {
+		t.Fatalf("expected editor command, message %q", p.message)
+	}
+
+	p = updateProjectPage(p, tea.KeyMsg{Type: tea.KeyEsc})
+	if p.diagOpen {
+		t.Fatal("expected esc to close the diagnostics list")
+	}
+}
+
+func TestProjectPageProfileSelectedMsgAppliesBuildOptions(t *testing.T) {
+	wsRoot := t.TempDir()
+	cfg := config.Defaults()
+	p := NewProjectPage(nil, &cfg, wsRoot, "")
+
+	page, _ := p.Update(app.ProfileSelectedMsg{Profile: config.Profile{
+		Name: "release", Pristine: true, CMakeArgs: "-DCONFIG_LOG=n", Snippets: []string{"cdc-acm-console"},
+	}})
+	p = page.(*ProjectPage)
+	if p.build.profile != "release" || !p.build.pristine || p.build.cmakeInput.Value() != "-DCONFIG_LOG=n" || len(p.build.extras[extraSnippet].selected) != 1 {
+		t.Fatalf("expected profile options applied, got %+v", p.build)
+	}
+
+	page, _ = p.Update(app.ProfileSelectedMsg{})
+	p = page.(*ProjectPage)
+	if p.build.profile != "" || !p.build.pristine {
+		t.Fatalf("expected clearing the profile to keep options, got profile %q pristine %v", p.build.profile, p.build.pristine)
+	}
+}
+
+func TestProjectPageExtrasPickerSelectsBuildArgs(t *testing.T) {
+	wsRoot := t.TempDir()
+	cfg := config.Defaults()
+	cfg.DefaultBoard = "nrf52840dk"
+	fake := &fakeRunner{}
+	p := NewProjectPage(nil, &cfg, wsRoot, "", fake)
+	p.projectPath = filepath.Join("apps", "demo")
+
+	page, _ := p.Update(west.BuildExtrasLoadedMsg{
+		Project: filepath.Join(wsRoot, "apps", "demo"),
+		Extras:  west.BuildExtras{Conf: []string{"debug.conf", "usb.conf"}, Snippets: []string{"rtt-console"}},
+	})
+	p = page.(*ProjectPage)
+
+	p.focusedField = projFieldConf
+	for _, k := range []tea.KeyMsg{
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyDown},
+		{Type: tea.KeySpace, Runes: []rune{' '}},
+		{Type: tea.KeyEnter},
+	} {
+		page, _ = p.Update(k)
+		p = page.(*ProjectPage)
+	}
+	if got := p.build.extras[extraConf].selected; len(got) != 1 || got[0] != "usb.conf" {
+		t.Fatalf("expected usb.conf selected, got %v", got)
+	}
+	if p.build.extras[extraConf].open {
+		t.Fatal("expected enter to close the picker")
+	}
+
+	p.triggerBuild()
+	args := strings.Join(fake.runCalls[len(fake.runCalls)-1].args, " ")
+	if !strings.Contains(args, "-DEXTRA_CONF_FILE=usb.conf") {
+		t.Fatalf("expected EXTRA_CONF_FILE in build args, got %q", args)
+	}
+}
+
+func TestProjectPageStaleBuildDirPromptsBeforeBuilding(t *testing.T) {
+	wsRoot := t.TempDir()
+	cache := "BOARD:STRING=native_sim\nAPPLICATION_SOURCE_DIR:PATH=" + filepath.Join(wsRoot, "apps", "demo") + "\n"
+	if err := os.MkdirAll(filepath.Join(wsRoot, "build"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wsRoot, "build", "CMakeCache.txt"), []byte(cache), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Defaults()
+	cfg.DefaultBoard = "nrf52840dk"
+	fake := &fakeRunner{nextMsg: west.CommandResultMsg{ExitCode: 0}}
+	p := NewProjectPage(nil, &cfg, wsRoot, "", fake)
+	p.projectPath = filepath.Join("apps", "demo")
+
+	page, cmd := p.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	p = page.(*ProjectPage)
+	if cmd != nil || len(fake.runCalls) != 0 {
+		t.Fatal("expected no build while the build dir is stale")
+	}
+	if len(p.stale) != 1 || p.stale[0].Field != "Board" || p.staleAlt != "build-nrf52840dk" {
+		t.Fatalf("expected a board mismatch with a fresh dir suggestion, got %+v alt %q", p.stale, p.staleAlt)
+	}
+	if !strings.Contains(p.View(), "native_sim") {
+		t.Fatal("expected the cached board in the prompt")
+	}
+
+	page, _ = p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	p = page.(*ProjectPage)
+	if len(fake.runCalls) != 1 || !strings.Contains(strings.Join(fake.runCalls[0].args, " "), "-p always") {
+		t.Fatalf("expected a pristine build, got %+v", fake.runCalls)
+	}
+	if p.stale != nil || p.build.pristine {
+		t.Fatal("expected the prompt closed and the pristine checkbox unchanged")
+	}
+
+	p.Update(west.CommandResultMsg{RequestID: p.activeRequestID})
+	p.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	page, cmd = p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	p = page.(*ProjectPage)
+	if cmd == nil || p.buildDirInput.Value() != "build-nrf52840dk" || cfg.BuildDir != "build-nrf52840dk" {
+		t.Fatalf("expected a switch to build-nrf52840dk, got %q", p.buildDirInput.Value())
+	}
+	if args := strings.Join(fake.runCalls[1].args, " "); !strings.Contains(args, "-d build-nrf52840dk") || strings.Contains(args, "-p always") {
+		t.Fatalf("expected an incremental build in the new dir, got %q", args)
+	}
+}
+
+func TestProjectPageStaleSwitchKeepsBuildDirTemplate(t *testing.T) {
+	wsRoot := t.TempDir()
+	dir := filepath.Join(wsRoot, "out", "nrf52840dk")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "CMakeCache.txt"), []byte("BOARD:STRING=native_sim\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Defaults()
+	cfg.DefaultBoard = "nrf52840dk"
+	cfg.BuildDir = "out/{board}"
+	fake := &fakeRunner{nextMsg: west.CommandResultMsg{ExitCode: 0}}
+	p := NewProjectPage(nil, &cfg, wsRoot, "", fake)
+
+	p.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	if p.staleAlt == "" {
+		t.Fatalf("expected a fresh dir suggestion, got stale %+v", p.stale)
+	}
+	alt := p.staleAlt
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if p.buildDirInput.Value() != alt || cfg.BuildDir != "out/{board}" {
+		t.Fatalf("expected %s for this session and the template kept, got input %q, config %q", alt, p.buildDirInput.Value(), cfg.BuildDir)
+	}
+	if saved := config.Load(wsRoot); saved.BuildDir == alt {
+		t.Fatalf("expected the template not to be saved over, got %q", saved.BuildDir)
+	}
+}
+
+func TestProjectPageShowsBuildProgressAndETA(t *testing.T) {
+	st := store.New(t.TempDir())
+	for _, d := range []string{"40s", "60s"} {
+		if err := st.AddBuild(store.BuildRecord{App: "apps/demo", Board: "qemu_x86", Success: true, Duration: d}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := st.AddBuild(store.BuildRecord{App: "apps/other", Board: "qemu_x86", Success: true, Duration: "10m"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := expectedBuildDuration(st, "apps/demo", "qemu_x86", false); got != 50*time.Second {
+		t.Fatalf("expectedBuildDuration = %v, want 50s", got)
+	}
+
+	cfg := config.Defaults()
+	p := NewProjectPage(st, &cfg, t.TempDir(), "")
+	p.activeRequestID = "build-1"
+	p.activeOp = "Build"
+	p.build.state = buildStateRunning
+	p.build.buildStart = time.Now()
+	p.build.expected = 50 * time.Second
+
+	if view := p.build.viewSection(100, false, false, false, extraNone); !strings.Contains(view, "Building...") {
+		t.Fatalf("expected Building... before output, got:\n%s", view)
+	}
+	p.Update(west.CommandOutputMsg{RequestID: "build-1", Line: "-- west build: generating a build system"})
+	if view := p.build.viewSection(100, false, false, false, extraNone); !strings.Contains(view, "Configuring...") {
+		t.Fatalf("expected configure phase, got:\n%s", view)
+	}
+	p.Update(west.CommandOutputMsg{RequestID: "build-1", Line: "[12/48] Building C object main.c.obj"})
+	view := p.build.viewSection(100, false, false, false, extraNone)
+	if !strings.Contains(view, "Compiling") || !strings.Contains(view, "12/48") || !strings.Contains(view, "left") {
+		t.Fatalf("expected progress bar with ETA, got:\n%s", view)
+	}
+}
+
+func TestProjectPageReproducesRecordedBuild(t *testing.T) {
+	wsRoot := t.TempDir()
+	cfg := config.Defaults()
+	p := NewProjectPage(nil, &cfg, wsRoot, "")
+	r := store.BuildRecord{
+		ID:        "20260101-120000.000000",
+		App:       "apps/demo",
+		Board:     "nrf52840dk/nrf52840",
+		Shield:    "x_nucleo_iks01a3",
+		Timestamp: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+		Pristine:  true,
+		Sysbuild:  true,
+		CMakeArgs: "-DCONFIG_DEBUG=y",
+		ExtraConf: []string{"debug.conf"},
+		Snippets:  []string{"rtt-console"},
+		BuildDir:  "build",
+		GitCommit: "deadbeef",
+	}
+
+	dir := reproduceDir(r)
+	if dir != "build-repro-20260101-120000.000000" {
+		t.Fatalf("reproduceDir = %q", dir)
+	}
+	page, cmd := p.Update(reproduceBuildMsg{record: r, buildDir: dir})
+	p = page.(*ProjectPage)
+	if cmd == nil {
+		t.Fatal("expected selection broadcasts")
+	}
+	if p.projectValue() != "apps/demo" || p.boardInput.Value() != r.Board || p.shieldInput.Value() != r.Shield {
+		t.Fatalf("selection not applied: project=%q board=%q shield=%q", p.projectValue(), p.boardInput.Value(), p.shieldInput.Value())
+	}
+	if !p.build.pristine || !p.build.sysbuild || p.build.cmakeInput.Value() != r.CMakeArgs {
+		t.Fatalf("build options not applied: %+v", p.build)
+	}
+	if !contains(p.build.extras[extraConf].selected, "debug.conf") || !contains(p.build.extras[extraSnippet].selected, "rtt-console") {
+		t.Fatal("expected recorded extras to be selected")
+	}
+	if p.buildDir() != dir {
+		t.Fatalf("buildDir = %q, want %q", p.buildDir(), dir)
+	}
+	if cfg.BuildDir == dir {
+		t.Fatal("expected the separate dir not to be saved as the build dir setting")
+	}
+}
+
+func TestReproduceWarnings(t *testing.T) {
+	r := store.BuildRecord{GitCommit: "aaaa1111"}
+	if w := reproduceWarnings(r, west.GitState{Commit: "aaaa1111"}); len(w) != 0 {
+		t.Fatalf("expected no warnings for a matching commit, got %q", w)
+	}
+	w := reproduceWarnings(r, west.GitState{Commit: "bbbb2222", Dirty: true})
+	if len(w) != 2 || !strings.Contains(w[0], "bbbb2222") || !strings.Contains(w[1], "uncommitted") {
+		t.Fatalf("unexpected warnings: %q", w)
+	}
+	r.GitDirty = true
+	if w := reproduceWarnings(r, west.GitState{Commit: "aaaa1111"}); len(w) != 1 || !strings.Contains(w[0], "cannot be restored") {
+		t.Fatalf("expected a warning about the dirty recorded build, got %q", w)
+	}
+}
+
+func TestProjectPageSyncsCompileCommandsOnBoardChange(t *testing.T) {
+	wsRoot := t.TempDir()
+	for _, board := range []string{"qemu_x86", "native_sim"} {
+		dir := filepath.Join(wsRoot, "build", board)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "compile_commands.json"), []byte("[]"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := config.Defaults()
+	cfg.BuildDir = "build/{board}"
+	p := NewProjectPage(nil, &cfg, wsRoot, "")
+
+	link := filepath.Join(wsRoot, "compile_commands.json")
+	for _, board := range []string{"qemu_x86", "native_sim"} {
+		_, cmd := p.Update(app.BoardSelectedMsg{Board: board})
+		if cmd == nil {
+			t.Fatal("expected compile_commands.json sync")
+		}
+		p.Update(cmd())
+		target, err := os.Readlink(link)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := filepath.Join(wsRoot, "build", board, "compile_commands.json"); target != want {
+			t.Fatalf("link -> %q, want %q", target, want)
+		}
+	}
+
+	cfg.CompileCommands = config.CompileCommandsOff
+	if _, cmd := p.Update(app.BoardSelectedMsg{Board: "qemu_x86"}); cmd != nil {
+		t.Fatal("expected no sync when compile_commands is off")
+	}
+}
+
+func TestProjectPageWatchModeDebouncesAndFlashes(t *testing.T) {
+	wsRoot := t.TempDir()
+	main := filepath.Join(wsRoot, "app", "src", "main.c")
+	if err := os.MkdirAll(filepath.Dir(main), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(main, []byte("int main;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Defaults()
+	cfg.DefaultBoard = "qemu_x86"
+	cfg.LastProject = "app"
+	cfg.CompileCommands = config.CompileCommandsOff
+	fake := &fakeRunner{}
+	p := NewProjectPage(nil, &cfg, wsRoot, "", fake)
+
+	p.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	p.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if p.watch.mode != watchBuildFlash {
+		t.Fatalf("watch mode = %v, want build and flash", p.watch.mode)
+	}
+	if !strings.Contains(p.watch.view(), "flashing on success") {
+		t.Fatalf("unexpected watch status %q", p.watch.view())
+	}
+
+	snap := p.watch.snapshot
+	changed := west.Snapshot{}
+	for path, mod := range snap {
+		changed[path] = mod
+	}
+	changed[main] = snap[main].Add(time.Second)
+
+	now := time.Now()
+	p.handleWatchTick(watchTickMsg{seq: p.watch.seq, snapshot: changed}, now)
+	if len(fake.runCalls) != 0 || len(p.watch.pending) != 1 {
+		t.Fatalf("expected change to wait for the debounce, calls=%d pending=%v", len(fake.runCalls), p.watch.pending)
+	}
+	p.handleWatchTick(watchTickMsg{seq: p.watch.seq, snapshot: changed}, now.Add(watchDebounce))
+	if len(fake.runCalls) != 1 || fake.runCalls[0].args[0] != "build" || !p.watch.auto {
+		t.Fatalf("expected an auto-build, got %+v", fake.runCalls)
+	}
+
+	// Ticks from an earlier session are ignored.
+	if cmd := p.handleWatchTick(watchTickMsg{seq: p.watch.seq - 1, snapshot: west.Snapshot{}}, now); cmd != nil {
+		t.Fatal("expected a stale tick to be ignored")
+	}
+
+	_, cmd := p.Update(west.CommandResultMsg{RequestID: p.activeRequestID, ExitCode: 0})
+	if cmd == nil || p.activeOp != "Flash" || len(fake.runCalls) != 2 || fake.runCalls[1].args[0] != "flash" {
+		t.Fatalf("expected flash after the auto-build, got op=%q calls=%+v", p.activeOp, fake.runCalls)
+	}
+	if !strings.Contains(p.watch.last, "src/main.c") {
+		t.Fatalf("expected last auto-build to name the change, got %q", p.watch.last)
+	}
+
+	p.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if p.watch.mode != watchOff || p.watch.view() != "" {
+		t.Fatal("expected watch mode off after a third ctrl+t")
+	}
+}
+
+func TestProjectPageWatchModeNeedsProject(t *testing.T) {
+	cfg := config.Defaults()
+	p := NewProjectPage(nil, &cfg, t.TempDir(), "", &fakeRunner{})
+
+	if cmd := p.toggleWatch(); cmd != nil || p.watch.mode != watchOff {
+		t.Fatalf("expected watch mode to stay off without a project, mode=%v", p.watch.mode)
+	}
+	if !strings.Contains(p.message, "Select a project") {
+		t.Fatalf("expected a message asking for a project, got %q", p.message)
+	}
+}
+
+func TestProjectPageWatchModeKeepsChangesUntilABuildStarts(t *testing.T) {
+	wsRoot := t.TempDir()
+	main := filepath.Join(wsRoot, "app", "src", "main.c")
+	if err := os.MkdirAll(filepath.Dir(main), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(main, []byte("int main;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Defaults()
+	cfg.LastProject = "app"
+	cfg.CompileCommands = config.CompileCommandsOff
+	fake := &fakeRunner{}
+	p := NewProjectPage(nil, &cfg, wsRoot, "", fake)
+	p.toggleWatch()
+
+	changed := west.Snapshot{}
+	for path, mod := range p.watch.snapshot {
+		changed[path] = mod
+	}
+	changed[main] = changed[main].Add(time.Second)
+	now := time.Now()
+	p.handleWatchTick(watchTickMsg{seq: p.watch.seq, snapshot: changed}, now)
+	p.handleWatchTick(watchTickMsg{seq: p.watch.seq, snapshot: changed}, now.Add(watchDebounce))
+	if len(fake.runCalls) != 0 || len(p.watch.pending) != 1 || !strings.Contains(p.watch.view(), "no board selected") {
+		t.Fatalf("expected the change to wait for a board, calls=%d pending=%v view=%q", len(fake.runCalls), p.watch.pending, p.watch.view())
+	}
+
+	p.boardInput.SetValue("qemu_x86")
+	p.handleWatchTick(watchTickMsg{seq: p.watch.seq, snapshot: changed}, now.Add(2*watchDebounce))
+	if len(fake.runCalls) != 1 || len(p.watch.pending) != 0 || p.watch.blocked != "" || !p.watch.auto {
+		t.Fatalf("expected the pending change to be built, calls=%d pending=%v", len(fake.runCalls), p.watch.pending)
+	}
+}
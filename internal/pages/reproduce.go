@@ -0,0 +1,100 @@
+package pages
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/buckleypaul/gust/internal/app"
+	"github.com/buckleypaul/gust/internal/config"
+	"github.com/buckleypaul/gust/internal/store"
+	"github.com/buckleypaul/gust/internal/west"
+)
+
+// reproduceBuildMsg asks the Project page to set itself up to rebuild a
+// recorded build.
+type reproduceBuildMsg struct {
+	record   store.BuildRecord
+	buildDir string // the record's own build dir, or a separate one
+}
+
+// reproduceDir is the separate build directory for reproducing r, so the
+// current build is left alone.
+func reproduceDir(r store.BuildRecord) string {
+	base := r.BuildDir
+	if base == "" {
+		base = config.DefaultBuildDir
+	}
+	id := r.ID
+	if id == "" {
+		id = r.Timestamp.Format("20060102-150405")
+	}
+	return base + "-repro-" + id
+}
+
+// reproduce applies a recorded build's project, board, shield and build
+// options, and warns when the sources no longer match what was recorded.
+func (p *ProjectPage) reproduce(msg reproduceBuildMsg) tea.Cmd {
+	r := msg.record
+	var cmds []tea.Cmd
+	if r.App != p.projectValue() {
+		cmds = append(cmds, p.selectProject(r.App))
+	}
+	if r.Board != p.boardInput.Value() {
+		board := r.Board
+		p.boardInput.SetValue(board)
+		p.filterBoards()
+		p.loadOverlay()
+		p.cfg.DefaultBoard = board
+		cmds = append(cmds, func() tea.Msg { return app.BoardSelectedMsg{Board: board} })
+	}
+	if r.Shield != p.shieldInput.Value() {
+		shield := r.Shield
+		p.shieldInput.SetValue(shield)
+		p.cfg.LastShield = shield
+		cmds = append(cmds, func() tea.Msg { return app.ShieldSelectedMsg{Shield: shield} })
+	}
+	if err := config.Save(*p.cfg, p.wsRoot, false); err != nil {
+		p.message = fmt.Sprintf("Config save failed: %v", err)
+	}
+	// The recorded dir is already expanded, so it replaces the setting
+	// only for this session rather than being saved over a template.
+	if dir := msg.buildDir; dir != p.buildDir() {
+		p.buildDirInput.SetValue(dir)
+		cmds = append(cmds, func() tea.Msg { return app.BuildDirChangedMsg{Dir: dir} })
+	}
+
+	p.build.profile = r.Profile
+	p.build.pristine = r.Pristine
+	p.build.sysbuild = r.Sysbuild
+	p.build.cmakeInput.SetValue(r.CMakeArgs)
+	p.build.extras[extraConf].setSelected(r.ExtraConf)
+	p.build.extras[extraOverlay].setSelected(r.ExtraOverlay)
+	p.build.extras[extraSnippet].setSelected(r.Snippets)
+
+	gitDir := west.ProjectPath(p.wsRoot, r.App)
+	if gitDir == "" {
+		gitDir = p.wsRoot
+	}
+	p.message = fmt.Sprintf("Reproducing build of %s. Press ctrl+b to build.", r.Timestamp.Format("Jan 02 15:04"))
+	if warnings := reproduceWarnings(r, west.ReadGitState(gitDir)); len(warnings) > 0 {
+		p.message += "\n  Warning: " + strings.Join(warnings, "; ")
+	}
+	return tea.Batch(cmds...)
+}
+
+// reproduceWarnings lists how the app's git state differs from a record.
+func reproduceWarnings(r store.BuildRecord, git west.GitState) []string {
+	var warnings []string
+	if r.GitCommit != "" && git.Commit != "" && r.GitCommit != git.Commit {
+		warnings = append(warnings, fmt.Sprintf("app is at %s, the build was made from %s", git.Commit, r.GitCommit))
+	}
+	switch {
+	case r.GitDirty:
+		warnings = append(warnings, "the build was made from uncommitted changes that cannot be restored")
+	case git.Dirty:
+		warnings = append(warnings, "app has uncommitted changes")
+	}
+	return warnings
+}
@@ -1,10 +1,20 @@
 package pages
 
-import tea "github.com/charmbracelet/bubbletea"
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/buckleypaul/gust/internal/app"
+	"github.com/buckleypaul/gust/internal/west"
+)
 
 type runCall struct {
 	name string
 	args []string
+	ctx  context.Context
 }
 
 type fakeRunner struct {
@@ -28,53 +38,104 @@ func (f *fakeRunner) cmd() tea.Cmd {
 	}
 }
 
-func (f *fakeRunner) Run(name string, args ...string) tea.Cmd {
+func (f *fakeRunner) Run(ctx context.Context, name string, args ...string) tea.Cmd {
 	copied := append([]string(nil), args...)
-	f.runCalls = append(f.runCalls, runCall{name: name, args: copied})
+	f.runCalls = append(f.runCalls, runCall{name: name, args: copied, ctx: ctx})
 	return f.cmd()
 }
 
-func (f *fakeRunner) Status() tea.Cmd {
+func (f *fakeRunner) Status(ctx context.Context) tea.Cmd {
 	f.statusCalls++
 	return f.cmd()
 }
 
-func (f *fakeRunner) List() tea.Cmd {
+func (f *fakeRunner) List(ctx context.Context) tea.Cmd {
 	f.listCalls++
 	return f.cmd()
 }
 
-func (f *fakeRunner) Diff() tea.Cmd {
+func (f *fakeRunner) Diff(ctx context.Context) tea.Cmd {
 	f.diffCalls++
 	return f.cmd()
 }
 
-func (f *fakeRunner) Update() tea.Cmd {
+func (f *fakeRunner) Update(ctx context.Context) tea.Cmd {
 	f.updateCalls++
 	return f.cmd()
 }
 
-func (f *fakeRunner) Init() tea.Cmd {
+func (f *fakeRunner) Init(ctx context.Context) tea.Cmd {
 	f.initCalls++
 	return f.cmd()
 }
 
-func (f *fakeRunner) ZephyrExport() tea.Cmd {
+func (f *fakeRunner) ZephyrExport(ctx context.Context) tea.Cmd {
 	f.zephyrExportCalls++
 	return f.cmd()
 }
 
-func (f *fakeRunner) PackagesPipInstall() tea.Cmd {
+func (f *fakeRunner) PackagesPipInstall(ctx context.Context) tea.Cmd {
 	f.packagesPipCalls++
 	return f.cmd()
 }
 
-func (f *fakeRunner) SdkInstall() tea.Cmd {
+func (f *fakeRunner) SdkInstall(ctx context.Context) tea.Cmd {
 	f.sdkInstallCalls++
 	return f.cmd()
 }
 
-func (f *fakeRunner) InstallBrewDeps() tea.Cmd {
+func (f *fakeRunner) InstallBrewDeps(ctx context.Context) tea.Cmd {
 	f.installBrewDepsCalls++
 	return f.cmd()
 }
+
+// newReplayRunner loads testdata/transcripts/<name>.json for replay against
+// wsRoot.
+func newReplayRunner(t *testing.T, name, wsRoot string) *west.ReplayRunner {
+	t.Helper()
+	tr, err := west.LoadTranscript(filepath.Join("testdata", "transcripts", name+".json"))
+	if err != nil {
+		t.Fatalf("LoadTranscript(%s) error: %v", name, err)
+	}
+	return west.NewReplayRunner(tr, wsRoot)
+}
+
+// followCommand feeds cmd's output stream into page the way the program loop
+// would, and returns the page after the final result has been handled.
+// Progress ticks batched with the stream are left out.
+func followCommand(t *testing.T, page app.Page, cmd tea.Cmd) app.Page {
+	t.Helper()
+	for cmd != nil {
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			msg = firstStreamMsg(batch)
+		}
+		page, cmd = page.Update(msg)
+		if _, ok := msg.(west.CommandOutputMsg); !ok {
+			return page
+		}
+	}
+	t.Fatal("output stream ended without a result")
+	return page
+}
+
+// firstStreamMsg runs the commands of a batch and returns the first message
+// that is not a progress tick.
+func firstStreamMsg(batch tea.BatchMsg) tea.Msg {
+	msgs := make(chan tea.Msg, len(batch))
+	pending := 0
+	for _, c := range batch {
+		if c != nil {
+			pending++
+			go func(c tea.Cmd) { msgs <- c() }(c)
+		}
+	}
+	for ; pending > 0; pending-- {
+		if msg := <-msgs; msg != nil {
+			if _, tick := msg.(buildTickMsg); !tick {
+				return msg
+			}
+		}
+	}
+	return nil
+}
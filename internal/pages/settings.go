@@ -25,6 +25,8 @@ var settingFields = []settingField{
 	{"Serial Baud Rate", "serial_baud_rate"},
 	{"Build Directory", "build_dir"},
 	{"Flash Runner", "flash_runner"},
+	{"Matrix Jobs", "matrix_jobs"},
+	{"Compile Commands", "compile_commands"},
 }
 
 type SettingsPage struct {
@@ -163,6 +165,10 @@ func (p *SettingsPage) getValue(idx int) string {
 		return p.cfg.BuildDir
 	case "flash_runner":
 		return p.cfg.FlashRunner
+	case "matrix_jobs":
+		return strconv.Itoa(p.cfg.MatrixJobs)
+	case "compile_commands":
+		return p.cfg.CompileCommands
 	}
 	return ""
 }
@@ -181,6 +187,19 @@ func (p *SettingsPage) applyValue(val string) {
 		p.cfg.BuildDir = val
 	case "flash_runner":
 		p.cfg.FlashRunner = val
+	case "matrix_jobs":
+		if n, err := strconv.Atoi(val); err == nil && n > 0 {
+			p.cfg.MatrixJobs = n
+		}
+	case "compile_commands":
+		switch val {
+		case "", config.CompileCommandsLink, config.CompileCommandsFiltered, config.CompileCommandsOff:
+			p.cfg.CompileCommands = val
+		default:
+			p.message = fmt.Sprintf("Compile Commands must be %s, %s or %s",
+				config.CompileCommandsLink, config.CompileCommandsFiltered, config.CompileCommandsOff)
+			return
+		}
 	}
 	p.message = fmt.Sprintf("%s updated", settingFields[p.cursor].label)
 }
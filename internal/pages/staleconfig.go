@@ -0,0 +1,142 @@
+package pages
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/buckleypaul/gust/internal/app"
+	"github.com/buckleypaul/gust/internal/config"
+	"github.com/buckleypaul/gust/internal/ui"
+	"github.com/buckleypaul/gust/internal/west"
+)
+
+// selectedConfig is what the next build would configure the build dir for.
+func (p *ProjectPage) selectedConfig() west.BuildDirConfig {
+	conf, _ := west.CMakeDefine(p.build.cmakeInput.Value(), "CONF_FILE")
+	return west.BuildDirConfig{
+		Board:     p.boardInput.Value(),
+		SourceDir: west.ProjectPath(p.wsRoot, p.projectValue()),
+		Shield:    p.shieldInput.Value(),
+		ConfFile:  conf,
+	}
+}
+
+// containerMount returns where the workspace is mounted when builds run in
+// a container, or "" when they run on the host.
+func containerMount(cfg *config.Config) string {
+	if !cfg.UseContainer() {
+		return ""
+	}
+	if cfg.Container.Mount != "" {
+		return cfg.Container.Mount
+	}
+	return west.DefaultContainerMount
+}
+
+// staleConfig compares the CMakeCache.txt in dir with the current selection.
+// A directory that was never configured is not stale.
+func (p *ProjectPage) staleConfig(dir string) []west.ConfigDiff {
+	cached, err := west.ReadBuildDirConfig(p.wsRoot, dir)
+	if err != nil {
+		return nil
+	}
+	if mount := containerMount(p.cfg); mount != "" {
+		cached.FromContainer(mount, p.wsRoot)
+	}
+	return west.StaleConfig(*cached, p.selectedConfig())
+}
+
+// alternateBuildDir suggests where to build instead of a stale directory:
+// a directory from the build history already configured for the current
+// selection, or else an unused one named after the board.
+func (p *ProjectPage) alternateBuildDir(current string) (dir string, existing bool) {
+	if p.store != nil {
+		builds, _ := p.store.Builds()
+		for i := len(builds) - 1; i >= 0; i-- {
+			d := builds[i].BuildDir
+			if d == "" || sameBuildDir(d, current) {
+				continue
+			}
+			if _, err := os.Stat(west.CMakeCachePath(p.wsRoot, d)); err == nil && len(p.staleConfig(d)) == 0 {
+				return d, true
+			}
+		}
+	}
+	base := current
+	if base == "" {
+		base = config.DefaultBuildDir
+	}
+	fresh := west.ExpandBuildDir(base+"-{board}", p.projectValue(), p.boardInput.Value(), p.build.profile)
+	if len(p.staleConfig(fresh)) > 0 {
+		return "", false
+	}
+	return fresh, false
+}
+
+// handleStaleKey answers the stale build directory prompt.
+func (p *ProjectPage) handleStaleKey(keyStr string) (app.Page, tea.Cmd) {
+	switch keyStr {
+	case "p":
+		p.stale = nil
+		p.build.pristineNext = true
+		return p, p.startBuild()
+	case "s":
+		if p.staleAlt == "" {
+			return p, nil
+		}
+		dir := p.staleAlt
+		p.stale = nil
+		p.buildDirInput.SetValue(dir)
+		// dir is expanded, so a saved template such as build/{board} is
+		// kept and the switch lasts for this session only.
+		if !strings.Contains(p.cfg.BuildDir, "{") {
+			p.cfg.BuildDir = dir
+			if err := config.Save(*p.cfg, p.wsRoot, false); err != nil {
+				p.message = fmt.Sprintf("Build dir set, but config save failed: %v", err)
+			}
+		}
+		return p, tea.Batch(
+			func() tea.Msg { return app.BuildDirChangedMsg{Dir: dir} },
+			p.startBuild(),
+		)
+	case "b", "enter":
+		p.stale = nil
+		return p, p.startBuild()
+	case "esc":
+		p.stale = nil
+	}
+	return p, nil
+}
+
+// viewStale renders the stale build directory prompt.
+func (p *ProjectPage) viewStale(width int) string {
+	var b strings.Builder
+	sectionLabel := lipgloss.NewStyle().Foreground(ui.Subtle).Bold(true)
+	header := "── Build dir " + p.buildDir() + " is configured differently "
+	b.WriteString("  " + sectionLabel.Render(header+strings.Repeat("─", max(width-len(header)-4, 10))) + "\n")
+	for _, d := range p.stale {
+		cached, selected := d.Cached, d.Selected
+		if cached == "" {
+			cached = "(none)"
+		}
+		if selected == "" {
+			selected = "(none)"
+		}
+		b.WriteString(fmt.Sprintf("  %-11s %s → %s\n", d.Field, ui.WarningBadge(cached), ui.BoldStyle.Render(selected)))
+	}
+	options := "  p: build pristine"
+	if p.staleAlt != "" {
+		kind := "new"
+		if p.staleAltExisting {
+			kind = "matching"
+		}
+		options += fmt.Sprintf("  s: switch to %s dir %s", kind, p.staleAlt)
+	}
+	options += "  b: build anyway  esc: cancel"
+	b.WriteString(ui.DimStyle.Render(options) + "\n")
+	return b.String()
+}
@@ -1,8 +1,8 @@
 package pages
 
 import (
+	"context"
 	"fmt"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -24,7 +24,8 @@ type TestPage struct {
 	runner          west.Runner
 	selectedProject string
 	selectedBoard   string
-	buildDir        string
+	buildDir        string // Build Dir setting, possibly a template
+	profile         string
 	running         bool
 	output          strings.Builder
 	viewport        viewport.Model
@@ -33,6 +34,7 @@ type TestPage struct {
 	message         string
 	requestSeq      int
 	activeRequestID string
+	cancel          context.CancelFunc
 }
 
 func NewTestPage(s *store.Store, cfg *config.Config, wsRoot string, runners ...west.Runner) *TestPage {
@@ -50,6 +52,7 @@ func NewTestPage(s *store.Store, cfg *config.Config, wsRoot string, runners ...w
 		selectedProject: cfg.LastProject,
 		selectedBoard:   cfg.DefaultBoard,
 		buildDir:        cfg.BuildDir,
+		profile:         cfg.ActiveProfile,
 	}
 }
 
@@ -69,8 +72,19 @@ func (p *TestPage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
 		p.buildDir = msg.Dir
 		return p, nil
 
+	case app.ProfileSelectedMsg:
+		p.profile = msg.Profile.Name
+		return p, nil
+
 	case tea.KeyMsg:
 		if p.running {
+			if msg.String() == "ctrl+x" && p.cancel != nil {
+				p.cancel()
+				p.output.WriteString("\nCancelling tests...\n")
+				p.viewport.SetContent(p.output.String())
+				p.viewport.GotoBottom()
+				return p, nil
+			}
 			var cmd tea.Cmd
 			p.viewport, cmd = p.viewport.Update(msg)
 			return p, cmd
@@ -84,30 +98,29 @@ func (p *TestPage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
 			p.output.Reset()
 			p.testStart = time.Now()
 
-			args := []string{"build", "-t", "run"}
-			if p.selectedBoard != "" {
-				args = append(args, "-b", p.selectedBoard)
-			}
-			if p.buildDir != "" {
-				args = append(args, "-d", p.buildDir)
-			}
-			project := p.selectedProject
-			if project != "" {
-				if !filepath.IsAbs(project) {
-					project = filepath.Join(p.wsRoot, project)
-				}
-				args = append(args, project)
-			}
+			project := west.ProjectPath(p.wsRoot, p.selectedProject)
+			args := west.TestArgs(p.selectedBoard, p.expandedBuildDir(), project)
 
 			p.output.WriteString("$ west " + strings.Join(args, " ") + "\n\n")
 			p.viewport.SetContent(p.output.String())
-			return p, west.WithRequestID(requestID, p.runner.Run("west", args...))
+			ctx, cancel := context.WithCancel(context.Background())
+			p.cancel = cancel
+			return p, west.WithRequestID(requestID, p.runner.Run(ctx, "west", args...))
 		case "c":
 			p.output.Reset()
 			p.viewport.SetContent("")
 			p.message = ""
 		}
 
+	case west.CommandOutputMsg:
+		if !p.running || msg.RequestID != p.activeRequestID {
+			return p, nil
+		}
+		p.output.WriteString(msg.Line + "\n")
+		p.viewport.SetContent(p.output.String())
+		p.viewport.GotoBottom()
+		return p, msg.Next
+
 	case west.CommandResultMsg:
 		// Only handle command results if we're actually running tests
 		if !p.running {
@@ -119,11 +132,20 @@ func (p *TestPage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
 
 		p.running = false
 		p.activeRequestID = ""
-		p.output.WriteString(msg.Output)
-		success := msg.ExitCode == 0
-		if success {
+		if p.cancel != nil {
+			p.cancel()
+			p.cancel = nil
+		}
+		if !msg.Streamed {
+			p.output.WriteString(msg.Output)
+		}
+		success := msg.ExitCode == 0 && !msg.Cancelled
+		switch {
+		case msg.Cancelled:
+			p.message = "Tests cancelled"
+		case success:
 			p.message = "Tests passed"
-		} else {
+		default:
 			p.message = fmt.Sprintf("Tests failed (exit code: %d)", msg.ExitCode)
 		}
 		p.output.WriteString(fmt.Sprintf("\n%s in %s\n", p.message, msg.Duration))
@@ -136,6 +158,7 @@ func (p *TestPage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
 				Timestamp: p.testStart,
 				Success:   success,
 				Duration:  msg.Duration.String(),
+				Cancelled: msg.Cancelled,
 			}); err != nil {
 				p.message = fmt.Sprintf("Tests completed, but history save failed: %v", err)
 			}
@@ -160,7 +183,7 @@ func (p *TestPage) View() string {
 		cfgB.WriteString(fmt.Sprintf("  Board:   %s\n", p.selectedBoard))
 	}
 	if p.buildDir != "" {
-		cfgB.WriteString(fmt.Sprintf("  Dir:     %s\n", p.buildDir))
+		cfgB.WriteString(fmt.Sprintf("  Dir:     %s\n", p.expandedBuildDir()))
 	}
 	if p.message != "" {
 		cfgB.WriteString("  " + p.message + "\n")
@@ -182,6 +205,11 @@ func (p *TestPage) View() string {
 func (p *TestPage) Name() string { return "Test" }
 
 func (p *TestPage) ShortHelp() []key.Binding {
+	if p.running {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "cancel")),
+		}
+	}
 	return []key.Binding{
 		key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "run tests")),
 		key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "clear")),
@@ -203,3 +231,9 @@ func (p *TestPage) nextRequestID() string {
 	p.requestSeq++
 	return fmt.Sprintf("test-%d", p.requestSeq)
 }
+
+// expandedBuildDir fills in the build directory template for the selected
+// project and board.
+func (p *TestPage) expandedBuildDir() string {
+	return west.ExpandBuildDir(p.buildDir, p.selectedProject, p.selectedBoard, p.profile)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/buckleypaul/gust/internal/app"
 	"github.com/buckleypaul/gust/internal/config"
+	"github.com/buckleypaul/gust/internal/store"
 	"github.com/buckleypaul/gust/internal/west"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -102,3 +103,68 @@ func TestTestPageHandlesBroadcastMessages(t *testing.T) {
 		t.Fatalf("expected buildDir build-x, got %s", p.buildDir)
 	}
 }
+
+func TestTestPageCancelRecordsCancelledRun(t *testing.T) {
+	cfg := config.Defaults()
+	st := store.New(t.TempDir())
+	fake := &fakeRunner{nextMsg: west.CommandResultMsg{}}
+	p := NewTestPage(st, &cfg, t.TempDir(), fake)
+
+	page, _ := p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	p = page.(*TestPage)
+	ctx := fake.runCalls[0].ctx
+
+	page, _ = p.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	p = page.(*TestPage)
+	if ctx.Err() == nil {
+		t.Fatal("expected test context to be cancelled")
+	}
+
+	page, _ = p.Update(west.CommandResultMsg{RequestID: p.activeRequestID, ExitCode: -1, Cancelled: true})
+	p = page.(*TestPage)
+	if p.running {
+		t.Fatal("expected running to stop after cancelled result")
+	}
+	tests, err := st.Tests()
+	if err != nil {
+		t.Fatalf("Tests() error: %v", err)
+	}
+	if len(tests) != 1 || !tests[0].Cancelled || tests[0].Success {
+		t.Fatalf("expected one cancelled test record, got %+v", tests)
+	}
+}
+
+func TestTestPageReplaysPassingAndFailingRuns(t *testing.T) {
+	wsRoot := t.TempDir()
+	cfg := config.Defaults()
+	cfg.DefaultBoard = "qemu_cortex_m3"
+	cfg.BuildDir = "build"
+	cfg.LastProject = filepath.Join("apps", "blinky")
+	st := store.New(filepath.Join(wsRoot, ".gust"))
+	replay := newReplayRunner(t, "test_blinky", wsRoot)
+	p := NewTestPage(st, &cfg, wsRoot, replay)
+
+	page, cmd := p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	p = followCommand(t, page, cmd).(*TestPage)
+	if p.message != "Tests passed" {
+		t.Fatalf("expected first run to pass, got %q", p.message)
+	}
+
+	page, cmd = p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	p = followCommand(t, page, cmd).(*TestPage)
+	if p.message != "Tests failed (exit code: 1)" {
+		t.Fatalf("expected second run to fail, got %q", p.message)
+	}
+	assertion := "Assertion failed at " + filepath.Join(wsRoot, "apps", "blinky", "src", "main.c") + ":42"
+	if !strings.Contains(p.output.String(), assertion) {
+		t.Fatalf("expected failure location with workspace path, got %q", p.output.String())
+	}
+
+	tests, err := st.Tests()
+	if err != nil {
+		t.Fatalf("Tests() error: %v", err)
+	}
+	if len(tests) != 2 || !tests[0].Success || tests[1].Success {
+		t.Fatalf("expected a passing then failing test record, got %+v", tests)
+	}
+}
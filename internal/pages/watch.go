@@ -0,0 +1,194 @@
+package pages
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/buckleypaul/gust/internal/ui"
+	"github.com/buckleypaul/gust/internal/west"
+)
+
+const (
+	// watchInterval is how often the project is polled for changes.
+	watchInterval = 500 * time.Millisecond
+	// watchDebounce is how long the sources must stay unchanged before an
+	// auto-build starts, so saving several files builds once.
+	watchDebounce = time.Second
+)
+
+// watchMode is what watch mode does after a change.
+type watchMode int
+
+const (
+	watchOff watchMode = iota
+	watchBuild
+	watchBuildFlash // flash after each successful auto-build
+)
+
+// watchTickMsg carries a fresh snapshot of the watched project.
+type watchTickMsg struct {
+	seq      int
+	snapshot west.Snapshot
+}
+
+// watchState rebuilds the project whenever its sources change.
+type watchState struct {
+	mode      watchMode
+	seq       int // ticks of an earlier watch session are ignored
+	snapshot  west.Snapshot
+	pending   []string // changed files not built yet
+	changedAt time.Time
+	blocked   string // why pending changes are not being built
+	auto      bool   // the running build was started by the watcher
+	trigger   string // files that started the running auto-build
+	last      string // result of the last auto-build
+}
+
+// toggleWatch cycles watch mode off → build → build and flash → off. It
+// needs a project; the workspace as a whole is too big to poll.
+func (p *ProjectPage) toggleWatch() tea.Cmd {
+	w := &p.watch
+	if w.mode == watchOff && p.watchRoot() == "" {
+		p.message = "Select a project to watch."
+		return nil
+	}
+	w.mode = (w.mode + 1) % (watchBuildFlash + 1)
+	w.seq++
+	w.pending = nil
+	w.blocked = ""
+	if w.mode == watchOff {
+		w.snapshot = nil
+		return nil
+	}
+	if w.mode == watchBuild {
+		// A fresh session: changes made while off do not count.
+		w.snapshot = west.SnapshotSources(p.watchRoot(), west.BuildDirPath(p.wsRoot, p.buildDir()))
+	}
+	return p.watchTick()
+}
+
+// watchRoot is the directory watched for changes, or "" without a project.
+func (p *ProjectPage) watchRoot() string {
+	return p.projectAbsPath()
+}
+
+func (p *ProjectPage) watchTick() tea.Cmd {
+	seq, root, buildDir := p.watch.seq, p.watchRoot(), west.BuildDirPath(p.wsRoot, p.buildDir())
+	return tea.Tick(watchInterval, func(time.Time) tea.Msg {
+		return watchTickMsg{seq: seq, snapshot: west.SnapshotSources(root, buildDir)}
+	})
+}
+
+// handleWatchTick records changes and starts an auto-build once they have
+// settled and nothing else is running. Changes stay pending until a build
+// starts.
+func (p *ProjectPage) handleWatchTick(msg watchTickMsg, now time.Time) tea.Cmd {
+	w := &p.watch
+	if w.mode == watchOff || msg.seq != w.seq {
+		return nil
+	}
+	if p.watchRoot() == "" {
+		w.mode, w.snapshot, w.pending, w.blocked = watchOff, nil, nil, ""
+		p.message = "Watch mode stopped: no project selected."
+		return nil
+	}
+	if changed := msg.snapshot.Changed(w.snapshot); len(changed) > 0 {
+		for _, c := range changed {
+			if !contains(w.pending, c) {
+				w.pending = append(w.pending, c)
+			}
+		}
+		w.changedAt = now
+	}
+	w.snapshot = msg.snapshot
+
+	cmds := []tea.Cmd{p.watchTick()}
+	if len(w.pending) > 0 && now.Sub(w.changedAt) >= watchDebounce && p.activeRequestID == "" && p.stale == nil {
+		if w.blocked = p.autoBuildBlocked(); w.blocked == "" {
+			trigger := p.watchSummary(w.pending)
+			cmds = append(cmds, p.startBuild())
+			w.auto = true
+			w.trigger = trigger
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// autoBuildBlocked returns why an auto-build cannot start, or "". Unlike a
+// manual build it never opens the stale config prompt; the watch line says
+// a manual build is needed instead.
+func (p *ProjectPage) autoBuildBlocked() string {
+	if p.boardInput.Value() == "" {
+		return "no board selected"
+	}
+	if !p.build.pristine && len(p.staleConfig(p.buildDir())) > 0 {
+		return "build settings changed, build with ctrl+b"
+	}
+	return ""
+}
+
+// completeWatchBuild records an auto-build's result and flashes it when
+// asked to.
+func (p *ProjectPage) completeWatchBuild(result west.CommandResultMsg) tea.Cmd {
+	w := &p.watch
+	if !w.auto {
+		return nil
+	}
+	w.auto = false
+	stamp := time.Now().Format("15:04:05")
+	switch {
+	case result.Cancelled:
+		w.last = fmt.Sprintf("%s cancelled (%s)", stamp, w.trigger)
+	case result.ExitCode != 0:
+		w.last = fmt.Sprintf("%s %s (%s)", stamp, ui.ErrorBadge("FAIL"), w.trigger)
+	default:
+		w.last = fmt.Sprintf("%s %s in %s (%s)", stamp, ui.SuccessBadge("OK"), formatElapsed(result.Duration), w.trigger)
+		if w.mode == watchBuildFlash {
+			return p.triggerFlash()
+		}
+	}
+	return nil
+}
+
+// watchSummary names the changed files relative to the project.
+func (p *ProjectPage) watchSummary(files []string) string {
+	root := p.watchRoot()
+	names := make([]string, 0, 2)
+	for _, f := range files {
+		if len(names) == 2 {
+			names = append(names, fmt.Sprintf("+%d more", len(files)-2))
+			break
+		}
+		if rel, err := filepath.Rel(root, f); err == nil {
+			f = rel
+		}
+		names = append(names, filepath.ToSlash(f))
+	}
+	return strings.Join(names, ", ")
+}
+
+// view renders the watch status line of the build section.
+func (w *watchState) view() string {
+	if w.mode == watchOff {
+		return ""
+	}
+	status := "Watching for changes"
+	if w.mode == watchBuildFlash {
+		status += ", flashing on success"
+	}
+	if len(w.pending) > 0 {
+		status += fmt.Sprintf(" · %d changed", len(w.pending))
+	}
+	if w.blocked != "" {
+		status += " · waiting: " + w.blocked
+	}
+	line := "  " + ui.AccentStyle.Render("◉ "+status)
+	if w.last != "" {
+		line += ui.DimStyle.Render("  last: ") + w.last
+	}
+	return line + "\n"
+}
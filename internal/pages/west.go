@@ -1,6 +1,7 @@
 package pages
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -16,7 +17,7 @@ import (
 type westCommand struct {
 	name string
 	desc string
-	cmd  func() tea.Cmd
+	cmd  func(ctx context.Context) tea.Cmd
 }
 
 type WestPage struct {
@@ -29,6 +30,7 @@ type WestPage struct {
 	width, height   int
 	requestSeq      int
 	activeRequestID string
+	cancel          context.CancelFunc
 }
 
 func NewWestPage(runners ...west.Runner) *WestPage {
@@ -55,6 +57,13 @@ func (p *WestPage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if p.running {
+			if msg.String() == "ctrl+x" && p.cancel != nil {
+				p.cancel()
+				p.output.WriteString("\nCancelling...\n")
+				p.viewport.SetContent(p.output.String())
+				p.viewport.GotoBottom()
+				return p, nil
+			}
 			// While running, only allow viewport scrolling
 			var cmd tea.Cmd
 			p.viewport, cmd = p.viewport.Update(msg)
@@ -77,34 +86,23 @@ func (p *WestPage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
 			p.output.Reset()
 			p.output.WriteString(fmt.Sprintf("Running west %s...\n\n", p.commands[p.cursor].name))
 			p.viewport.SetContent(p.output.String())
-			return p, west.WithRequestID(requestID, p.commands[p.cursor].cmd())
+			ctx, cancel := context.WithCancel(context.Background())
+			p.cancel = cancel
+			return p, west.WithRequestID(requestID, p.commands[p.cursor].cmd(ctx))
 		case "c":
 			p.output.Reset()
 			p.viewport.SetContent("")
 		}
 
 	case west.CommandOutputMsg:
-		if !p.running {
+		if !p.running || msg.RequestID != p.activeRequestID {
 			return p, nil
 		}
 		p.output.WriteString(msg.Line + "\n")
 		p.viewport.SetContent(p.output.String())
 		p.viewport.GotoBottom()
+		return p, msg.Next
 
-	case west.CommandCompletedMsg:
-		if !p.running {
-			return p, nil
-		}
-		p.running = false
-		p.activeRequestID = ""
-		if msg.Err != nil {
-			p.output.WriteString(fmt.Sprintf("\nError: %v\n", msg.Err))
-		}
-		p.output.WriteString(fmt.Sprintf("\nCompleted in %s (exit code: %d)\n", msg.Duration, msg.ExitCode))
-		p.viewport.SetContent(p.output.String())
-		p.viewport.GotoBottom()
-
-	// Handle the bundled result from RunStreaming
 	case west.CommandResultMsg:
 		// Only handle command results if we're actually running a west command
 		if !p.running {
@@ -116,9 +114,17 @@ func (p *WestPage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
 
 		p.running = false
 		p.activeRequestID = ""
-		p.output.WriteString(msg.Output)
+		if p.cancel != nil {
+			p.cancel()
+			p.cancel = nil
+		}
+		if !msg.Streamed {
+			p.output.WriteString(msg.Output)
+		}
 		status := "success"
-		if msg.ExitCode != 0 {
+		if msg.Cancelled {
+			status = "cancelled"
+		} else if msg.ExitCode != 0 {
 			status = fmt.Sprintf("failed (exit code: %d)", msg.ExitCode)
 		}
 		p.output.WriteString(fmt.Sprintf("\nCompleted in %s — %s\n", msg.Duration, status))
@@ -156,6 +162,11 @@ func (p *WestPage) View() string {
 func (p *WestPage) Name() string { return "West" }
 
 func (p *WestPage) ShortHelp() []key.Binding {
+	if p.running {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "cancel")),
+		}
+	}
 	return []key.Binding{
 		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "run")),
 		key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "clear")),
@@ -1,6 +1,7 @@
 package pages
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/buckleypaul/gust/internal/west"
@@ -34,3 +35,18 @@ func TestWestPageEnterUsesInjectedRunner(t *testing.T) {
 		t.Fatal("expected request ID")
 	}
 }
+
+func TestWestPageAppendsStreamedOutput(t *testing.T) {
+	p := NewWestPage(&fakeRunner{})
+	p.running = true
+	p.activeRequestID = "west-1"
+
+	page, cmd := p.Update(west.CommandOutputMsg{RequestID: "west-1", Line: "=== updating zephyr", Next: func() tea.Msg { return nil }})
+	updated := page.(*WestPage)
+	if cmd == nil {
+		t.Fatal("expected continuation command")
+	}
+	if !strings.Contains(updated.output.String(), "=== updating zephyr") {
+		t.Fatalf("expected streamed line in output, got %q", updated.output.String())
+	}
+}
@@ -1,6 +1,7 @@
 package pages
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -106,7 +107,7 @@ func (p *WorkspacePage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
 				p.output.Reset()
 				p.output.WriteString("Running west update...\n\n")
 				p.viewport.SetContent(p.output.String())
-				return p, west.WithRequestID(requestID, p.runner.Update())
+				return p, west.WithRequestID(requestID, p.runner.Update(context.Background()))
 			}
 		case "c":
 			p.output.Reset()
@@ -123,6 +124,15 @@ func (p *WorkspacePage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
 			}
 		}
 
+	case west.CommandOutputMsg:
+		if (!p.settingUp && !p.updating) || msg.RequestID != p.activeRequestID {
+			return p, nil
+		}
+		p.output.WriteString(msg.Line + "\n")
+		p.viewport.SetContent(p.output.String())
+		p.viewport.GotoBottom()
+		return p, msg.Next
+
 	case west.CommandResultMsg:
 		if p.settingUp {
 			if msg.RequestID != p.activeRequestID {
@@ -141,7 +151,9 @@ func (p *WorkspacePage) Update(msg tea.Msg) (app.Page, tea.Cmd) {
 
 		p.updating = false
 		p.activeRequestID = ""
-		p.output.WriteString(msg.Output)
+		if !msg.Streamed {
+			p.output.WriteString(msg.Output)
+		}
 		if msg.ExitCode == 0 {
 			p.message = "Update completed successfully"
 		} else {
@@ -191,23 +203,25 @@ func (p *WorkspacePage) startStep() tea.Cmd {
 
 	switch p.currentStep {
 	case stepBrewDeps:
-		return west.WithRequestID(requestID, p.runner.InstallBrewDeps())
+		return west.WithRequestID(requestID, p.runner.InstallBrewDeps(context.Background()))
 	case stepInit:
-		return west.WithRequestID(requestID, p.runner.Init())
+		return west.WithRequestID(requestID, p.runner.Init(context.Background()))
 	case stepUpdate:
-		return west.WithRequestID(requestID, p.runner.Update())
+		return west.WithRequestID(requestID, p.runner.Update(context.Background()))
 	case stepExport:
-		return west.WithRequestID(requestID, p.runner.ZephyrExport())
+		return west.WithRequestID(requestID, p.runner.ZephyrExport(context.Background()))
 	case stepPipInstall:
-		return west.WithRequestID(requestID, p.runner.PackagesPipInstall())
+		return west.WithRequestID(requestID, p.runner.PackagesPipInstall(context.Background()))
 	case stepSdkInstall:
-		return west.WithRequestID(requestID, p.runner.SdkInstall())
+		return west.WithRequestID(requestID, p.runner.SdkInstall(context.Background()))
 	}
 	return nil
 }
 
 func (p *WorkspacePage) handleSetupResult(msg west.CommandResultMsg) tea.Cmd {
-	p.output.WriteString(msg.Output)
+	if !msg.Streamed {
+		p.output.WriteString(msg.Output)
+	}
 
 	if msg.ExitCode != 0 {
 		p.setupFailed = true
@@ -1,6 +1,7 @@
 package pages
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -91,3 +92,26 @@ func TestWorkspacePageUpdateKeyUsesInjectedRunner(t *testing.T) {
 		t.Fatalf("expected update command to use runner once, got %d", fake.updateCalls)
 	}
 }
+
+func TestWorkspacePageReplaysUpdate(t *testing.T) {
+	ws := &west.Workspace{Initialized: true}
+	replay := newReplayRunner(t, "workspace_update", t.TempDir())
+	p := NewWorkspacePage(ws, replay)
+
+	page, cmd := p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	p = followCommand(t, page, cmd).(*WorkspacePage)
+
+	if p.updating {
+		t.Fatal("expected update to finish")
+	}
+	if p.message != "Update completed successfully" {
+		t.Fatalf("expected success message, got %q", p.message)
+	}
+	out := p.output.String()
+	if !strings.Contains(out, "=== updating hal_nordic (modules/hal/nordic):") {
+		t.Fatalf("expected replayed update output, got %q", out)
+	}
+	if !strings.Contains(out, "in 41.207s") {
+		t.Fatalf("expected recorded duration, got %q", out)
+	}
+}
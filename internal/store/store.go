@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 )
 
 // Store manages persistence of build/flash/test records and serial logs.
@@ -27,9 +28,141 @@ func (s *Store) logsDir() string {
 	return filepath.Join(s.root, "logs")
 }
 
-// AddBuild appends a build record.
+// ArchivesKept is how many archived builds are kept per project and board.
+// Older builds stay in history without their archive.
+const ArchivesKept = 5
+
+// AddBuild appends a build record, assigning it an ID if it has none. When
+// the build was archived, archives beyond ArchivesKept for its project and
+// board are deleted.
 func (s *Store) AddBuild(r BuildRecord) error {
-	return s.appendRecord("builds.json", r)
+	if r.ID == "" {
+		r.ID = NewRecordID(r.Timestamp)
+	}
+	if err := s.appendRecord("builds.json", r); err != nil {
+		return err
+	}
+	if r.ArchiveDir == "" {
+		return nil
+	}
+	return s.pruneArchives(r.App, r.Board, ArchivesKept)
+}
+
+// pruneArchives deletes the archives of all but the newest keep archived
+// builds of app for board and clears their records' ArchiveDir.
+func (s *Store) pruneArchives(app, board string, keep int) error {
+	var pruned []string
+	err := s.rewriteBuilds(func(records []BuildRecord) []BuildRecord {
+		kept := 0
+		for i := len(records) - 1; i >= 0; i-- {
+			r := &records[i]
+			if r.ArchiveDir == "" || r.App != app || r.Board != board {
+				continue
+			}
+			if kept < keep {
+				kept++
+				continue
+			}
+			if r.ID != "" {
+				pruned = append(pruned, r.ID)
+			}
+			r.ArchiveDir = ""
+		}
+		// Never delete an archive a remaining record still points at.
+		inUse := map[string]bool{}
+		for _, r := range records {
+			if r.ArchiveDir != "" {
+				inUse[r.ID] = true
+			}
+		}
+		unused := pruned[:0]
+		for _, id := range pruned {
+			if !inUse[id] {
+				unused = append(unused, id)
+			}
+		}
+		pruned = unused
+		return records
+	})
+	if err != nil {
+		return err
+	}
+	for _, id := range pruned {
+		if err := os.RemoveAll(s.ArtifactsDir(id)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateBuild applies fn to the build record with the given ID, for details
+// gathered after the build was recorded. Unknown IDs are ignored.
+func (s *Store) UpdateBuild(id string, fn func(*BuildRecord)) error {
+	return s.rewriteBuilds(func(records []BuildRecord) []BuildRecord {
+		for i := range records {
+			if records[i].ID == id {
+				fn(&records[i])
+			}
+		}
+		return records
+	})
+}
+
+// rewriteBuilds replaces the build records with what fn returns for them.
+func (s *Store) rewriteBuilds(fn func([]BuildRecord) []BuildRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := filepath.Join(s.historyDir(), "builds.json")
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	var records []BuildRecord
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &records); err != nil {
+			return fmt.Errorf("invalid %s: %w", path, err)
+		}
+	}
+	data, err = json.MarshalIndent(fn(records), "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0o644)
+}
+
+// issuedIDs holds the record IDs handed out by this process.
+var (
+	issuedIDsMu sync.Mutex
+	issuedIDs   = map[string]bool{}
+)
+
+// NewRecordID returns an ID for a record started at t. IDs sort by time and
+// are safe to use as directory names. They are unique within the process:
+// records started in the same microsecond, such as matrix cells, get the
+// next free microsecond.
+func NewRecordID(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	t = t.UTC().Truncate(time.Microsecond)
+	issuedIDsMu.Lock()
+	defer issuedIDsMu.Unlock()
+	for {
+		id := t.Format("20060102-150405.000000")
+		if !issuedIDs[id] {
+			issuedIDs[id] = true
+			return id
+		}
+		t = t.Add(time.Microsecond)
+	}
+}
+
+// ArtifactsDir returns the directory a build's outputs are archived in.
+func (s *Store) ArtifactsDir(id string) string {
+	return filepath.Join(s.root, "artifacts", id)
 }
 
 // AddFlash appends a flash record.
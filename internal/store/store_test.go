@@ -1,6 +1,7 @@
 package store
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -90,3 +91,65 @@ func TestAddBuildFailsOnCorruptHistoryFile(t *testing.T) {
 		t.Fatal("expected AddBuild to fail with corrupt history file")
 	}
 }
+
+func TestUpdateBuild(t *testing.T) {
+	s := New(t.TempDir())
+	s.AddBuild(BuildRecord{ID: "a", Board: "board1"})
+	s.AddBuild(BuildRecord{ID: "b", Board: "board2"})
+
+	if err := s.UpdateBuild("b", func(r *BuildRecord) { r.Provenance = &Provenance{ZephyrVersion: "3.7.0"} }); err != nil {
+		t.Fatalf("UpdateBuild failed: %v", err)
+	}
+	builds, _ := s.Builds()
+	if len(builds) != 2 || builds[0].Provenance != nil || builds[1].Provenance == nil || builds[1].Provenance.ZephyrVersion != "3.7.0" {
+		t.Fatalf("expected only build b to be updated, got %+v", builds)
+	}
+}
+
+func TestAddBuildPrunesOldArchives(t *testing.T) {
+	s := New(t.TempDir())
+	add := func(id, board string) {
+		t.Helper()
+		if err := os.MkdirAll(s.ArtifactsDir(id), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.AddBuild(BuildRecord{ID: id, App: "app", Board: board, ArchiveDir: s.ArtifactsDir(id)}); err != nil {
+			t.Fatalf("AddBuild failed: %v", err)
+		}
+	}
+	add("other", "board2")
+	for i := 0; i <= ArchivesKept; i++ {
+		add(fmt.Sprintf("b%d", i), "board1")
+	}
+
+	builds, _ := s.Builds()
+	if len(builds) != ArchivesKept+2 {
+		t.Fatalf("expected every build kept in history, got %d", len(builds))
+	}
+	if builds[1].ID != "b0" || builds[1].ArchiveDir != "" {
+		t.Fatalf("expected the oldest board1 archive pruned, got %+v", builds[1])
+	}
+	if _, err := os.Stat(s.ArtifactsDir("b0")); !os.IsNotExist(err) {
+		t.Fatalf("expected b0's archive deleted, got %v", err)
+	}
+	for _, id := range []string{"other", "b1", fmt.Sprintf("b%d", ArchivesKept)} {
+		if _, err := os.Stat(s.ArtifactsDir(id)); err != nil {
+			t.Fatalf("expected %s's archive kept: %v", id, err)
+		}
+	}
+}
+
+func TestAddBuildGivesRecordsStartedTogetherDistinctIDs(t *testing.T) {
+	s := New(t.TempDir())
+	start := time.Now()
+	s.AddBuild(BuildRecord{Board: "board1", Timestamp: start})
+	s.AddBuild(BuildRecord{Board: "board2", Timestamp: start})
+
+	builds, _ := s.Builds()
+	if len(builds) != 2 || builds[0].ID == "" || builds[0].ID == builds[1].ID {
+		t.Fatalf("expected distinct IDs, got %+v", builds)
+	}
+	if s.ArtifactsDir(builds[0].ID) == s.ArtifactsDir(builds[1].ID) {
+		t.Fatal("expected distinct artifacts directories")
+	}
+}
@@ -4,20 +4,86 @@ import "time"
 
 // BuildRecord captures the result of a build operation.
 type BuildRecord struct {
-	Board     string    `json:"board"`
-	App       string    `json:"app"`
-	Timestamp time.Time `json:"timestamp"`
-	Success   bool      `json:"success"`
-	Duration  string    `json:"duration"`
-	Artifacts []string  `json:"artifacts"`
-	Shield     string    `json:"shield,omitempty"`
-	Pristine   bool      `json:"pristine,omitempty"`
-	CMakeArgs  string    `json:"cmake_args,omitempty"`
-	GitBranch  string    `json:"git_branch,omitempty"`
-	GitCommit  string    `json:"git_commit,omitempty"`
-	GitDirty   bool      `json:"git_dirty,omitempty"`
-	BuildDir   string    `json:"build_dir,omitempty"`
-	BinarySize int64     `json:"binary_size,omitempty"`
+	ID         string        `json:"id,omitempty"`
+	Board      string        `json:"board"`
+	App        string        `json:"app"`
+	Timestamp  time.Time     `json:"timestamp"`
+	Success    bool          `json:"success"`
+	Duration   string        `json:"duration"`
+	Artifacts  []string      `json:"artifacts"`
+	Shield     string        `json:"shield,omitempty"`
+	Pristine   bool          `json:"pristine,omitempty"`
+	CMakeArgs  string        `json:"cmake_args,omitempty"`
+	GitBranch  string        `json:"git_branch,omitempty"`
+	GitCommit  string        `json:"git_commit,omitempty"`
+	GitDirty   bool          `json:"git_dirty,omitempty"`
+	BuildDir   string        `json:"build_dir,omitempty"`
+	BinarySize int64         `json:"binary_size,omitempty"`
+	Cancelled  bool          `json:"cancelled,omitempty"`
+	Errors     int           `json:"errors,omitempty"`
+	Warnings   int           `json:"warnings,omitempty"`
+	Sizes      *SectionSizes `json:"sizes,omitempty"`
+	Sysbuild   bool          `json:"sysbuild,omitempty"`
+	Profile    string        `json:"profile,omitempty"`
+	// Extra Kconfig fragments, devicetree overlays and snippets.
+	ExtraConf    []string       `json:"extra_conf,omitempty"`
+	ExtraOverlay []string       `json:"extra_overlay,omitempty"`
+	Snippets     []string       `json:"snippets,omitempty"`
+	Domains      []DomainRecord `json:"domains,omitempty"`
+	// ArchiveDir holds copies of the build's outputs, see Store.ArtifactsDir.
+	ArchiveDir string       `json:"archive_dir,omitempty"`
+	Provenance *Provenance  `json:"provenance,omitempty"`
+	Timing     *BuildTiming `json:"timing,omitempty"`
+}
+
+// BuildTiming summarises where a successful build's time went, from the
+// .ninja_log of its build directory. Times are in milliseconds.
+type BuildTiming struct {
+	Steps   int          `json:"steps"`
+	CPU     int64        `json:"cpu_ms"`
+	Wall    int64        `json:"wall_ms"`
+	Modules []StepTiming `json:"modules,omitempty"` // slowest first
+	Slowest []StepTiming `json:"slowest,omitempty"` // compile and link steps
+}
+
+// StepTiming is the time spent on a module or on one ninja output.
+type StepTiming struct {
+	Name   string `json:"name"`
+	Module string `json:"module,omitempty"`
+	Time   int64  `json:"ms"`
+	Steps  int    `json:"steps,omitempty"`
+	Link   bool   `json:"link,omitempty"`
+}
+
+// Provenance records the Zephyr tree, toolchain, host tools and west
+// project revisions a successful build was made with.
+type Provenance struct {
+	ZephyrVersion string            `json:"zephyr_version,omitempty"`
+	Toolchain     string            `json:"toolchain,omitempty"`
+	SDKVersion    string            `json:"sdk_version,omitempty"`
+	CMakeVersion  string            `json:"cmake_version,omitempty"`
+	NinjaVersion  string            `json:"ninja_version,omitempty"`
+	Manifest      map[string]string `json:"manifest,omitempty"` // project → frozen revision
+}
+
+// DomainRecord describes one image of a sysbuild build.
+type DomainRecord struct {
+	Name       string        `json:"name"`
+	BuildDir   string        `json:"build_dir"`
+	Artifacts  []string      `json:"artifacts,omitempty"`
+	BinarySize int64         `json:"binary_size,omitempty"`
+	Sizes      *SectionSizes `json:"sizes,omitempty"`
+}
+
+// SectionSizes holds the section totals read from zephyr.elf after a
+// successful build.
+type SectionSizes struct {
+	Text   int64 `json:"text"`
+	ROData int64 `json:"rodata"`
+	Data   int64 `json:"data"`
+	BSS    int64 `json:"bss"`
+	Flash  int64 `json:"flash"`
+	RAM    int64 `json:"ram"`
 }
 
 // FlashRecord captures the result of a flash operation.
@@ -26,6 +92,9 @@ type FlashRecord struct {
 	Timestamp time.Time `json:"timestamp"`
 	Success   bool      `json:"success"`
 	Duration  string    `json:"duration"`
+	Cancelled bool      `json:"cancelled,omitempty"`
+	Domain    string    `json:"domain,omitempty"`
+	BuildID   string    `json:"build_id,omitempty"` // archived build that was flashed
 }
 
 // TestRecord captures the result of a test run.
@@ -35,6 +104,7 @@ type TestRecord struct {
 	Success   bool      `json:"success"`
 	Duration  string    `json:"duration"`
 	Output    string    `json:"output,omitempty"`
+	Cancelled bool      `json:"cancelled,omitempty"`
 }
 
 // SerialLog tracks a serial logging session.
@@ -81,3 +81,8 @@ func SuccessBadge(text string) string {
 func ErrorBadge(text string) string {
 	return Badge(text, Error)
 }
+
+// WarningBadge renders an amber badge.
+func WarningBadge(text string) string {
+	return Badge(text, Warning)
+}
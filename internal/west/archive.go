@@ -0,0 +1,189 @@
+package west
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/buckleypaul/gust/internal/store"
+)
+
+// ArchiveManifestName is the manifest written into every archive directory.
+const ArchiveManifestName = "manifest.json"
+
+// archivedNames are the files kept from each image directory, relative to
+// it. CMakeCache.txt and runners.yaml are what `west flash` needs to flash
+// from the archive instead of the original build directory.
+var archivedNames = []string{
+	"CMakeCache.txt",
+	"zephyr/zephyr.signed.hex",
+	"zephyr/zephyr.signed.bin",
+	"zephyr/zephyr.hex",
+	"zephyr/zephyr.bin",
+	"zephyr/zephyr.uf2",
+	"zephyr/zephyr.elf",
+	"zephyr/zephyr.exe",
+	"zephyr/zephyr.map",
+	"zephyr/.config",
+	"zephyr/zephyr.dts",
+	"zephyr/runners.yaml",
+}
+
+// ArchiveManifest describes an archived build.
+type ArchiveManifest struct {
+	ID        string         `json:"id"`
+	Board     string         `json:"board"`
+	App       string         `json:"app"`
+	GitCommit string         `json:"git_commit,omitempty"`
+	BuildDir  string         `json:"build_dir"`
+	Created   time.Time      `json:"created"`
+	Files     []ArchivedFile `json:"files"`
+}
+
+// ArchivedFile is one file in an archive, relative to the archive directory.
+type ArchivedFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ArchiveBuild copies the key outputs of the build in buildDir into dest and
+// writes a manifest there. The directory layout is kept, sysbuild domains
+// included, so dest can be passed to `west flash -d`.
+func ArchiveBuild(wsRoot, buildDir, dest string, m ArchiveManifest) (*ArchiveManifest, error) {
+	src := BuildDirPath(wsRoot, buildDir)
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return nil, err
+	}
+	m.Files = nil
+
+	dirs := []string{""}
+	if domains, err := ReadDomains(wsRoot, buildDir); err == nil {
+		dirs = domains.Domains
+		absDest, err := filepath.Abs(dest)
+		if err != nil {
+			return nil, err
+		}
+		if err := archiveDomainsYAML(src, absDest); err != nil {
+			return nil, err
+		}
+		m.Files = append(m.Files, ArchivedFile{Path: "domains.yaml"})
+	}
+	for _, dir := range dirs {
+		for _, name := range archivedNames {
+			rel := filepath.Join(dir, filepath.FromSlash(name))
+			f, err := copyArchived(filepath.Join(src, rel), filepath.Join(dest, rel))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			f.Path = filepath.ToSlash(rel)
+			m.Files = append(m.Files, f)
+		}
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dest, ArchiveManifestName), data, 0o644); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ArchiveRecord archives the outputs of the successful build r describes
+// into the store's artifacts directory and points the record at it,
+// assigning the record its ID if it has none. The path is kept
+// workspace-relative so it can be passed to west like any build dir.
+func ArchiveRecord(r *store.BuildRecord, s *store.Store, wsRoot string) error {
+	if r.ID == "" {
+		r.ID = store.NewRecordID(r.Timestamp)
+		// Another gust process may have archived a build under the same ID.
+		for {
+			if _, err := os.Stat(s.ArtifactsDir(r.ID)); os.IsNotExist(err) {
+				break
+			}
+			r.ID = store.NewRecordID(r.Timestamp)
+		}
+	}
+	dest := s.ArtifactsDir(r.ID)
+	_, err := ArchiveBuild(wsRoot, r.BuildDir, dest, ArchiveManifest{
+		ID:        r.ID,
+		Board:     r.Board,
+		App:       r.App,
+		GitCommit: r.GitCommit,
+		BuildDir:  r.BuildDir,
+		Created:   r.Timestamp,
+	})
+	if err != nil {
+		return err
+	}
+	if rel, err := filepath.Rel(wsRoot, dest); err == nil && !strings.HasPrefix(rel, "..") {
+		dest = rel
+	}
+	r.ArchiveDir = dest
+	return nil
+}
+
+// ReadArchiveManifest loads the manifest of an archive directory.
+func ReadArchiveManifest(dir string) (*ArchiveManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ArchiveManifestName))
+	if err != nil {
+		return nil, err
+	}
+	var m ArchiveManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ArchiveFlashArgs returns the `west flash` arguments for an archive
+// directory. The archive holds no sources, so west must not try to rebuild.
+func ArchiveFlashArgs(dir, runner, domain string) []string {
+	return append(FlashArgs(dir, runner, domain), "--skip-rebuild")
+}
+
+// copyArchived copies src to dst and returns its size and checksum.
+func copyArchived(src, dst string) (ArchivedFile, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return ArchivedFile{}, err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return ArchivedFile{}, err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return ArchivedFile{}, err
+	}
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(out, h), in)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return ArchivedFile{}, err
+	}
+	return ArchivedFile{Size: n, SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// archiveDomainsYAML copies domains.yaml with its build directories pointed
+// at the archive, so `west flash --domain` finds the archived images.
+func archiveDomainsYAML(src, dest string) error {
+	data, err := os.ReadFile(filepath.Join(src, "domains.yaml"))
+	if err != nil {
+		return err
+	}
+	out := strings.ReplaceAll(string(data), src, dest)
+	return os.WriteFile(filepath.Join(dest, "domains.yaml"), []byte(out), 0o644)
+}
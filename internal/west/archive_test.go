@@ -0,0 +1,125 @@
+package west
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/buckleypaul/gust/internal/store"
+)
+
+func TestArchiveBuildCopiesOutputsAndWritesManifest(t *testing.T) {
+	wsRoot := t.TempDir()
+	for name, content := range map[string]string{
+		"build/CMakeCache.txt":        "BOARD:STRING=nrf52840dk\n",
+		"build/zephyr/zephyr.hex":     ":00000001FF\n",
+		"build/zephyr/zephyr.elf":     "ELF",
+		"build/zephyr/.config":        "CONFIG_GPIO=y\n",
+		"build/zephyr/runners.yaml":   "flash-runner: jlink\n",
+		"build/zephyr/zephyr.obj.tmp": "ignored",
+	} {
+		path := filepath.Join(wsRoot, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dest := filepath.Join(wsRoot, ".gust", "artifacts", "b1")
+	m, err := ArchiveBuild(wsRoot, "build", dest, ArchiveManifest{ID: "b1", Board: "nrf52840dk"})
+	if err != nil {
+		t.Fatalf("ArchiveBuild: %v", err)
+	}
+	var paths []string
+	for _, f := range m.Files {
+		paths = append(paths, f.Path)
+	}
+	if got := strings.Join(paths, " "); got != "CMakeCache.txt zephyr/zephyr.hex zephyr/zephyr.elf zephyr/.config zephyr/runners.yaml" {
+		t.Fatalf("archived files = %q", got)
+	}
+	if m.Files[1].Size != 12 || len(m.Files[1].SHA256) != 64 {
+		t.Fatalf("expected size and checksum for zephyr.hex, got %+v", m.Files[1])
+	}
+	if _, err := os.Stat(filepath.Join(dest, "zephyr", "zephyr.hex")); err != nil {
+		t.Fatalf("expected zephyr.hex in archive: %v", err)
+	}
+
+	read, err := ReadArchiveManifest(dest)
+	if err != nil || read.ID != "b1" || len(read.Files) != 5 {
+		t.Fatalf("ReadArchiveManifest = %+v, %v", read, err)
+	}
+}
+
+func TestArchiveBuildRewritesSysbuildDomains(t *testing.T) {
+	wsRoot := t.TempDir()
+	src := filepath.Join(wsRoot, "build")
+	yaml := "default: app\nbuild_dir: " + src + "\ndomains:\n- name: app\n  build_dir: " + filepath.Join(src, "app") + "\n"
+	for name, content := range map[string]string{
+		"build/domains.yaml":          yaml,
+		"build/app/zephyr/zephyr.hex": "hex",
+	} {
+		path := filepath.Join(wsRoot, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dest := filepath.Join(wsRoot, "archive")
+	if _, err := ArchiveBuild(wsRoot, "build", dest, ArchiveManifest{}); err != nil {
+		t.Fatalf("ArchiveBuild: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "app", "zephyr", "zephyr.hex")); err != nil {
+		t.Fatalf("expected domain image in archive: %v", err)
+	}
+	d, err := ReadDomains(wsRoot, dest)
+	if err != nil || d.Default != "app" {
+		t.Fatalf("ReadDomains(archive) = %+v, %v", d, err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dest, "domains.yaml"))
+	if !strings.Contains(string(data), "build_dir: "+filepath.Join(dest, "app")) {
+		t.Fatalf("expected domain build dir pointed at the archive, got:\n%s", data)
+	}
+}
+
+func TestArchiveRecordGivesBuildsStartedTogetherTheirOwnArchive(t *testing.T) {
+	wsRoot := t.TempDir()
+	for _, dir := range []string{"build-a", "build-b"} {
+		path := filepath.Join(wsRoot, dir, "zephyr", "zephyr.hex")
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(dir), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := store.New(filepath.Join(wsRoot, ".gust"))
+	start := time.Now()
+
+	for _, dir := range []string{"build-a", "build-b"} {
+		r := store.BuildRecord{App: "app", Board: dir, BuildDir: dir, Timestamp: start, Success: true}
+		if err := ArchiveRecord(&r, s, wsRoot); err != nil {
+			t.Fatalf("ArchiveRecord(%s): %v", dir, err)
+		}
+		if err := s.AddBuild(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	builds, _ := s.Builds()
+	if len(builds) != 2 || builds[0].ID == builds[1].ID || builds[0].ArchiveDir == builds[1].ArchiveDir {
+		t.Fatalf("expected distinct IDs and archives, got %+v", builds)
+	}
+	for _, b := range builds {
+		data, err := os.ReadFile(filepath.Join(wsRoot, b.ArchiveDir, "zephyr", "zephyr.hex"))
+		if err != nil || string(data) != b.BuildDir {
+			t.Fatalf("archive of %s holds %q, %v", b.BuildDir, data, err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package west
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// BuildOptions describes a `west build` invocation. Project must already be
+// an absolute path or one relative to the current directory.
+type BuildOptions struct {
+	Board        string
+	Shield       string // one or more shields, separated by spaces or commas
+	BuildDir     string
+	Project      string
+	Pristine     bool
+	Sysbuild     bool
+	CMakeArgs    string   // extra CMake arguments, split on whitespace
+	ExtraConf    []string // EXTRA_CONF_FILE entries
+	ExtraOverlay []string // EXTRA_DTC_OVERLAY_FILE entries
+	Snippets     []string
+}
+
+// BuildArgs returns the arguments (without the leading "west") for o.
+func BuildArgs(o BuildOptions) []string {
+	args := []string{"build", "-b", o.Board}
+	if o.BuildDir != "" {
+		args = append(args, "-d", o.BuildDir)
+	}
+	if o.Pristine {
+		args = append(args, "-p", "always")
+	}
+	if o.Sysbuild {
+		args = append(args, "--sysbuild")
+	}
+	for _, shield := range SplitShields(o.Shield) {
+		args = append(args, "--shield", shield)
+	}
+	for _, snippet := range o.Snippets {
+		args = append(args, "-S", snippet)
+	}
+	// The source directory goes before "--": west hands everything after
+	// it to CMake.
+	if o.Project != "" {
+		args = append(args, o.Project)
+	}
+	var cmake []string
+	if len(o.ExtraConf) > 0 {
+		cmake = append(cmake, "-DEXTRA_CONF_FILE="+strings.Join(o.ExtraConf, ";"))
+	}
+	if len(o.ExtraOverlay) > 0 {
+		cmake = append(cmake, "-DEXTRA_DTC_OVERLAY_FILE="+strings.Join(o.ExtraOverlay, ";"))
+	}
+	cmake = append(cmake, strings.Fields(o.CMakeArgs)...)
+	if len(cmake) > 0 {
+		args = append(args, "--")
+		args = append(args, cmake...)
+	}
+	return args
+}
+
+// SplitShields splits a shield setting into individual shield names.
+func SplitShields(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '\t'
+	})
+}
+
+// FlashArgs returns the `west flash` arguments for buildDir, an optional
+// runner override and, for sysbuild, an optional single domain.
+func FlashArgs(buildDir, runner, domain string) []string {
+	args := []string{"flash"}
+	if buildDir != "" {
+		args = append(args, "-d", buildDir)
+	}
+	if domain != "" {
+		args = append(args, "--domain", domain)
+	}
+	if runner != "" {
+		args = append(args, "--runner", runner)
+	}
+	return args
+}
+
+// TestArgs returns the arguments that build and run the project's "run"
+// target. Empty values are left for west to default.
+func TestArgs(board, buildDir, project string) []string {
+	args := []string{"build", "-t", "run"}
+	if board != "" {
+		args = append(args, "-b", board)
+	}
+	if buildDir != "" {
+		args = append(args, "-d", buildDir)
+	}
+	if project != "" {
+		args = append(args, project)
+	}
+	return args
+}
+
+// ProjectPath resolves a workspace-relative project path against wsRoot.
+// Absolute paths and the empty string are returned unchanged.
+func ProjectPath(wsRoot, project string) string {
+	if project == "" || filepath.IsAbs(project) {
+		return project
+	}
+	return filepath.Join(wsRoot, project)
+}
@@ -0,0 +1,29 @@
+package west
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildArgsWithShieldsSnippetsAndExtraFiles(t *testing.T) {
+	got := BuildArgs(BuildOptions{
+		Board:        "nrf52840dk",
+		Shield:       "adafruit_2_8_tft_touch_v2, nrf7002ek",
+		BuildDir:     "build",
+		Project:      "/ws/app",
+		ExtraConf:    []string{"debug.conf", "usb.conf"},
+		ExtraOverlay: []string{"usb.overlay"},
+		Snippets:     []string{"rtt-console"},
+		CMakeArgs:    "-DFOO=1",
+	})
+	want := []string{
+		"build", "-b", "nrf52840dk", "-d", "build",
+		"--shield", "adafruit_2_8_tft_touch_v2", "--shield", "nrf7002ek",
+		"-S", "rtt-console",
+		"/ws/app",
+		"--", "-DEXTRA_CONF_FILE=debug.conf;usb.conf", "-DEXTRA_DTC_OVERLAY_FILE=usb.overlay", "-DFOO=1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("BuildArgs =\n %v\nwant\n %v", got, want)
+	}
+}
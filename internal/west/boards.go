@@ -2,6 +2,8 @@ package west
 
 import (
 	"bufio"
+	"context"
+	"fmt"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -20,14 +22,19 @@ type BoardsLoadedMsg struct {
 	Err    error
 }
 
-// ListBoards runs `west boards` and parses the output into Board structs.
-func ListBoards() tea.Cmd {
+// ListBoards runs `west boards` through r and parses the output into Board
+// structs.
+func ListBoards(r Runner) tea.Cmd {
+	cmd := r.Run(context.Background(), "west", "boards")
 	return func() tea.Msg {
-		output, err := RunSimple("west", "boards")
-		if err != nil {
-			return BoardsLoadedMsg{Err: err}
+		result, ok := Drain(cmd, nil).(CommandResultMsg)
+		if !ok {
+			return BoardsLoadedMsg{Err: fmt.Errorf("west boards: unexpected result")}
 		}
-		boards := parseBoards(output)
+		if result.ExitCode != 0 {
+			return BoardsLoadedMsg{Err: fmt.Errorf("west boards: exit status %d", result.ExitCode)}
+		}
+		boards := parseBoards(result.Output)
 		return BoardsLoadedMsg{Boards: boards}
 	}
 }
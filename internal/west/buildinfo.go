@@ -0,0 +1,82 @@
+package west
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// GitState describes the checkout a build was made from.
+type GitState struct {
+	Branch string
+	Commit string // abbreviated to 8 characters
+	Dirty  bool
+}
+
+// ReadGitState inspects the git checkout containing dir. Fields that cannot
+// be determined (not a repository, git missing) are left empty.
+func ReadGitState(dir string) GitState {
+	var g GitState
+	if o, err := gitOutput(dir, "branch", "--show-current"); err == nil {
+		g.Branch = strings.TrimSpace(o)
+	}
+	if o, err := gitOutput(dir, "rev-parse", "--short=8", "HEAD"); err == nil {
+		g.Commit = strings.TrimSpace(o)
+	}
+	// Untracked files do not count: the compile_commands.json link kept in
+	// the project would otherwise make every build dirty.
+	if o, err := gitOutput(dir, "status", "--porcelain", "--untracked-files=no"); err == nil {
+		g.Dirty = strings.TrimSpace(o) != ""
+	}
+	return g
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// BinarySize returns the size of zephyr/zephyr.bin in buildDir, resolved
+// against wsRoot, or 0 if it does not exist.
+func BinarySize(wsRoot, buildDir string) int64 {
+	if fi, err := os.Stat(filepath.Join(BuildDirPath(wsRoot, buildDir), "zephyr", "zephyr.bin")); err == nil {
+		return fi.Size()
+	}
+	return 0
+}
+
+// ExpandBuildDir fills in a build directory template. {project}, {board}
+// and {profile} are replaced with flattened names, so each combination
+// gets its own directory and keeps its incremental build; a template
+// without placeholders is returned unchanged.
+func ExpandBuildDir(tmpl, project, board, profile string) string {
+	if !strings.Contains(tmpl, "{") {
+		return tmpl
+	}
+	if project = matrixDirName(project); project == "" || project == "." {
+		project = "app"
+	}
+	if profile == "" {
+		profile = "default"
+	}
+	return strings.NewReplacer(
+		"{project}", project,
+		"{board}", matrixDirName(board),
+		"{profile}", matrixDirName(profile),
+	).Replace(tmpl)
+}
+
+// BuildDirPath resolves buildDir against wsRoot. An empty buildDir means
+// west's default, "build".
+func BuildDirPath(wsRoot, buildDir string) string {
+	if buildDir == "" {
+		buildDir = "build"
+	}
+	if !filepath.IsAbs(buildDir) {
+		buildDir = filepath.Join(wsRoot, buildDir)
+	}
+	return buildDir
+}
@@ -0,0 +1,54 @@
+package west
+
+import (
+	"time"
+
+	"github.com/buckleypaul/gust/internal/store"
+)
+
+// NewBuildRecord starts the history record of a build run with o. app is
+// the project as it was selected, profile the active build profile and
+// start when the build began. o.Project is not recorded.
+func NewBuildRecord(o BuildOptions, app, profile string, git GitState, start time.Time, result CommandResultMsg, diag *DiagnosticParser) store.BuildRecord {
+	r := store.BuildRecord{
+		Board:        o.Board,
+		App:          app,
+		Timestamp:    start,
+		Success:      result.ExitCode == 0 && !result.Cancelled,
+		Duration:     result.Duration.String(),
+		Shield:       o.Shield,
+		Pristine:     o.Pristine,
+		Sysbuild:     o.Sysbuild,
+		Profile:      profile,
+		CMakeArgs:    o.CMakeArgs,
+		ExtraConf:    o.ExtraConf,
+		ExtraOverlay: o.ExtraOverlay,
+		Snippets:     o.Snippets,
+		GitBranch:    git.Branch,
+		GitCommit:    git.Commit,
+		GitDirty:     git.Dirty,
+		BuildDir:     o.BuildDir,
+		Cancelled:    result.Cancelled,
+	}
+	r.Errors, r.Warnings = diag.Counts()
+	return r
+}
+
+// CompleteBuildRecord adds what a successful build left in its build
+// directory to r: its artifacts and sizes, the timing of its ninja run and,
+// when s is not nil, an archive of its outputs. Failed builds are left
+// alone. The error reports a failed archive; the record is complete
+// otherwise.
+func CompleteBuildRecord(r *store.BuildRecord, s *store.Store, wsRoot string) error {
+	if !r.Success {
+		return nil
+	}
+	RecordBuildOutputs(r, wsRoot)
+	if t, err := ReadBuildTiming(wsRoot, r.BuildDir, r.Timestamp); err == nil && len(t.Steps) > 0 {
+		r.Timing = TimingRecord(t)
+	}
+	if s == nil {
+		return nil
+	}
+	return ArchiveRecord(r, s, wsRoot)
+}
@@ -0,0 +1,45 @@
+package west
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/buckleypaul/gust/internal/store"
+)
+
+func TestBuildRecordFailedBuildIsNotCompleted(t *testing.T) {
+	var diag DiagnosticParser
+	diag.Feed("/ws/a.c:1:1: error: boom")
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	r := NewBuildRecord(BuildOptions{Board: "nrf52840dk", BuildDir: "build", Pristine: true}, "app", "release",
+		GitState{Branch: "main", Commit: "abc123"}, start, CommandResultMsg{ExitCode: 1, Duration: time.Second}, &diag)
+	if r.Success || r.Board != "nrf52840dk" || r.Profile != "release" || r.GitBranch != "main" || !r.Pristine || r.Errors != 1 {
+		t.Fatalf("unexpected record %+v", r)
+	}
+	if err := CompleteBuildRecord(&r, store.New(t.TempDir()), t.TempDir()); err != nil || r.ID != "" || r.ArchiveDir != "" {
+		t.Fatalf("expected failed build to be left alone, got %+v, %v", r, err)
+	}
+}
+
+func TestBuildRecordCompletedBuildIsArchived(t *testing.T) {
+	wsRoot := t.TempDir()
+	hex := filepath.Join(wsRoot, "build", "zephyr", "zephyr.hex")
+	if err := os.MkdirAll(filepath.Dir(hex), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(hex, []byte(":00000001FF\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var diag DiagnosticParser
+	r := NewBuildRecord(BuildOptions{Board: "nrf52840dk", BuildDir: "build"}, "app", "", GitState{}, time.Now(),
+		CommandResultMsg{Duration: time.Second}, &diag)
+	if err := CompleteBuildRecord(&r, store.New(filepath.Join(wsRoot, ".gust")), wsRoot); err != nil {
+		t.Fatalf("CompleteBuildRecord: %v", err)
+	}
+	if !r.Success || r.ID == "" || r.ArchiveDir == "" {
+		t.Fatalf("expected successful build to be archived, got %+v", r)
+	}
+}
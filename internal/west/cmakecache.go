@@ -0,0 +1,219 @@
+package west
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CacheEntry is one variable from CMakeCache.txt.
+type CacheEntry struct {
+	Name  string
+	Type  string // BOOL, STRING, PATH, FILEPATH, INTERNAL, STATIC or UNINITIALIZED
+	Value string
+	Help  string // the // comment lines above the entry
+}
+
+// CMakeCachePath returns the CMakeCache.txt of buildDir, resolved against
+// wsRoot.
+func CMakeCachePath(wsRoot, buildDir string) string {
+	return filepath.Join(BuildDirPath(wsRoot, buildDir), "CMakeCache.txt")
+}
+
+// ReadCMakeCache parses a CMakeCache.txt in file order.
+func ReadCMakeCache(path string) ([]CacheEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []CacheEntry
+	var help []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			help = nil
+			continue
+		case strings.HasPrefix(line, "//"):
+			help = append(help, strings.TrimSpace(strings.TrimPrefix(line, "//")))
+			continue
+		case strings.HasPrefix(line, "#"):
+			continue
+		}
+		if e, ok := parseCacheLine(line); ok {
+			e.Help = strings.Join(help, " ")
+			entries = append(entries, e)
+		}
+		help = nil
+	}
+	return entries, scanner.Err()
+}
+
+// parseCacheLine splits NAME:TYPE=VALUE. Names may be quoted when they
+// contain a colon.
+func parseCacheLine(line string) (CacheEntry, bool) {
+	var name, rest string
+	if strings.HasPrefix(line, `"`) {
+		end := strings.Index(line[1:], `"`)
+		if end < 0 {
+			return CacheEntry{}, false
+		}
+		name, rest = line[1:end+1], line[end+2:]
+	} else {
+		i := strings.Index(line, ":")
+		if i < 0 {
+			return CacheEntry{}, false
+		}
+		name, rest = line[:i], line[i:]
+	}
+	typ, value, ok := strings.Cut(strings.TrimPrefix(rest, ":"), "=")
+	if !ok {
+		return CacheEntry{}, false
+	}
+	return CacheEntry{Name: name, Type: typ, Value: value}, true
+}
+
+// cacheValue returns the value of name, falling back to the CACHED_<name>
+// copy Zephyr keeps of the variables it checks between runs.
+func cacheValue(entries []CacheEntry, name string) string {
+	var cached string
+	for _, e := range entries {
+		switch e.Name {
+		case name:
+			return e.Value
+		case "CACHED_" + name:
+			cached = e.Value
+		}
+	}
+	return cached
+}
+
+// BuildDirConfig is the selection a build directory was last configured for.
+type BuildDirConfig struct {
+	Board     string
+	SourceDir string
+	Shield    string // shields separated by spaces, commas or semicolons
+	ConfFile  string
+}
+
+// ReadBuildDirConfig reads what buildDir was configured for from its
+// CMakeCache.txt. Sysbuild directories record the application in APP_DIR.
+func ReadBuildDirConfig(wsRoot, buildDir string) (*BuildDirConfig, error) {
+	entries, err := ReadCMakeCache(CMakeCachePath(wsRoot, buildDir))
+	if err != nil {
+		return nil, err
+	}
+	c := &BuildDirConfig{
+		Board:     cacheValue(entries, "BOARD"),
+		SourceDir: cacheValue(entries, "APPLICATION_SOURCE_DIR"),
+		Shield:    cacheValue(entries, "SHIELD"),
+		ConfFile:  cacheValue(entries, "CONF_FILE"),
+	}
+	if c.SourceDir == "" {
+		c.SourceDir = cacheValue(entries, "APP_DIR")
+	}
+	return c, nil
+}
+
+// ConfigDiff is one setting where a build directory's configuration differs
+// from the selection about to be built.
+type ConfigDiff struct {
+	Field    string
+	Cached   string
+	Selected string
+}
+
+// StaleConfig compares a build directory's configuration with the selection
+// to be built. A field the selection leaves empty is not compared, except
+// shields, where "none" differs from a cached shield.
+func StaleConfig(cached, selected BuildDirConfig) []ConfigDiff {
+	var diffs []ConfigDiff
+	if selected.Board != "" && cached.Board != "" && !sameBoard(cached.Board, selected.Board) {
+		diffs = append(diffs, ConfigDiff{"Board", cached.Board, selected.Board})
+	}
+	if selected.SourceDir != "" && cached.SourceDir != "" && !samePath(cached.SourceDir, selected.SourceDir) {
+		diffs = append(diffs, ConfigDiff{"App source", cached.SourceDir, selected.SourceDir})
+	}
+	if !sameList(cached.Shield, selected.Shield) {
+		diffs = append(diffs, ConfigDiff{"Shield", cached.Shield, selected.Shield})
+	}
+	if selected.ConfFile != "" && !sameList(cached.ConfFile, selected.ConfFile) {
+		diffs = append(diffs, ConfigDiff{"CONF_FILE", cached.ConfFile, selected.ConfFile})
+	}
+	return diffs
+}
+
+// sameBoard treats a board and its fully qualified form as the same, since
+// Zephyr caches the normalized name (nrf52840dk becomes nrf52840dk/nrf52840).
+func sameBoard(a, b string) bool {
+	return a == b || strings.HasPrefix(a, b+"/") || strings.HasPrefix(b, a+"/")
+}
+
+func samePath(a, b string) bool {
+	resolve := func(p string) string {
+		if r, err := filepath.EvalSymlinks(p); err == nil {
+			return r
+		}
+		return filepath.Clean(p)
+	}
+	return resolve(a) == resolve(b)
+}
+
+func sameList(a, b string) bool {
+	split := func(s string) []string {
+		return strings.FieldsFunc(s, func(r rune) bool {
+			return r == ' ' || r == ',' || r == ';' || r == '\t'
+		})
+	}
+	la, lb := split(a), split(b)
+	if len(la) != len(lb) {
+		return false
+	}
+	for i := range la {
+		if la[i] != lb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// CMakeDefine returns the value given to -D<name> in a CMake argument
+// string, accepting both -DNAME=value and -DNAME:TYPE=value.
+func CMakeDefine(args, name string) (string, bool) {
+	for _, arg := range strings.Fields(args) {
+		def, ok := strings.CutPrefix(arg, "-D")
+		if !ok {
+			continue
+		}
+		key, value, ok := strings.Cut(def, "=")
+		if !ok {
+			continue
+		}
+		if k, _, _ := strings.Cut(key, ":"); k == name {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// FromContainer maps the paths of a build configured inside a container,
+// where the workspace is mounted at mount, back to hostRoot.
+func (c *BuildDirConfig) FromContainer(mount, hostRoot string) {
+	c.SourceDir = translatePath(c.SourceDir, mount, hostRoot)
+	c.ConfFile = translatePath(c.ConfFile, mount, hostRoot)
+}
+
+// CMakeCacheSetArgs returns the `west build` arguments that reconfigure
+// buildDir with name set to value, without building.
+func CMakeCacheSetArgs(buildDir, name, value string) []string {
+	args := []string{"build"}
+	if buildDir != "" {
+		args = append(args, "-d", buildDir)
+	}
+	return append(args, "--cmake-only", "--", "-D"+name+"="+value)
+}
@@ -0,0 +1,89 @@
+package west
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testCMakeCache = `# This is the CMakeCache file.
+
+//Board to build for
+BOARD:STRING=nrf52840dk/nrf52840
+
+//No help, variable specified on the command line.
+CACHED_SHIELD:INTERNAL=nrf7002ek
+APPLICATION_SOURCE_DIR:PATH=/ws/apps/blinky
+"WEIRD:NAME":STRING=x
+CONF_FILE:INTERNAL=/ws/apps/blinky/prj.conf
+`
+
+func TestReadCMakeCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CMakeCache.txt")
+	if err := os.WriteFile(path, []byte(testCMakeCache), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ReadCMakeCache(path)
+	if err != nil {
+		t.Fatalf("ReadCMakeCache: %v", err)
+	}
+	want := []CacheEntry{
+		{"BOARD", "STRING", "nrf52840dk/nrf52840", "Board to build for"},
+		{"CACHED_SHIELD", "INTERNAL", "nrf7002ek", "No help, variable specified on the command line."},
+		{"APPLICATION_SOURCE_DIR", "PATH", "/ws/apps/blinky", ""},
+		{"WEIRD:NAME", "STRING", "x", ""},
+		{"CONF_FILE", "INTERNAL", "/ws/apps/blinky/prj.conf", ""},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Fatalf("ReadCMakeCache =\n%+v\nwant\n%+v", entries, want)
+	}
+}
+
+func TestStaleConfig(t *testing.T) {
+	wsRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(wsRoot, "build"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(CMakeCachePath(wsRoot, ""), []byte(testCMakeCache), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cached, err := ReadBuildDirConfig(wsRoot, "")
+	if err != nil {
+		t.Fatalf("ReadBuildDirConfig: %v", err)
+	}
+
+	same := BuildDirConfig{Board: "nrf52840dk", SourceDir: "/ws/apps/blinky", Shield: "nrf7002ek"}
+	if diffs := StaleConfig(*cached, same); len(diffs) != 0 {
+		t.Fatalf("expected a qualified board to match, got %+v", diffs)
+	}
+
+	other := BuildDirConfig{Board: "native_sim", SourceDir: "/ws/apps/other", ConfFile: "prj.conf;debug.conf"}
+	got := StaleConfig(*cached, other)
+	want := []ConfigDiff{
+		{"Board", "nrf52840dk/nrf52840", "native_sim"},
+		{"App source", "/ws/apps/blinky", "/ws/apps/other"},
+		{"Shield", "nrf7002ek", ""},
+		{"CONF_FILE", "/ws/apps/blinky/prj.conf", "prj.conf;debug.conf"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("StaleConfig =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestCMakeDefine(t *testing.T) {
+	if v, ok := CMakeDefine("-G Ninja -DCONF_FILE:STRING=a.conf -DFOO=1", "CONF_FILE"); !ok || v != "a.conf" {
+		t.Fatalf("CMakeDefine = %q, %v", v, ok)
+	}
+	if _, ok := CMakeDefine("-DCONF_FILE_EXTRA=1", "CONF_FILE"); ok {
+		t.Fatal("expected no match for a longer name")
+	}
+}
+
+func TestCMakeCacheSetArgs(t *testing.T) {
+	got := CMakeCacheSetArgs("build", "CONFIG_DEBUG", "y")
+	want := []string{"build", "-d", "build", "--cmake-only", "--", "-DCONFIG_DEBUG=y"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("CMakeCacheSetArgs = %v, want %v", got, want)
+	}
+}
@@ -2,6 +2,7 @@ package west
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os/exec"
 	"strings"
@@ -10,49 +11,49 @@ import (
 )
 
 // Status runs `west status` and returns the output.
-func Status() tea.Cmd {
-	return RunStreaming("west", "status")
+func Status(ctx context.Context) tea.Cmd {
+	return RunStreaming(ctx, "west", "status")
 }
 
 // List runs `west list` and returns the output.
-func List() tea.Cmd {
-	return RunStreaming("west", "list")
+func List(ctx context.Context) tea.Cmd {
+	return RunStreaming(ctx, "west", "list")
 }
 
 // Diff runs `west diff` and returns the output.
-func Diff() tea.Cmd {
-	return RunStreaming("west", "diff")
+func Diff(ctx context.Context) tea.Cmd {
+	return RunStreaming(ctx, "west", "diff")
 }
 
 // Forall runs `west forall -c <cmd>` and returns the output.
-func Forall(cmd string) tea.Cmd {
-	return RunStreaming("west", "forall", "-c", cmd)
+func Forall(ctx context.Context, cmd string) tea.Cmd {
+	return RunStreaming(ctx, "west", "forall", "-c", cmd)
 }
 
 // Update runs `west update` and streams output.
-func Update() tea.Cmd {
-	return RunStreaming("west", "update")
+func Update(ctx context.Context) tea.Cmd {
+	return RunStreaming(ctx, "west", "update")
 }
 
 // Init runs `west init -l .` to initialize the workspace using the local manifest.
-func Init() tea.Cmd {
-	return RunStreaming("west", "init", "-l", ".")
+func Init(ctx context.Context) tea.Cmd {
+	return RunStreaming(ctx, "west", "init", "-l", ".")
 }
 
 // ZephyrExport runs `west zephyr-export` to export CMake packages.
-func ZephyrExport() tea.Cmd {
-	return RunStreaming("west", "zephyr-export")
+func ZephyrExport(ctx context.Context) tea.Cmd {
+	return RunStreaming(ctx, "west", "zephyr-export")
 }
 
 // PackagesPipInstall runs `west packages pip --install` to install Python dependencies.
-func PackagesPipInstall() tea.Cmd {
-	return RunStreaming("west", "packages", "pip", "--install")
+func PackagesPipInstall(ctx context.Context) tea.Cmd {
+	return RunStreaming(ctx, "west", "packages", "pip", "--install")
 }
 
 // SdkInstall runs `west sdk install` to download and install the Zephyr SDK.
 // It first checks if wget is available, as the SDK setup script requires it.
 // Installs only the ARM toolchain by default (covers most embedded targets like nRF, STM32, etc.)
-func SdkInstall() tea.Cmd {
+func SdkInstall(ctx context.Context) tea.Cmd {
 	return func() tea.Msg {
 		// Pre-flight check: verify wget is installed
 		if _, err := exec.LookPath("wget"); err != nil {
@@ -71,7 +72,7 @@ Then re-run the setup wizard.
 
 		// Install SDK with ARM toolchain only (most common embedded targets)
 		// User can install additional toolchains later with: west sdk install -t <toolchain>
-		return RunStreaming("west", "sdk", "install", "-t", "arm-zephyr-eabi")()
+		return RunStreaming(ctx, "west", "sdk", "install", "-t", "arm-zephyr-eabi")()
 	}
 }
 
@@ -91,7 +92,7 @@ var requiredBrewPackages = []string{
 }
 
 // InstallBrewDeps checks for required Homebrew packages and installs any that are missing.
-func InstallBrewDeps() tea.Cmd {
+func InstallBrewDeps(ctx context.Context) tea.Cmd {
 	return func() tea.Msg {
 		var output bytes.Buffer
 
@@ -137,12 +138,20 @@ Then re-run the setup wizard.
 		output.WriteString(fmt.Sprintf("\nInstalling %d missing package(s): %s\n\n",
 			len(missing), strings.Join(missing, ", ")))
 
-		cmd := exec.Command("brew", append([]string{"install"}, missing...)...)
+		cmd := exec.CommandContext(ctx, "brew", append([]string{"install"}, missing...)...)
 		applyEnv(cmd)
+		setProcessGroup(cmd)
 		cmd.Stdout = &output
 		cmd.Stderr = &output
 
 		if err := cmd.Run(); err != nil {
+			if ctx.Err() != nil {
+				return CommandResultMsg{
+					Output:    output.String(),
+					ExitCode:  -1,
+					Cancelled: true,
+				}
+			}
 			if exitErr, ok := err.(*exec.ExitError); ok {
 				return CommandResultMsg{
 					Output:   output.String(),
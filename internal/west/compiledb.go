@@ -0,0 +1,173 @@
+package west
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// CompileCommandsName is the compilation database CMake writes into a
+	// build directory and clangd looks for in the source tree.
+	CompileCommandsName = "compile_commands.json"
+	// gustCompileCommandsName is the filtered or translated copy written
+	// next to it.
+	gustCompileCommandsName = "compile_commands.gust.json"
+)
+
+// SyncCompileCommands points projectDir/compile_commands.json at the
+// compilation database of the build in buildDir, or of its default image
+// for sysbuild. With filter set, the link points at a copy without the
+// translation units of the Zephyr tree itself. When the build ran in a
+// container with the workspace at mount, the link points at a copy with
+// mount replaced by wsRoot, so host tools find the files.
+//
+// It returns the database linked to, or "" when the build dir has none, in
+// which case a link left for another build is removed. A regular file at
+// the link's place is never replaced.
+func SyncCompileCommands(wsRoot, buildDir, projectDir string, filter bool, mount string) (string, error) {
+	dir := BuildDirPath(wsRoot, buildDir)
+	if d, err := ReadDomains(wsRoot, buildDir); err == nil && d.Default != "" {
+		dir = filepath.Join(dir, d.Default)
+	}
+	link := filepath.Join(projectDir, CompileCommandsName)
+	if fi, err := os.Lstat(link); err == nil && fi.Mode()&os.ModeSymlink == 0 {
+		return "", fmt.Errorf("%s is not a symlink; leaving it alone", link)
+	}
+
+	src := filepath.Join(dir, CompileCommandsName)
+	if _, err := os.Stat(src); err != nil {
+		if os.IsNotExist(err) {
+			return "", removeSymlink(link)
+		}
+		return "", err
+	}
+	if filter || mount != "" {
+		zephyrBase := ""
+		if filter {
+			if entries, err := ReadCMakeCache(filepath.Join(dir, "CMakeCache.txt")); err == nil {
+				zephyrBase = translatePath(cacheValue(entries, "ZEPHYR_BASE"), mount, wsRoot)
+			}
+			if zephyrBase == "" {
+				zephyrBase = filepath.Join(wsRoot, "zephyr")
+			}
+		}
+		copied := filepath.Join(dir, gustCompileCommandsName)
+		if err := copyCompileCommands(src, copied, mount, wsRoot, zephyrBase); err != nil {
+			return "", err
+		}
+		src = copied
+	}
+
+	abs, err := filepath.Abs(src)
+	if err != nil {
+		return "", err
+	}
+	if target, err := os.Readlink(link); err == nil && target == abs {
+		return abs, nil
+	}
+	return abs, replaceSymlink(abs, link)
+}
+
+// replaceSymlink points link at target. The new link is made under a
+// temporary name and renamed over link, so syncs racing each other never
+// see it missing or fail because it already exists.
+func replaceSymlink(target, link string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(link), ".gust-tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	if err := os.Remove(tmpPath); err != nil {
+		return err
+	}
+	if err := os.Symlink(target, tmpPath); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, link); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// removeSymlink removes path if it is a symlink.
+func removeSymlink(path string) error {
+	fi, err := os.Lstat(path)
+	if err != nil || fi.Mode()&os.ModeSymlink == 0 {
+		return nil
+	}
+	return os.Remove(path)
+}
+
+// copyCompileCommands copies the database at src to dst with the paths
+// under mount moved to wsRoot and, unless zephyrBase is "", without the
+// entries for files under zephyrBase. Other fields are kept as they are.
+func copyCompileCommands(src, dst, mount, wsRoot, zephyrBase string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	var entries []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("%s: %w", src, err)
+	}
+	base := filepath.Clean(zephyrBase) + string(filepath.Separator)
+	kept := make([]map[string]json.RawMessage, 0, len(entries))
+	for _, e := range entries {
+		if mount != "" {
+			translateCompileCommand(e, mount, wsRoot)
+		}
+		var file, directory string
+		_ = json.Unmarshal(e["file"], &file)
+		_ = json.Unmarshal(e["directory"], &directory)
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(directory, file)
+		}
+		if zephyrBase != "" && strings.HasPrefix(filepath.Clean(file), base) {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	out, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, out, 0o644)
+}
+
+// translateCompileCommand moves the paths in a database entry from the
+// container's mount to wsRoot, including those glued to an option such as
+// -I/workdir/zephyr/include.
+func translateCompileCommand(e map[string]json.RawMessage, mount, wsRoot string) {
+	arg := func(s string) string {
+		if i := strings.Index(s, mount); i > 0 && s[0] == '-' && !strings.Contains(s[:i], "/") {
+			return s[:i] + translatePath(s[i:], mount, wsRoot)
+		}
+		return translatePath(s, mount, wsRoot)
+	}
+	for _, key := range []string{"directory", "file", "output"} {
+		var v string
+		if json.Unmarshal(e[key], &v) == nil {
+			e[key], _ = json.Marshal(arg(v))
+		}
+	}
+	var command string
+	if json.Unmarshal(e["command"], &command) == nil {
+		args := strings.Split(command, " ")
+		for i := range args {
+			args[i] = arg(args[i])
+		}
+		e["command"], _ = json.Marshal(strings.Join(args, " "))
+	}
+	var arguments []string
+	if json.Unmarshal(e["arguments"], &arguments) == nil {
+		for i := range arguments {
+			arguments[i] = arg(arguments[i])
+		}
+		e["arguments"], _ = json.Marshal(arguments)
+	}
+}
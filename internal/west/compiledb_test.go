@@ -0,0 +1,205 @@
+package west
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func writeCompileDB(t *testing.T, dir string, files ...string) {
+	t.Helper()
+	var entries []map[string]string
+	for _, f := range files {
+		entries = append(entries, map[string]string{"directory": dir, "file": f, "command": "cc -c " + f})
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, CompileCommandsName), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyncCompileCommandsLinksAndFilters(t *testing.T) {
+	wsRoot := t.TempDir()
+	project := filepath.Join(wsRoot, "app")
+	if err := os.MkdirAll(project, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	buildDir := filepath.Join(wsRoot, "build")
+	writeCompileDB(t, buildDir,
+		filepath.Join(project, "src", "main.c"),
+		filepath.Join(wsRoot, "zephyr", "kernel", "sched.c"),
+	)
+	link := filepath.Join(project, CompileCommandsName)
+
+	target, err := SyncCompileCommands(wsRoot, "build", project, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.Readlink(link); got != target || target != filepath.Join(buildDir, CompileCommandsName) {
+		t.Fatalf("link -> %q, target %q", got, target)
+	}
+
+	target, err = SyncCompileCommands(wsRoot, "build", project, true, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []map[string]string
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0]["file"] != filepath.Join(project, "src", "main.c") || entries[0]["command"] == "" {
+		t.Fatalf("filtered database via %s = %v", target, entries)
+	}
+
+	// A build dir without a database removes the stale link.
+	if target, err := SyncCompileCommands(wsRoot, "build-other", project, false, ""); err != nil || target != "" {
+		t.Fatalf("SyncCompileCommands(missing) = %q, %v", target, err)
+	}
+	if _, err := os.Lstat(link); !os.IsNotExist(err) {
+		t.Fatalf("expected stale link removed, got %v", err)
+	}
+}
+
+func TestSyncCompileCommandsTranslatesContainerPaths(t *testing.T) {
+	wsRoot := t.TempDir()
+	buildDir := filepath.Join(wsRoot, "build")
+	if err := os.MkdirAll(buildDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	db := `[
+  {"directory": "/workdir/build", "file": "/workdir/app/src/main.c", "command": "cc -I/workdir/zephyr/include -c /workdir/app/src/main.c"},
+  {"directory": "/workdir/build", "file": "/workdir/zephyr/kernel/sched.c", "command": "cc -c /workdir/zephyr/kernel/sched.c"}
+]`
+	if err := os.WriteFile(filepath.Join(buildDir, CompileCommandsName), []byte(db), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(buildDir, "CMakeCache.txt"), []byte("ZEPHYR_BASE:PATH=/workdir/zephyr\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, filter := range []bool{false, true} {
+		if _, err := SyncCompileCommands(wsRoot, "build", wsRoot, filter, "/workdir"); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(filepath.Join(wsRoot, CompileCommandsName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var entries []map[string]string
+		if err := json.Unmarshal(data, &entries); err != nil {
+			t.Fatal(err)
+		}
+		want := 2
+		if filter {
+			want = 1
+		}
+		if len(entries) != want {
+			t.Fatalf("filter=%v: expected %d entries, got %v", filter, want, entries)
+		}
+		e := entries[0]
+		if e["directory"] != buildDir || e["file"] != filepath.Join(wsRoot, "app", "src", "main.c") ||
+			e["command"] != "cc -I"+filepath.Join(wsRoot, "zephyr", "include")+" -c "+filepath.Join(wsRoot, "app", "src", "main.c") {
+			t.Fatalf("filter=%v: expected host paths, got %v", filter, e)
+		}
+	}
+}
+
+func TestSyncCompileCommandsKeepsRegularFile(t *testing.T) {
+	wsRoot := t.TempDir()
+	writeCompileDB(t, filepath.Join(wsRoot, "build"), "main.c")
+	own := filepath.Join(wsRoot, CompileCommandsName)
+	if err := os.WriteFile(own, []byte("[]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SyncCompileCommands(wsRoot, "build", wsRoot, false, ""); err == nil {
+		t.Fatal("expected an error for an existing regular file")
+	}
+	if data, _ := os.ReadFile(own); string(data) != "[]" {
+		t.Fatalf("regular file was changed: %q", data)
+	}
+}
+
+func TestSyncCompileCommandsConcurrently(t *testing.T) {
+	wsRoot := t.TempDir()
+	writeCompileDB(t, filepath.Join(wsRoot, "build-a"), "a.c")
+	writeCompileDB(t, filepath.Join(wsRoot, "build-b"), "b.c")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		buildDir := "build-a"
+		if i%2 == 1 {
+			buildDir = "build-b"
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := SyncCompileCommands(wsRoot, buildDir, wsRoot, false, ""); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent sync: %v", err)
+	}
+
+	if _, err := os.ReadFile(filepath.Join(wsRoot, CompileCommandsName)); err != nil {
+		t.Fatalf("expected a working link, got %v", err)
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(wsRoot, ".gust-tmp-*")); len(tmp) != 0 {
+		t.Fatalf("temporary links left behind: %v", tmp)
+	}
+}
+
+func TestCompileCommandsLinkDoesNotMakeCheckoutDirty(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	project := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+		cmd.Dir = project
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	main := filepath.Join(project, "main.c")
+	if err := os.WriteFile(main, []byte("int main;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("init", "-q")
+	git("add", "main.c")
+	git("commit", "-q", "-m", "init")
+
+	wsRoot := t.TempDir()
+	writeCompileDB(t, filepath.Join(wsRoot, "build"), main)
+	if _, err := SyncCompileCommands(wsRoot, "build", project, false, ""); err != nil {
+		t.Fatal(err)
+	}
+	if g := ReadGitState(project); g.Dirty || g.Commit == "" {
+		t.Fatalf("expected a clean checkout with only the link added, got %+v", g)
+	}
+
+	if err := os.WriteFile(main, []byte("int main(void);"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if g := ReadGitState(project); !g.Dirty {
+		t.Fatal("expected a modified tracked file to make the checkout dirty")
+	}
+}
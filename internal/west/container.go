@@ -0,0 +1,199 @@
+package west
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DefaultContainerMount is where the workspace appears inside the container
+// when ContainerOptions.Mount is empty.
+const DefaultContainerMount = "/workdir"
+
+// ContainerOptions configures a ContainerRunner.
+type ContainerOptions struct {
+	// Engine is the engine invocation, e.g. "docker" or "podman --remote".
+	// Defaults to "docker".
+	Engine string
+	// Image is the toolchain image every command runs in.
+	Image string
+	// Mount is the workspace path inside the container.
+	Mount string
+	// Env lists variables to pass in: NAME forwards the host value,
+	// NAME=value sets it explicitly.
+	Env []string
+	// RunArgs are extra flags for `<engine> run`, e.g. "--user", "1000:1000"
+	// or "--privileged" for flashing.
+	RunArgs []string
+}
+
+// ContainerRunner runs every command inside a container with the workspace
+// bind-mounted. Host workspace paths in arguments are translated to the
+// mount point, and mount paths in output are translated back, so pages see
+// the same paths as with DefaultRunner.
+type ContainerRunner struct {
+	opts     ContainerOptions
+	hostRoot string
+	seq      atomic.Int64
+}
+
+// NewContainerRunner returns a runner that mounts hostRoot into containers
+// started from opts.Image.
+func NewContainerRunner(hostRoot string, opts ContainerOptions) *ContainerRunner {
+	if strings.TrimSpace(opts.Engine) == "" {
+		opts.Engine = "docker"
+	}
+	if opts.Mount == "" {
+		opts.Mount = DefaultContainerMount
+	}
+	return &ContainerRunner{opts: opts, hostRoot: hostRoot}
+}
+
+// invocation returns the engine binary and the full argument list that runs
+// name with args in a container called containerName.
+func (r *ContainerRunner) invocation(containerName, name string, args []string) (string, []string) {
+	engine := strings.Fields(r.opts.Engine)
+	full := append([]string(nil), engine[1:]...)
+	full = append(full, "run", "--rm", "--name", containerName,
+		"-v", r.hostRoot+":"+r.opts.Mount,
+		"-w", r.opts.Mount,
+	)
+	for _, e := range r.opts.Env {
+		full = append(full, "-e", e)
+	}
+	full = append(full, r.opts.RunArgs...)
+	full = append(full, r.opts.Image, name)
+	for _, a := range args {
+		full = append(full, translatePath(a, r.hostRoot, r.opts.Mount))
+	}
+	return engine[0], full
+}
+
+func (r *ContainerRunner) Run(ctx context.Context, name string, args ...string) tea.Cmd {
+	containerName := fmt.Sprintf("gust-%d-%d", os.Getpid(), r.seq.Add(1))
+	engine, full := r.invocation(containerName, name, args)
+	cmd := func() tea.Msg {
+		c := exec.CommandContext(ctx, engine, full...)
+		c.Dir = r.hostRoot
+		setProcessGroup(c)
+		// Killing the engine client leaves the container running; stop it
+		// by name as well.
+		kill := c.Cancel
+		c.Cancel = func() error {
+			stop := append(strings.Fields(r.opts.Engine)[1:], "kill", containerName)
+			_ = exec.Command(engine, stop...).Run()
+			if kill != nil {
+				return kill()
+			}
+			return c.Process.Kill()
+		}
+		return startStream(ctx, c).next()
+	}
+	return mapOutput(cmd, func(s string) string {
+		return translatePath(s, r.opts.Mount, r.hostRoot)
+	})
+}
+
+func (r *ContainerRunner) Status(ctx context.Context) tea.Cmd {
+	return r.Run(ctx, "west", "status")
+}
+
+func (r *ContainerRunner) List(ctx context.Context) tea.Cmd {
+	return r.Run(ctx, "west", "list")
+}
+
+func (r *ContainerRunner) Diff(ctx context.Context) tea.Cmd {
+	return r.Run(ctx, "west", "diff")
+}
+
+func (r *ContainerRunner) Update(ctx context.Context) tea.Cmd {
+	return r.Run(ctx, "west", "update")
+}
+
+func (r *ContainerRunner) Init(ctx context.Context) tea.Cmd {
+	return r.Run(ctx, "west", "init", "-l", ".")
+}
+
+// The remaining setup steps install host tooling or write outside the
+// workspace, which would be lost with the container. The image is expected
+// to provide them.
+
+func (r *ContainerRunner) ZephyrExport(ctx context.Context) tea.Cmd {
+	return r.providedByImage("Zephyr CMake package export")
+}
+
+func (r *ContainerRunner) PackagesPipInstall(ctx context.Context) tea.Cmd {
+	return r.providedByImage("Python dependencies")
+}
+
+func (r *ContainerRunner) SdkInstall(ctx context.Context) tea.Cmd {
+	return r.providedByImage("Zephyr SDK")
+}
+
+func (r *ContainerRunner) InstallBrewDeps(ctx context.Context) tea.Cmd {
+	return r.providedByImage("System dependencies")
+}
+
+func (r *ContainerRunner) providedByImage(what string) tea.Cmd {
+	return func() tea.Msg {
+		return CommandResultMsg{
+			Output: fmt.Sprintf("%s: provided by container image %s, skipping.\n", what, r.opts.Image),
+		}
+	}
+}
+
+// mapOutput applies f to every streamed line and to the final output of cmd.
+func mapOutput(cmd tea.Cmd, f func(string) string) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case CommandOutputMsg:
+			msg.Line = f(msg.Line)
+			msg.Next = mapOutput(msg.Next, f)
+			return msg
+		case CommandResultMsg:
+			msg.Output = f(msg.Output)
+			return msg
+		default:
+			return msg
+		}
+	}
+}
+
+// translatePath replaces the directory from with to wherever it appears in s
+// as a whole path, so /ws/app becomes /workdir/app but /ws2 and /data/ws are
+// untouched.
+func translatePath(s, from, to string) string {
+	if from == "" || from == to {
+		return s
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(s, from)
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		end := i + len(from)
+		startOK := i == 0 || !isPathChar(s[i-1])
+		endOK := end == len(s) || s[end] == '/' || !isPathChar(s[end])
+		if startOK && endOK {
+			b.WriteString(s[:i] + to)
+		} else {
+			b.WriteString(s[:end])
+		}
+		s = s[end:]
+	}
+}
+
+func isPathChar(c byte) bool {
+	return c == '/' || c == '.' || c == '_' || c == '-' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,122 @@
+//go:build !windows
+
+package west
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeStubEngine writes a shell script that stands in for docker. It runs
+// the given script for `run` and records the container name in killed.txt
+// for `kill`.
+func writeStubEngine(t *testing.T, run string) (engine, dir string) {
+	t.Helper()
+	dir = t.TempDir()
+	engine = filepath.Join(dir, "engine")
+	script := `#!/bin/sh
+if [ "$1" = kill ]; then
+	echo "$2" > "` + dir + `/killed.txt"
+	exit 0
+fi
+` + run + "\n"
+	if err := os.WriteFile(engine, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return engine, dir
+}
+
+func TestContainerRunnerInvocationAndPathTranslation(t *testing.T) {
+	engine, dir := writeStubEngine(t, `printf '%s\n' "$@" > "$(dirname "$0")/args.txt"
+echo "/workdir/apps/blinky/src/main.c:3: error: boom"
+exit 3`)
+	hostRoot := t.TempDir()
+	r := NewContainerRunner(hostRoot, ContainerOptions{
+		Engine:  engine,
+		Image:   "zephyr-ci:v1",
+		Env:     []string{"CCACHE_DIR", "ZEPHYR_TOOLCHAIN_VARIANT=zephyr"},
+		RunArgs: []string{"--user", "1000:1000"},
+	})
+
+	var lines []string
+	msg := Drain(r.Run(context.Background(), "west", "build", "-b", "native_sim", filepath.Join(hostRoot, "apps", "blinky")), func(line string) {
+		lines = append(lines, line)
+	})
+	result := msg.(CommandResultMsg)
+	if result.ExitCode != 3 {
+		t.Fatalf("expected engine exit code 3, got %d", result.ExitCode)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "args.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := strings.Fields(string(data))
+	got := strings.Join(args, " ")
+	want := "run --rm --name " + args[3] + " -v " + hostRoot + ":/workdir -w /workdir" +
+		" -e CCACHE_DIR -e ZEPHYR_TOOLCHAIN_VARIANT=zephyr --user 1000:1000" +
+		" zephyr-ci:v1 west build -b native_sim /workdir/apps/blinky"
+	if got != want {
+		t.Fatalf("unexpected invocation\n got: %s\nwant: %s", got, want)
+	}
+
+	wantLine := filepath.Join(hostRoot, "apps", "blinky", "src", "main.c") + ":3: error: boom"
+	if len(lines) != 1 || lines[0] != wantLine {
+		t.Fatalf("expected output path translated to host, got %q", lines)
+	}
+	if !strings.Contains(result.Output, wantLine) {
+		t.Fatalf("expected final output translated to host, got %q", result.Output)
+	}
+}
+
+func TestContainerRunnerCancelKillsContainer(t *testing.T) {
+	engine, dir := writeStubEngine(t, `sleep 10`)
+	r := NewContainerRunner(t.TempDir(), ContainerOptions{Engine: engine, Image: "img"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan CommandResultMsg, 1)
+	go func() { done <- Drain(r.Run(ctx, "west", "build"), nil).(CommandResultMsg) }()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	select {
+	case result := <-done:
+		if !result.Cancelled {
+			t.Fatalf("expected cancelled result, got %+v", result)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("engine was not stopped after cancel")
+	}
+	killed, err := os.ReadFile(filepath.Join(dir, "killed.txt"))
+	if err != nil || !strings.HasPrefix(string(killed), "gust-") {
+		t.Fatalf("expected engine kill for the container, got %q (%v)", killed, err)
+	}
+}
+
+func TestContainerRunnerSkipsHostSetupSteps(t *testing.T) {
+	r := NewContainerRunner(t.TempDir(), ContainerOptions{Engine: "/nonexistent/engine", Image: "img"})
+	result := r.SdkInstall(context.Background())().(CommandResultMsg)
+	if result.ExitCode != 0 || !strings.Contains(result.Output, "provided by container image img") {
+		t.Fatalf("expected SDK install to be skipped, got %+v", result)
+	}
+}
+
+func TestTranslatePathMatchesWholePaths(t *testing.T) {
+	cases := map[string]string{
+		"/ws":                   "/workdir",
+		"/ws/app":               "/workdir/app",
+		"-DCONF=/ws/a.conf":     "-DCONF=/workdir/a.conf",
+		"/ws2/app":              "/ws2/app",
+		"/data/ws/app":          "/data/ws/app",
+		"'/ws/a' and \"/ws/b\"": "'/workdir/a' and \"/workdir/b\"",
+	}
+	for in, want := range cases {
+		if got := translatePath(in, "/ws", "/workdir"); got != want {
+			t.Errorf("translatePath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package west
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Severity of a build diagnostic.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic origins.
+const (
+	SourceCompiler   = "compiler"
+	SourceCMake      = "cmake"
+	SourceDevicetree = "devicetree"
+	SourceKconfig    = "kconfig"
+	SourceLinker     = "linker"
+)
+
+// Diagnostic is one error or warning found in build output. File, Line and
+// Column are empty or zero when the tool did not report them.
+type Diagnostic struct {
+	Severity Severity
+	Source   string
+	File     string
+	Line     int
+	Column   int
+	Message  string
+}
+
+var (
+	// /path/main.c:12:5: error: 'x' undeclared
+	// prj.conf:3: warning: attempt to assign ... (Kconfig uses the same shape)
+	compilerDiagRe = regexp.MustCompile(`^(.+?):(\d+):(?:(\d+):)?\s+(fatal error|error|warning):\s+(.*)$`)
+	// main.c:(.text.main+0x8): undefined reference to `foo'
+	// /path/main.c:20: undefined reference to `foo'
+	linkerDiagRe = regexp.MustCompile(`^(.+?):(?:(\d+)|\([^)]*\)):\s+(undefined reference to .*|multiple definition of .*)$`)
+	// CMake Error at CMakeLists.txt:12 (find_package):
+	// CMake Warning (dev) at cmake/foo.cmake:3 (message):
+	cmakeDiagRe = regexp.MustCompile(`^CMake (Error|Warning|Deprecation Warning)(?: \(dev\))?(?: at (.+?):(\d+)(?: \([^)]*\))?)?:\s*(.*)$`)
+	// Error: app.overlay:12.3-4 syntax error
+	// Warning (unit_address_vs_reg): /path/zephyr.dts:123.20-130.5: node has a reg ...
+	dtcDiagRe = regexp.MustCompile(`^(Error|Warning)(?: \(([^)]+)\))?: (.+?):(\d+)\.(\d+)(?:-[\d.]+)?:?\s+(.*)$`)
+	// devicetree error: /path/board.dts:12 (column 4): parse error: ...
+	edtDiagRe = regexp.MustCompile(`^devicetree (error|warning): (?:(.+?):(\d+) \(column (\d+)\): )?(.*)$`)
+	// warning: FOO (defined at drivers/Kconfig:10) was assigned the value ...
+	// error: Aborting due to Kconfig warnings
+	kconfigDiagRe  = regexp.MustCompile(`^(warning|error): (.*)$`)
+	kconfigDefAtRe = regexp.MustCompile(`\(defined at ([^:)]+):(\d+)`)
+)
+
+// DiagnosticParser extracts diagnostics from build output fed to it one line
+// at a time, so counts can be shown while a build is still running. The zero
+// value is ready to use.
+type DiagnosticParser struct {
+	diags []Diagnostic
+	seen  map[Diagnostic]bool
+	// cmake collects the indented message lines that follow a CMake
+	// Error/Warning header.
+	cmake *Diagnostic
+}
+
+// Feed parses one line of output.
+func (p *DiagnosticParser) Feed(line string) {
+	line = strings.TrimRight(line, "\r")
+
+	if p.cmake != nil {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(line, " ") && trimmed != "" {
+			if p.cmake.Message != "" {
+				p.cmake.Message += " "
+			}
+			p.cmake.Message += trimmed
+			return
+		}
+		if trimmed == "" && p.cmake.Message == "" {
+			// CMake puts a blank line between the header and the message.
+			return
+		}
+		p.add(*p.cmake)
+		p.cmake = nil
+	}
+
+	if d, ok := parseDiagnosticLine(line); ok {
+		if strings.HasPrefix(line, "CMake ") {
+			p.cmake = &d
+			return
+		}
+		p.add(d)
+	}
+}
+
+// Diagnostics returns everything found so far, in output order.
+func (p *DiagnosticParser) Diagnostics() []Diagnostic {
+	if p.cmake != nil {
+		p.add(*p.cmake)
+		p.cmake = nil
+	}
+	return p.diags
+}
+
+// Counts returns the number of errors and warnings found so far.
+func (p *DiagnosticParser) Counts() (errors, warnings int) {
+	diags := p.diags
+	if p.cmake != nil {
+		diags = append(diags[:len(diags):len(diags)], *p.cmake)
+	}
+	for _, d := range diags {
+		switch d.Severity {
+		case SeverityError:
+			errors++
+		case SeverityWarning:
+			warnings++
+		}
+	}
+	return errors, warnings
+}
+
+// Reset discards all diagnostics.
+func (p *DiagnosticParser) Reset() {
+	*p = DiagnosticParser{}
+}
+
+func (p *DiagnosticParser) add(d Diagnostic) {
+	if p.seen == nil {
+		p.seen = make(map[Diagnostic]bool)
+	}
+	// The same header warning is reported once per translation unit.
+	if p.seen[d] {
+		return
+	}
+	p.seen[d] = true
+	p.diags = append(p.diags, d)
+}
+
+// ParseDiagnostics extracts all diagnostics from complete build output.
+func ParseDiagnostics(output string) []Diagnostic {
+	var p DiagnosticParser
+	for _, line := range strings.Split(output, "\n") {
+		p.Feed(line)
+	}
+	return p.Diagnostics()
+}
+
+func parseDiagnosticLine(line string) (Diagnostic, bool) {
+	if m := cmakeDiagRe.FindStringSubmatch(line); m != nil {
+		d := Diagnostic{Severity: SeverityWarning, Source: SourceCMake, File: m[2], Line: atoi(m[3]), Message: m[4]}
+		if m[1] == "Error" {
+			d.Severity = SeverityError
+		}
+		return d, true
+	}
+
+	if m := edtDiagRe.FindStringSubmatch(line); m != nil {
+		return Diagnostic{
+			Severity: Severity(m[1]),
+			Source:   SourceDevicetree,
+			File:     m[2],
+			Line:     atoi(m[3]),
+			Column:   atoi(m[4]),
+			Message:  m[5],
+		}, true
+	}
+
+	if m := dtcDiagRe.FindStringSubmatch(line); m != nil {
+		d := Diagnostic{
+			Severity: SeverityWarning,
+			Source:   SourceDevicetree,
+			File:     m[3],
+			Line:     atoi(m[4]),
+			Column:   atoi(m[5]),
+			Message:  m[6],
+		}
+		if m[1] == "Error" {
+			d.Severity = SeverityError
+		}
+		if m[2] != "" {
+			d.Message += " [" + m[2] + "]"
+		}
+		return d, true
+	}
+
+	if m := compilerDiagRe.FindStringSubmatch(line); m != nil {
+		d := Diagnostic{
+			Severity: SeverityWarning,
+			Source:   sourceForFile(m[1]),
+			File:     m[1],
+			Line:     atoi(m[2]),
+			Column:   atoi(m[3]),
+			Message:  m[5],
+		}
+		if m[4] != "warning" {
+			d.Severity = SeverityError
+		}
+		return d, true
+	}
+
+	if m := linkerDiagRe.FindStringSubmatch(line); m != nil {
+		d := Diagnostic{Severity: SeverityError, Source: SourceLinker, Message: m[3]}
+		if m[2] != "" {
+			d.File, d.Line = m[1], atoi(m[2])
+		}
+		return d, true
+	}
+
+	if m := kconfigDiagRe.FindStringSubmatch(line); m != nil {
+		d := Diagnostic{Severity: Severity(m[1]), Source: SourceKconfig, Message: m[2]}
+		if at := kconfigDefAtRe.FindStringSubmatch(m[2]); at != nil {
+			d.File, d.Line = at[1], atoi(at[2])
+		}
+		return d, true
+	}
+
+	return Diagnostic{}, false
+}
+
+// sourceForFile classifies a compiler-style diagnostic by the file it names.
+func sourceForFile(file string) string {
+	base := file
+	if i := strings.LastIndexAny(base, `/\`); i >= 0 {
+		base = base[i+1:]
+	}
+	switch {
+	case strings.HasSuffix(base, ".conf") || strings.HasPrefix(base, "Kconfig"):
+		return SourceKconfig
+	case strings.HasSuffix(base, ".dts") || strings.HasSuffix(base, ".dtsi") || strings.HasSuffix(base, ".overlay"):
+		return SourceDevicetree
+	case base == "CMakeLists.txt" || strings.HasSuffix(base, ".cmake"):
+		return SourceCMake
+	}
+	return SourceCompiler
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,74 @@
+package west
+
+import (
+	"testing"
+)
+
+func TestParseDiagnosticsRecognisesEachTool(t *testing.T) {
+	output := `[12/130] Building C object CMakeFiles/app.dir/src/main.c.obj
+FAILED: CMakeFiles/app.dir/src/main.c.obj
+/ws/apps/blinky/src/main.c: In function 'main':
+/ws/apps/blinky/src/main.c:42:9: error: 'led' undeclared (first use in this function)
+/ws/apps/blinky/src/main.c:42:9: note: each undeclared identifier is reported only once
+/ws/apps/blinky/src/util.h:7:13: warning: 'helper' defined but not used [-Wunused-function]
+/ws/apps/blinky/src/util.h:7:13: warning: 'helper' defined but not used [-Wunused-function]
+/ws/apps/blinky/src/main.c:50: undefined reference to ` + "`missing_fn'" + `
+CMake Error at /ws/zephyr/cmake/modules/boards.cmake:186 (message):
+
+  Invalid BOARD; see above.
+  Board not found.
+
+-- Configuring incomplete, errors occurred!
+Error: /ws/apps/blinky/boards/nrf52840dk.overlay:12.3-4 syntax error
+Warning (unit_address_vs_reg): /ws/build/zephyr/zephyr.dts:123.20-130.5: node has a reg or ranges property, but no unit name
+devicetree error: /ws/apps/blinky/app.overlay:8 (column 2): parse error: expected '/' or label
+/ws/apps/blinky/prj.conf:3: warning: attempt to assign the value 'y' to the undefined symbol FOO
+warning: BT (defined at subsys/bluetooth/Kconfig:9) was assigned the value 'y' but got the value 'n'.
+error: Aborting due to Kconfig warnings
+`
+	got := ParseDiagnostics(output)
+	want := []Diagnostic{
+		{SeverityError, SourceCompiler, "/ws/apps/blinky/src/main.c", 42, 9, "'led' undeclared (first use in this function)"},
+		{SeverityWarning, SourceCompiler, "/ws/apps/blinky/src/util.h", 7, 13, "'helper' defined but not used [-Wunused-function]"},
+		{SeverityError, SourceLinker, "/ws/apps/blinky/src/main.c", 50, 0, "undefined reference to `missing_fn'"},
+		{SeverityError, SourceCMake, "/ws/zephyr/cmake/modules/boards.cmake", 186, 0, "Invalid BOARD; see above. Board not found."},
+		{SeverityError, SourceDevicetree, "/ws/apps/blinky/boards/nrf52840dk.overlay", 12, 3, "syntax error"},
+		{SeverityWarning, SourceDevicetree, "/ws/build/zephyr/zephyr.dts", 123, 20, "node has a reg or ranges property, but no unit name [unit_address_vs_reg]"},
+		{SeverityError, SourceDevicetree, "/ws/apps/blinky/app.overlay", 8, 2, "parse error: expected '/' or label"},
+		{SeverityWarning, SourceKconfig, "/ws/apps/blinky/prj.conf", 3, 0, "attempt to assign the value 'y' to the undefined symbol FOO"},
+		{SeverityWarning, SourceKconfig, "subsys/bluetooth/Kconfig", 9, 0, "BT (defined at subsys/bluetooth/Kconfig:9) was assigned the value 'y' but got the value 'n'."},
+		{SeverityError, SourceKconfig, "", 0, 0, "Aborting due to Kconfig warnings"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d diagnostics, got %d: %+v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("diagnostic %d:\n got: %+v\nwant: %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDiagnosticParserCountsWhileStreaming(t *testing.T) {
+	var p DiagnosticParser
+	p.Feed("CMake Warning at CMakeLists.txt:5 (message):")
+	if errs, warns := p.Counts(); errs != 0 || warns != 1 {
+		t.Fatalf("expected pending CMake warning to count, got %d errors %d warnings", errs, warns)
+	}
+	p.Feed("  deprecated option")
+	p.Feed("")
+	p.Feed("/ws/a.c:1:1: error: boom")
+
+	diags := p.Diagnostics()
+	if len(diags) != 2 || diags[0].Message != "deprecated option" {
+		t.Fatalf("expected CMake message to survive counting, got %+v", diags)
+	}
+	if errs, warns := p.Counts(); errs != 1 || warns != 1 {
+		t.Fatalf("expected 1 error 1 warning, got %d/%d", errs, warns)
+	}
+
+	p.Reset()
+	if len(p.Diagnostics()) != 0 {
+		t.Fatal("expected Reset to clear diagnostics")
+	}
+}
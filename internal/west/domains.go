@@ -0,0 +1,187 @@
+package west
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/buckleypaul/gust/internal/store"
+)
+
+// Domains describes a sysbuild build directory, as recorded by sysbuild in
+// domains.yaml.
+type Domains struct {
+	Default    string
+	Domains    []string
+	FlashOrder []string
+}
+
+// ReadDomains parses domains.yaml in buildDir, resolved against wsRoot. It
+// returns an error wrapping os.ErrNotExist for builds made without sysbuild.
+func ReadDomains(wsRoot, buildDir string) (*Domains, error) {
+	f, err := os.Open(filepath.Join(BuildDirPath(wsRoot, buildDir), "domains.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// domains.yaml is generated with a fixed shape:
+	//
+	//	default: app
+	//	build_dir: /ws/build
+	//	domains:
+	//	- name: app
+	//	  build_dir: /ws/build/app
+	//	flash_order:
+	//	- mcuboot
+	//	- app
+	//
+	// so a line scanner is enough.
+	d := &Domains{}
+	var section string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "-") {
+			key, value, _ := strings.Cut(trimmed, ":")
+			section = key
+			if key == "default" {
+				d.Default = yamlScalar(value)
+			}
+			continue
+		}
+		item, isItem := strings.CutPrefix(trimmed, "- ")
+		switch section {
+		case "domains":
+			if key, value, ok := strings.Cut(item, ":"); ok && key == "name" {
+				d.Domains = append(d.Domains, yamlScalar(value))
+			}
+		case "flash_order":
+			if isItem {
+				d.FlashOrder = append(d.FlashOrder, yamlScalar(item))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if d.Default == "" && len(d.Domains) > 0 {
+		d.Default = d.Domains[0]
+	}
+	return d, nil
+}
+
+func yamlScalar(s string) string {
+	return strings.Trim(strings.TrimSpace(s), `"'`)
+}
+
+// imageNames are the firmware files a Zephyr image directory may contain,
+// in the order they are listed.
+var imageNames = []string{
+	"zephyr.signed.hex",
+	"zephyr.signed.bin",
+	"zephyr.hex",
+	"zephyr.bin",
+	"zephyr.uf2",
+	"zephyr.elf",
+	"zephyr.exe",
+}
+
+// ImageArtifacts lists the firmware files present in buildDir/zephyr,
+// relative to buildDir.
+func ImageArtifacts(wsRoot, buildDir string) []string {
+	dir := BuildDirPath(wsRoot, buildDir)
+	var out []string
+	for _, name := range imageNames {
+		rel := filepath.Join("zephyr", name)
+		if _, err := os.Stat(filepath.Join(dir, rel)); err == nil {
+			out = append(out, rel)
+		}
+	}
+	return out
+}
+
+// DomainBuildDir returns the build directory of a sysbuild domain. Domains
+// always build into a subdirectory named after themselves.
+func DomainBuildDir(buildDir, domain string) string {
+	if buildDir == "" {
+		buildDir = "build"
+	}
+	return filepath.Join(buildDir, domain)
+}
+
+// ImageOutputs is what a successful build left in one image directory.
+type ImageOutputs struct {
+	Domain     string // sysbuild domain, empty for a single-image build
+	BuildDir   string
+	Artifacts  []string
+	BinarySize int64
+	Sizes      *SectionSizes
+}
+
+// ReadBuildOutputs collects the images of a finished build. A sysbuild
+// directory yields one entry per domain, default domain first; anything
+// else yields a single entry for buildDir itself.
+func ReadBuildOutputs(wsRoot, buildDir string) []ImageOutputs {
+	domains, err := ReadDomains(wsRoot, buildDir)
+	if err != nil {
+		return []ImageOutputs{readImageOutputs(wsRoot, buildDir, "")}
+	}
+	names := []string{domains.Default}
+	for _, name := range domains.Domains {
+		if name != domains.Default {
+			names = append(names, name)
+		}
+	}
+	out := make([]ImageOutputs, 0, len(names))
+	for _, name := range names {
+		out = append(out, readImageOutputs(wsRoot, DomainBuildDir(buildDir, name), name))
+	}
+	return out
+}
+
+// RecordBuildOutputs fills in the artifacts and sizes of the successful
+// build r describes. For sysbuild the top-level sizes describe the default
+// domain, and Artifacts lists every domain's images relative to the build
+// directory.
+func RecordBuildOutputs(r *store.BuildRecord, wsRoot string) {
+	images := ReadBuildOutputs(wsRoot, r.BuildDir)
+	main := images[0]
+	r.BinarySize = main.BinarySize
+	r.Sizes = (*store.SectionSizes)(main.Sizes)
+	if main.Domain == "" {
+		r.Artifacts = main.Artifacts
+		return
+	}
+	r.Artifacts = nil
+	for _, img := range images {
+		for _, a := range img.Artifacts {
+			r.Artifacts = append(r.Artifacts, filepath.Join(img.Domain, a))
+		}
+		r.Domains = append(r.Domains, store.DomainRecord{
+			Name:       img.Domain,
+			BuildDir:   img.BuildDir,
+			Artifacts:  img.Artifacts,
+			BinarySize: img.BinarySize,
+			Sizes:      (*store.SectionSizes)(img.Sizes),
+		})
+	}
+}
+
+func readImageOutputs(wsRoot, dir, domain string) ImageOutputs {
+	o := ImageOutputs{
+		Domain:     domain,
+		BuildDir:   dir,
+		Artifacts:  ImageArtifacts(wsRoot, dir),
+		BinarySize: BinarySize(wsRoot, dir),
+	}
+	if sz, err := BuildSectionSizes(wsRoot, dir); err == nil {
+		o.Sizes = &sz
+	}
+	return o
+}
@@ -0,0 +1,62 @@
+package west
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadDomains(t *testing.T) {
+	wsRoot := t.TempDir()
+	dir := filepath.Join(wsRoot, "build")
+	if err := os.MkdirAll(filepath.Join(dir, "app", "zephyr"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	yaml := `default: app
+build_dir: /ws/build
+domains:
+- name: app
+  build_dir: /ws/build/app
+- name: mcuboot
+  build_dir: /ws/build/mcuboot
+flash_order:
+- mcuboot
+- app
+`
+	if err := os.WriteFile(filepath.Join(dir, "domains.yaml"), []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := ReadDomains(wsRoot, "")
+	if err != nil {
+		t.Fatalf("ReadDomains: %v", err)
+	}
+	want := &Domains{Default: "app", Domains: []string{"app", "mcuboot"}, FlashOrder: []string{"mcuboot", "app"}}
+	if !reflect.DeepEqual(d, want) {
+		t.Fatalf("ReadDomains = %+v, want %+v", d, want)
+	}
+
+	if _, err := ReadDomains(wsRoot, "other"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected not-exist error without sysbuild, got %v", err)
+	}
+}
+
+func TestImageArtifacts(t *testing.T) {
+	wsRoot := t.TempDir()
+	dir := filepath.Join(wsRoot, "build", "app", "zephyr")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"zephyr.elf", "zephyr.signed.hex", "zephyr.map"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := ImageArtifacts(wsRoot, DomainBuildDir("", "app"))
+	want := []string{filepath.Join("zephyr", "zephyr.signed.hex"), filepath.Join("zephyr", "zephyr.elf")}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ImageArtifacts = %v, want %v", got, want)
+	}
+}
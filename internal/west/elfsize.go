@@ -0,0 +1,59 @@
+package west
+
+import (
+	"debug/elf"
+	"path/filepath"
+)
+
+// SectionSizes summarises the allocated sections of a firmware image.
+// Initialised data occupies flash (its load image) and RAM (at run time).
+type SectionSizes struct {
+	Text   int64
+	ROData int64
+	Data   int64
+	BSS    int64
+	Flash  int64
+	RAM    int64
+}
+
+// ELFSectionSizes reads the section headers of the ELF file at path.
+func ELFSectionSizes(path string) (SectionSizes, error) {
+	f, err := elf.Open(path)
+	if err != nil {
+		return SectionSizes{}, err
+	}
+	defer f.Close()
+	return sumSections(f.Sections), nil
+}
+
+// BuildSectionSizes reads zephyr/zephyr.elf in buildDir, resolved against
+// wsRoot.
+func BuildSectionSizes(wsRoot, buildDir string) (SectionSizes, error) {
+	return ELFSectionSizes(filepath.Join(BuildDirPath(wsRoot, buildDir), "zephyr", "zephyr.elf"))
+}
+
+// sumSections classifies allocated sections the way GNU size does in its
+// Berkeley format: executable sections are text, other read-only sections
+// rodata, writable PROGBITS data, and NOBITS (bss, noinit) bss.
+func sumSections(sections []*elf.Section) SectionSizes {
+	var s SectionSizes
+	for _, sec := range sections {
+		if sec.Flags&elf.SHF_ALLOC == 0 || sec.Size == 0 {
+			continue
+		}
+		size := int64(sec.Size)
+		switch {
+		case sec.Type == elf.SHT_NOBITS:
+			s.BSS += size
+		case sec.Flags&elf.SHF_EXECINSTR != 0:
+			s.Text += size
+		case sec.Flags&elf.SHF_WRITE != 0:
+			s.Data += size
+		default:
+			s.ROData += size
+		}
+	}
+	s.Flash = s.Text + s.ROData + s.Data
+	s.RAM = s.Data + s.BSS
+	return s
+}
@@ -0,0 +1,59 @@
+package west
+
+import (
+	"debug/elf"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSumSectionsClassifiesAllocatedSections(t *testing.T) {
+	sec := func(name string, typ elf.SectionType, flags elf.SectionFlag, size uint64) *elf.Section {
+		return &elf.Section{SectionHeader: elf.SectionHeader{Name: name, Type: typ, Flags: flags, Size: size}}
+	}
+	got := sumSections([]*elf.Section{
+		sec("rom_start", elf.SHT_PROGBITS, elf.SHF_ALLOC|elf.SHF_EXECINSTR, 256),
+		sec("text", elf.SHT_PROGBITS, elf.SHF_ALLOC|elf.SHF_EXECINSTR, 10000),
+		sec("rodata", elf.SHT_PROGBITS, elf.SHF_ALLOC, 2000),
+		sec("datas", elf.SHT_PROGBITS, elf.SHF_ALLOC|elf.SHF_WRITE, 300),
+		sec("bss", elf.SHT_NOBITS, elf.SHF_ALLOC|elf.SHF_WRITE, 4000),
+		sec("noinit", elf.SHT_NOBITS, elf.SHF_ALLOC|elf.SHF_WRITE, 1000),
+		sec(".debug_info", elf.SHT_PROGBITS, 0, 99999),
+	})
+	want := SectionSizes{Text: 10256, ROData: 2000, Data: 300, BSS: 5000, Flash: 12556, RAM: 5300}
+	if got != want {
+		t.Fatalf("sumSections = %+v, want %+v", got, want)
+	}
+}
+
+func TestBuildSectionSizesReadsELF(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	if f, err := elf.Open(exe); err != nil {
+		t.Skip("test binary is not ELF")
+	} else {
+		f.Close()
+	}
+	wsRoot := t.TempDir()
+	dir := filepath.Join(wsRoot, "build", "zephyr")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(exe, filepath.Join(dir, "zephyr.elf")); err != nil {
+		t.Skip(err)
+	}
+
+	s, err := BuildSectionSizes(wsRoot, "")
+	if err != nil {
+		t.Fatalf("BuildSectionSizes: %v", err)
+	}
+	if s.Text == 0 || s.Flash < s.Text {
+		t.Fatalf("expected text and flash totals, got %+v", s)
+	}
+
+	if _, err := BuildSectionSizes(wsRoot, "missing"); err == nil {
+		t.Fatal("expected error for a missing ELF")
+	}
+}
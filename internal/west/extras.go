@@ -0,0 +1,150 @@
+package west
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// BuildExtras lists the optional build inputs found for a project: Kconfig
+// fragments and devicetree overlays (relative to the project, as Zephyr
+// resolves EXTRA_CONF_FILE and EXTRA_DTC_OVERLAY_FILE) and snippet names.
+type BuildExtras struct {
+	Conf     []string
+	Overlays []string
+	Snippets []string
+}
+
+// BuildExtrasLoadedMsg is sent when a project's build extras have been
+// discovered.
+type BuildExtrasLoadedMsg struct {
+	Project string // absolute project directory the extras belong to
+	Extras  BuildExtras
+}
+
+// implicitInputs are picked up by Zephyr without being named, so offering
+// them as extras would only include them twice.
+var implicitInputs = map[string]bool{
+	"prj.conf":      true,
+	"sysbuild.conf": true,
+	"app.overlay":   true,
+}
+
+// LoadBuildExtras discovers the build extras of the project at projectDir.
+func LoadBuildExtras(wsRoot, projectDir string) tea.Cmd {
+	return func() tea.Msg {
+		return BuildExtrasLoadedMsg{Project: projectDir, Extras: DiscoverBuildExtras(wsRoot, projectDir)}
+	}
+}
+
+// DiscoverBuildExtras scans projectDir for *.conf and *.overlay files and
+// collects the snippets defined in the project, in Zephyr, and in any module
+// that declares a snippet_root.
+func DiscoverBuildExtras(wsRoot, projectDir string) BuildExtras {
+	var extras BuildExtras
+	if projectDir == "" {
+		return extras
+	}
+	filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			// Snippet directories carry their own fragments, applied with -S.
+			if path != projectDir && (skipDirs[d.Name()] || d.Name() == "snippets" || strings.HasPrefix(d.Name(), "build")) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(projectDir, path)
+		if err != nil || implicitInputs[rel] {
+			return nil
+		}
+		switch filepath.Ext(path) {
+		case ".conf":
+			extras.Conf = append(extras.Conf, filepath.ToSlash(rel))
+		case ".overlay":
+			extras.Overlays = append(extras.Overlays, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	sort.Strings(extras.Conf)
+	sort.Strings(extras.Overlays)
+
+	seen := map[string]bool{}
+	for _, root := range snippetRoots(wsRoot, projectDir) {
+		for _, name := range snippetsIn(root) {
+			if !seen[name] {
+				seen[name] = true
+				extras.Snippets = append(extras.Snippets, name)
+			}
+		}
+	}
+	sort.Strings(extras.Snippets)
+	return extras
+}
+
+// snippetRoots returns the directories Zephyr searches for snippets/: the
+// application, ZEPHYR_BASE, and modules whose zephyr/module.yml sets
+// build.settings.snippet_root.
+func snippetRoots(wsRoot, projectDir string) []string {
+	roots := []string{projectDir}
+	if wsRoot == "" {
+		return roots
+	}
+	roots = append(roots, filepath.Join(wsRoot, "zephyr"))
+	for _, pattern := range []string{"*/zephyr/module.yml", "*/*/zephyr/module.yml", "*/*/*/zephyr/module.yml"} {
+		matches, _ := filepath.Glob(filepath.Join(wsRoot, pattern))
+		for _, m := range matches {
+			if root := moduleSnippetRoot(m); root != "" {
+				roots = append(roots, root)
+			}
+		}
+	}
+	return roots
+}
+
+// moduleSnippetRoot reads snippet_root from a module.yml, resolved against
+// the module directory. It returns "" when the module declares none.
+func moduleSnippetRoot(moduleYml string) string {
+	f, err := os.Open(moduleYml)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "snippet_root:"); ok {
+			moduleDir := filepath.Dir(filepath.Dir(moduleYml))
+			return filepath.Join(moduleDir, yamlScalar(value))
+		}
+	}
+	return ""
+}
+
+// snippetsIn returns the names of the snippets under root/snippets. The name
+// comes from snippet.yml and falls back to the directory name.
+func snippetsIn(root string) []string {
+	files, _ := filepath.Glob(filepath.Join(root, "snippets", "*", "snippet.yml"))
+	var names []string
+	for _, file := range files {
+		name := filepath.Base(filepath.Dir(file))
+		if f, err := os.Open(file); err == nil {
+			scanner := bufio.NewScanner(f)
+			for scanner.Scan() {
+				if value, ok := strings.CutPrefix(scanner.Text(), "name:"); ok {
+					name = yamlScalar(value)
+					break
+				}
+			}
+			f.Close()
+		}
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,46 @@
+package west
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDiscoverBuildExtras(t *testing.T) {
+	wsRoot := t.TempDir()
+	files := map[string]string{
+		"apps/demo/prj.conf":                            "",
+		"apps/demo/app.overlay":                         "",
+		"apps/demo/debug.conf":                          "",
+		"apps/demo/boards/nrf52840dk.overlay":           "",
+		"apps/demo/build/zephyr/.config":                "",
+		"apps/demo/build/zephyr/stale.conf":             "",
+		"apps/demo/snippets/local/snippet.yml":          "name: local-usb\n",
+		"apps/demo/snippets/local/local.conf":           "",
+		"zephyr/snippets/rtt-console/snippet.yml":       "name: rtt-console\n",
+		"modules/lib/extra/zephyr/module.yml":           "build:\n  settings:\n    snippet_root: .\n",
+		"modules/lib/extra/snippets/fancy/snippet.yml":  "",
+		"modules/lib/plain/zephyr/module.yml":           "build:\n  cmake: .\n",
+		"modules/lib/plain/snippets/hidden/snippet.yml": "name: hidden\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(wsRoot, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := DiscoverBuildExtras(wsRoot, filepath.Join(wsRoot, "apps", "demo"))
+	want := BuildExtras{
+		Conf:     []string{"debug.conf"},
+		Overlays: []string{"boards/nrf52840dk.overlay"},
+		Snippets: []string{"fancy", "local-usb", "rtt-console"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("DiscoverBuildExtras = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,94 @@
+package west
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FlashRunners lists the west flash and debug runners a board supports,
+// from the zephyr/runners.yaml written when its build is configured.
+type FlashRunners struct {
+	Runners     []string
+	FlashRunner string // used when west flash is given no --runner
+	DebugRunner string
+}
+
+// ReadFlashRunners reads zephyr/runners.yaml in buildDir, resolved against
+// wsRoot, or in its default image's build directory for sysbuild. It
+// returns an error wrapping os.ErrNotExist until the build is configured.
+func ReadFlashRunners(wsRoot, buildDir string) (*FlashRunners, error) {
+	dir := BuildDirPath(wsRoot, buildDir)
+	if d, err := ReadDomains(wsRoot, buildDir); err == nil && d.Default != "" {
+		dir = filepath.Join(dir, d.Default)
+	}
+	f, err := os.Open(filepath.Join(dir, "zephyr", "runners.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseRunnersYAML(f)
+}
+
+// ParseRunnersYAML reads the runner list and default runners from
+// runners.yaml. It is generated with a fixed shape:
+//
+//	runners:
+//	- nrfjprog
+//	- jlink
+//	flash-runner: nrfjprog
+//	debug-runner: jlink
+//	config:
+//	  ...
+//
+// so only its top-level keys are looked at.
+func ParseRunnersYAML(r io.Reader) (*FlashRunners, error) {
+	fr := &FlashRunners{}
+	var section string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if line[0] != ' ' && line[0] != '-' {
+			key, value, _ := strings.Cut(trimmed, ":")
+			section = key
+			switch key {
+			case "flash-runner":
+				fr.FlashRunner = yamlScalar(value)
+			case "debug-runner":
+				fr.DebugRunner = yamlScalar(value)
+			}
+			continue
+		}
+		if section == "runners" && strings.HasPrefix(trimmed, "- ") {
+			fr.Runners = append(fr.Runners, yamlScalar(strings.TrimPrefix(trimmed, "- ")))
+		}
+	}
+	return fr, scanner.Err()
+}
+
+// Supports reports whether name is one of the board's runners.
+func (fr *FlashRunners) Supports(name string) bool {
+	for _, r := range fr.Runners {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate returns an error naming the supported runners when runner is
+// set but not one of them. An empty runner means the board's default, and
+// a board listing no runners accepts any.
+func (fr *FlashRunners) Validate(runner string) error {
+	if runner == "" || len(fr.Runners) == 0 || fr.Supports(runner) {
+		return nil
+	}
+	return fmt.Errorf("runner %q is not supported by this board; use one of %s", runner, strings.Join(fr.Runners, ", "))
+}
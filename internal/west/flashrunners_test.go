@@ -0,0 +1,79 @@
+package west
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleRunnersYAML = `# Available runners configured by board.cmake.
+runners:
+- nrfjprog
+- nrfutil
+- jlink
+
+# Default flash runner if --runner is not given.
+flash-runner: nrfjprog
+
+# Default debug runner if --runner is not given.
+debug-runner: jlink
+
+# Common runner configuration values.
+config:
+  board_dir: /ws/zephyr/boards/nordic/nrf52840dk
+  elf_file: zephyr.elf
+
+# Runner specific arguments
+args:
+  nrfjprog:
+    - --nrf-family=NRF52
+  jlink:
+    - --dt-flash=y
+`
+
+func TestParseRunnersYAML(t *testing.T) {
+	fr, err := ParseRunnersYAML(strings.NewReader(sampleRunnersYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"nrfjprog", "nrfutil", "jlink"}; !reflect.DeepEqual(fr.Runners, want) {
+		t.Fatalf("Runners = %v, want %v", fr.Runners, want)
+	}
+	if fr.FlashRunner != "nrfjprog" || fr.DebugRunner != "jlink" {
+		t.Fatalf("defaults = %q, %q", fr.FlashRunner, fr.DebugRunner)
+	}
+	if err := fr.Validate("jlink"); err != nil {
+		t.Fatalf("jlink rejected: %v", err)
+	}
+	if err := fr.Validate(""); err != nil {
+		t.Fatalf("board default rejected: %v", err)
+	}
+	if err := fr.Validate("nrfjprg"); err == nil || !strings.Contains(err.Error(), "nrfjprog, nrfutil, jlink") {
+		t.Fatalf("expected mistyped runner to be rejected with the list, got %v", err)
+	}
+}
+
+func TestReadFlashRunnersUsesDefaultDomain(t *testing.T) {
+	ws := t.TempDir()
+	build := filepath.Join(ws, "build")
+	for dir, content := range map[string]string{
+		filepath.Join(build, "domains.yaml"):                  "default: app\ndomains:\n- name: app\n  build_dir: /x/build/app\n",
+		filepath.Join(build, "app", "zephyr", "runners.yaml"): sampleRunnersYAML,
+	} {
+		if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(dir, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fr, err := ReadFlashRunners(ws, "build")
+	if err != nil || fr.FlashRunner != "nrfjprog" {
+		t.Fatalf("ReadFlashRunners = %+v, %v", fr, err)
+	}
+	if _, err := ReadFlashRunners(ws, "missing"); !os.IsNotExist(err) {
+		t.Fatalf("expected an unconfigured build dir to report not-exist, got %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package west
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// FootprintKind selects the ROM or RAM footprint report.
+type FootprintKind string
+
+const (
+	FootprintROM FootprintKind = "rom"
+	FootprintRAM FootprintKind = "ram"
+)
+
+// FootprintNode is one entry of a footprint report: a directory, file or
+// symbol with the combined size of everything below it.
+type FootprintNode struct {
+	Name       string           `json:"name"`
+	Identifier string           `json:"identifier"`
+	Size       int64            `json:"size"`
+	Children   []*FootprintNode `json:"children,omitempty"`
+}
+
+// Footprint is the JSON written by Zephyr's size_report script as rom.json
+// or ram.json in the build directory.
+type Footprint struct {
+	Root      *FootprintNode `json:"symbols"`
+	TotalSize int64          `json:"total_size"`
+}
+
+// FootprintArgs returns the `west build` arguments that regenerate the
+// kind report for buildDir.
+func FootprintArgs(buildDir string, kind FootprintKind) []string {
+	args := []string{"build"}
+	if buildDir != "" {
+		args = append(args, "-d", buildDir)
+	}
+	return append(args, "-t", string(kind)+"_report")
+}
+
+// FootprintPath returns where the kind report for buildDir is written.
+func FootprintPath(wsRoot, buildDir string, kind FootprintKind) string {
+	return filepath.Join(BuildDirPath(wsRoot, buildDir), string(kind)+".json")
+}
+
+// LoadFootprint reads a footprint report. Children are sorted largest first.
+func LoadFootprint(path string) (*Footprint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f Footprint
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filepath.Base(path), err)
+	}
+	if f.Root == nil {
+		return nil, fmt.Errorf("parse %s: no symbols", filepath.Base(path))
+	}
+	if f.TotalSize == 0 {
+		f.TotalSize = f.Root.Size
+	}
+	sortFootprint(f.Root)
+	return &f, nil
+}
+
+func sortFootprint(n *FootprintNode) {
+	sort.SliceStable(n.Children, func(i, j int) bool {
+		return n.Children[i].Size > n.Children[j].Size
+	})
+	for _, c := range n.Children {
+		sortFootprint(c)
+	}
+}
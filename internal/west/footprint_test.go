@@ -0,0 +1,52 @@
+package west
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadFootprintSortsChildrenBySize(t *testing.T) {
+	dir := t.TempDir()
+	data := `{
+  "symbols": {
+    "name": "Root", "identifier": "root", "size": 300,
+    "children": [
+      {"name": "kernel", "identifier": ":/kernel", "size": 100},
+      {"name": "drivers", "identifier": ":/drivers", "size": 200, "children": [
+        {"name": "uart.c", "identifier": ":/drivers/uart.c", "size": 50},
+        {"name": "gpio.c", "identifier": ":/drivers/gpio.c", "size": 150}
+      ]}
+    ]
+  },
+  "total_size": 300
+}`
+	path := FootprintPath(dir, "out", FootprintROM)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := LoadFootprint(path)
+	if err != nil {
+		t.Fatalf("LoadFootprint: %v", err)
+	}
+	if f.TotalSize != 300 || f.Root.Children[0].Name != "drivers" || f.Root.Children[0].Children[0].Name != "gpio.c" {
+		t.Fatalf("expected children sorted by size, got %+v", f.Root)
+	}
+
+	if _, err := LoadFootprint(filepath.Join(dir, "missing.json")); err == nil {
+		t.Fatal("expected error for missing report")
+	}
+}
+
+func TestFootprintArgs(t *testing.T) {
+	got := FootprintArgs("build/nrf", FootprintRAM)
+	want := []string{"build", "-d", "build/nrf", "-t", "ram_report"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("FootprintArgs = %v, want %v", got, want)
+	}
+}
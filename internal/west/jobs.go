@@ -0,0 +1,294 @@
+package west
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxFinishedJobs bounds how many completed jobs the manager keeps.
+const maxFinishedJobs = 50
+
+// JobState is the lifecycle state of a tracked command.
+type JobState int
+
+const (
+	JobRunning JobState = iota
+	JobSucceeded
+	JobFailed
+	JobCancelled
+)
+
+func (s JobState) String() string {
+	switch s {
+	case JobRunning:
+		return "running"
+	case JobSucceeded:
+		return "done"
+	case JobFailed:
+		return "failed"
+	case JobCancelled:
+		return "cancelled"
+	}
+	return "unknown"
+}
+
+// Job is a snapshot of one command launched through a tracked Runner.
+type Job struct {
+	ID       string
+	Command  string
+	Start    time.Time
+	End      time.Time // zero while running
+	State    JobState
+	ExitCode int
+}
+
+// Duration returns how long the job ran, or has been running so far.
+func (j Job) Duration() time.Duration {
+	if j.End.IsZero() {
+		return time.Since(j.Start)
+	}
+	return j.End.Sub(j.Start)
+}
+
+type trackedJob struct {
+	Job
+	output strings.Builder
+	cancel context.CancelFunc
+}
+
+// JobManager records every command started through a Runner returned by
+// TrackJobs, including its output, so the UI can show what is running
+// anywhere in the application. It is safe for concurrent use.
+type JobManager struct {
+	mu   sync.Mutex
+	jobs []*trackedJob
+	seq  int
+}
+
+// NewJobManager creates an empty JobManager.
+func NewJobManager() *JobManager {
+	return &JobManager{}
+}
+
+// Jobs returns snapshots of all known jobs, newest first.
+func (m *JobManager) Jobs() []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	jobs := make([]Job, 0, len(m.jobs))
+	for i := len(m.jobs) - 1; i >= 0; i-- {
+		jobs = append(jobs, m.jobs[i].Job)
+	}
+	return jobs
+}
+
+// Running returns the number of jobs that have not finished yet.
+func (m *JobManager) Running() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, j := range m.jobs {
+		if j.State == JobRunning {
+			n++
+		}
+	}
+	return n
+}
+
+// Output returns the output collected so far for the job with the given ID.
+func (m *JobManager) Output(id string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if j := m.find(id); j != nil {
+		return j.output.String()
+	}
+	return ""
+}
+
+// Cancel stops a running job. It returns false if the job is unknown or has
+// already finished.
+func (m *JobManager) Cancel(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j := m.find(id)
+	if j == nil || j.State != JobRunning {
+		return false
+	}
+	j.cancel()
+	return true
+}
+
+// ClearFinished forgets all jobs that are no longer running.
+func (m *JobManager) ClearFinished() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	kept := m.jobs[:0]
+	for _, j := range m.jobs {
+		if j.State == JobRunning {
+			kept = append(kept, j)
+		}
+	}
+	m.jobs = kept
+}
+
+func (m *JobManager) find(id string) *trackedJob {
+	for _, j := range m.jobs {
+		if j.ID == id {
+			return j
+		}
+	}
+	return nil
+}
+
+func (m *JobManager) add(command string, cancel context.CancelFunc) *trackedJob {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seq++
+	j := &trackedJob{
+		Job: Job{
+			ID:      fmt.Sprintf("job-%d", m.seq),
+			Command: command,
+			Start:   time.Now(),
+			State:   JobRunning,
+		},
+		cancel: cancel,
+	}
+	m.jobs = append(m.jobs, j)
+	m.prune()
+	return j
+}
+
+// prune drops the oldest finished jobs beyond maxFinishedJobs.
+func (m *JobManager) prune() {
+	finished := 0
+	for _, j := range m.jobs {
+		if j.State != JobRunning {
+			finished++
+		}
+	}
+	if finished <= maxFinishedJobs {
+		return
+	}
+	drop := finished - maxFinishedJobs
+	kept := m.jobs[:0]
+	for _, j := range m.jobs {
+		if drop > 0 && j.State != JobRunning {
+			drop--
+			continue
+		}
+		kept = append(kept, j)
+	}
+	m.jobs = kept
+}
+
+func (m *JobManager) appendLine(j *trackedJob, line string) {
+	m.mu.Lock()
+	j.output.WriteString(line + "\n")
+	m.mu.Unlock()
+}
+
+func (m *JobManager) complete(j *trackedJob, final tea.Msg) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j.End = time.Now()
+	result, ok := final.(CommandResultMsg)
+	switch {
+	case !ok:
+		j.State = JobFailed
+		j.ExitCode = -1
+	case result.Cancelled:
+		j.State = JobCancelled
+		j.ExitCode = result.ExitCode
+	case result.ExitCode == 0:
+		j.State = JobSucceeded
+	default:
+		j.State = JobFailed
+		j.ExitCode = result.ExitCode
+	}
+	if ok && !result.Streamed {
+		j.output.WriteString(result.Output)
+	}
+	j.cancel()
+	m.prune()
+}
+
+// track registers a job when cmd runs and drives cmd to completion in the
+// background, so the job finishes even if the page that started it stops
+// following its output.
+func (m *JobManager) track(ctx context.Context, command string, start func(context.Context) tea.Cmd) tea.Cmd {
+	ctx, cancel := context.WithCancel(ctx)
+	inner := start(ctx)
+	if inner == nil {
+		cancel()
+		return nil
+	}
+	var j *trackedJob
+	observed := observe(inner,
+		func(line string) { m.appendLine(j, line) },
+		func(final tea.Msg) { m.complete(j, final) },
+	)
+	return func() tea.Msg {
+		j = m.add(command, cancel)
+		return observed()
+	}
+}
+
+// TrackJobs wraps r so every command it starts is recorded in m.
+func TrackJobs(r Runner, m *JobManager) Runner {
+	return trackedRunner{inner: r, jobs: m}
+}
+
+type trackedRunner struct {
+	inner Runner
+	jobs  *JobManager
+}
+
+func (t trackedRunner) Run(ctx context.Context, name string, args ...string) tea.Cmd {
+	if isInternal(ctx) {
+		return t.inner.Run(ctx, name, args...)
+	}
+	command := strings.Join(append([]string{name}, args...), " ")
+	return t.jobs.track(ctx, command, func(ctx context.Context) tea.Cmd {
+		return t.inner.Run(ctx, name, args...)
+	})
+}
+
+func (t trackedRunner) Status(ctx context.Context) tea.Cmd {
+	return t.jobs.track(ctx, "west status", t.inner.Status)
+}
+
+func (t trackedRunner) List(ctx context.Context) tea.Cmd {
+	return t.jobs.track(ctx, "west list", t.inner.List)
+}
+
+func (t trackedRunner) Diff(ctx context.Context) tea.Cmd {
+	return t.jobs.track(ctx, "west diff", t.inner.Diff)
+}
+
+func (t trackedRunner) Update(ctx context.Context) tea.Cmd {
+	return t.jobs.track(ctx, "west update", t.inner.Update)
+}
+
+func (t trackedRunner) Init(ctx context.Context) tea.Cmd {
+	return t.jobs.track(ctx, "west init -l .", t.inner.Init)
+}
+
+func (t trackedRunner) ZephyrExport(ctx context.Context) tea.Cmd {
+	return t.jobs.track(ctx, "west zephyr-export", t.inner.ZephyrExport)
+}
+
+func (t trackedRunner) PackagesPipInstall(ctx context.Context) tea.Cmd {
+	return t.jobs.track(ctx, "west packages pip --install", t.inner.PackagesPipInstall)
+}
+
+func (t trackedRunner) SdkInstall(ctx context.Context) tea.Cmd {
+	return t.jobs.track(ctx, "west sdk install", t.inner.SdkInstall)
+}
+
+func (t trackedRunner) InstallBrewDeps(ctx context.Context) tea.Cmd {
+	return t.jobs.track(ctx, "brew install (missing dependencies)", t.inner.InstallBrewDeps)
+}
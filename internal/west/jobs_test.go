@@ -0,0 +1,122 @@
+package west
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrackJobsRecordsCommandAndOutput(t *testing.T) {
+	m := NewJobManager()
+	r := TrackJobs(DefaultRunner{}, m)
+
+	msg := Drain(r.Run(context.Background(), "sh", "-c", "echo hello; exit 2"), nil)
+	result, ok := msg.(CommandResultMsg)
+	if !ok {
+		t.Fatalf("expected CommandResultMsg, got %T", msg)
+	}
+	if result.ExitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", result.ExitCode)
+	}
+
+	jobs := m.Jobs()
+	if len(jobs) != 1 {
+		t.Fatalf("expected 1 job, got %d", len(jobs))
+	}
+	j := jobs[0]
+	if j.Command != "sh -c echo hello; exit 2" {
+		t.Fatalf("unexpected command %q", j.Command)
+	}
+	if j.State != JobFailed || j.ExitCode != 2 {
+		t.Fatalf("expected failed job with exit 2, got %v/%d", j.State, j.ExitCode)
+	}
+	if got := m.Output(j.ID); got != "hello\n" {
+		t.Fatalf("expected job output hello, got %q", got)
+	}
+	if m.Running() != 0 {
+		t.Fatalf("expected no running jobs, got %d", m.Running())
+	}
+}
+
+func TestTrackJobsSkipsInternalCommands(t *testing.T) {
+	m := NewJobManager()
+	var out strings.Builder
+	n := &Notifier{Methods: map[string][]string{EventBuild: {NotifyBell}}, Out: &out}
+	r := TrackJobs(NotifyRunner(scriptedRunner{result: CommandResultMsg{ExitCode: 0}}, n), m)
+
+	msg := Drain(r.Run(Internal(context.Background()), "west", "build", "-b", "qemu_x86"), nil)
+	if _, ok := msg.(CommandResultMsg); !ok {
+		t.Fatalf("expected CommandResultMsg, got %T", msg)
+	}
+	if jobs := m.Jobs(); len(jobs) != 0 {
+		t.Fatalf("expected internal command to stay off the jobs list, got %+v", jobs)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("expected no notification for internal command, got %q", out.String())
+	}
+}
+
+func TestTrackJobsFinishesWithoutConsumer(t *testing.T) {
+	m := NewJobManager()
+	r := TrackJobs(DefaultRunner{}, m)
+
+	// Read only the first line, as a page that was cleared mid-run would.
+	cmd := r.Run(context.Background(), "sh", "-c", "echo one; echo two")
+	if _, ok := cmd().(CommandOutputMsg); !ok {
+		t.Fatal("expected first output line")
+	}
+	waitForJobs(t, m)
+
+	j := m.Jobs()[0]
+	if j.State != JobSucceeded {
+		t.Fatalf("expected succeeded job, got %v", j.State)
+	}
+	if got := m.Output(j.ID); got != "one\ntwo\n" {
+		t.Fatalf("expected full output, got %q", got)
+	}
+}
+
+func TestJobManagerCancel(t *testing.T) {
+	m := NewJobManager()
+	r := TrackJobs(DefaultRunner{}, m)
+
+	cmd := r.Run(context.Background(), "sh", "-c", "echo started; sleep 30")
+	if _, ok := cmd().(CommandOutputMsg); !ok {
+		t.Fatal("expected first output line")
+	}
+	id := m.Jobs()[0].ID
+	if !m.Cancel(id) {
+		t.Fatal("expected Cancel to succeed for running job")
+	}
+	waitForJobs(t, m)
+
+	if state := m.Jobs()[0].State; state != JobCancelled {
+		t.Fatalf("expected cancelled job, got %v", state)
+	}
+	if m.Cancel(id) {
+		t.Fatal("expected Cancel to fail for finished job")
+	}
+}
+
+func TestJobManagerClearFinished(t *testing.T) {
+	m := NewJobManager()
+	r := TrackJobs(DefaultRunner{}, m)
+	Drain(r.Run(context.Background(), "true"), nil)
+
+	m.ClearFinished()
+	if len(m.Jobs()) != 0 {
+		t.Fatalf("expected finished jobs to be cleared, got %d", len(m.Jobs()))
+	}
+}
+
+func waitForJobs(t *testing.T, m *JobManager) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for m.Running() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for jobs to finish")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,28 @@
+package west
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// MatrixBuildDir returns the build directory for one project/board cell of
+// a build matrix: <base>-matrix/<project>/<board>, with path separators and
+// board qualifiers flattened so every cell gets its own directory. It sits
+// beside base rather than inside it so a pristine build of base leaves the
+// matrix alone. A base template that already separates projects and
+// boards is expanded and used as is.
+func MatrixBuildDir(base, project, board, profile string) string {
+	if strings.Contains(base, "{project}") && strings.Contains(base, "{board}") {
+		return ExpandBuildDir(base, project, board, profile)
+	}
+	base = ExpandBuildDir(base, project, board, profile)
+	if base == "" {
+		base = "build"
+	}
+	return filepath.Join(base+"-matrix", matrixDirName(project), matrixDirName(board))
+}
+
+func matrixDirName(s string) string {
+	s = strings.Trim(filepath.ToSlash(s), "/")
+	return strings.NewReplacer("/", "_", ":", "_", " ", "_").Replace(s)
+}
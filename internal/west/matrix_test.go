@@ -0,0 +1,39 @@
+package west
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMatrixBuildDir(t *testing.T) {
+	cases := []struct {
+		base, project, board, want string
+	}{
+		{"build", "apps/blinky", "nrf52840dk", filepath.Join("build-matrix", "apps_blinky", "nrf52840dk")},
+		{"", "apps/blinky", "nrf5340dk/nrf5340/cpuapp", filepath.Join("build-matrix", "apps_blinky", "nrf5340dk_nrf5340_cpuapp")},
+		{"out", "app", "native_sim", filepath.Join("out-matrix", "app", "native_sim")},
+		{"build/{project}/{board}", "apps/blinky", "native_sim", "build/apps_blinky/native_sim"},
+		{"build-{profile}", "apps/blinky", "native_sim", filepath.Join("build-default-matrix", "apps_blinky", "native_sim")},
+	}
+	for _, c := range cases {
+		if got := MatrixBuildDir(c.base, c.project, c.board, ""); got != c.want {
+			t.Errorf("MatrixBuildDir(%q, %q, %q) = %q, want %q", c.base, c.project, c.board, got, c.want)
+		}
+	}
+}
+
+func TestExpandBuildDir(t *testing.T) {
+	cases := []struct {
+		tmpl, project, board, profile, want string
+	}{
+		{"build", "apps/blinky", "nrf52840dk", "debug", "build"},
+		{"build/{project}/{board}", "apps/blinky", "nrf5340dk/nrf5340/cpuapp", "", "build/apps_blinky/nrf5340dk_nrf5340_cpuapp"},
+		{"build-{profile}", ".", "native_sim", "", "build-default"},
+		{"build-{profile}-{project}", "", "native_sim", "release", "build-release-app"},
+	}
+	for _, c := range cases {
+		if got := ExpandBuildDir(c.tmpl, c.project, c.board, c.profile); got != c.want {
+			t.Errorf("ExpandBuildDir(%q, %q, %q, %q) = %q, want %q", c.tmpl, c.project, c.board, c.profile, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package west
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/buckleypaul/gust/internal/store"
+)
+
+// NinjaStep is one command ninja ran, as recorded in .ninja_log.
+type NinjaStep struct {
+	Output   string // path relative to the build directory
+	Module   string // e.g. app, zephyr/kernel, zephyr/drivers, modules/hal_nordic
+	Link     bool   // links an executable rather than compiling or archiving
+	Duration time.Duration
+}
+
+// ModuleTiming is the time spent on one module's steps.
+type ModuleTiming struct {
+	Module   string
+	Duration time.Duration
+	Steps    int
+}
+
+// BuildTiming breaks down the last ninja run of a build directory.
+type BuildTiming struct {
+	Steps   []NinjaStep // slowest first
+	Modules []ModuleTiming
+	// CPU is the sum of all step durations; Wall is from the first step
+	// starting to the last one finishing.
+	CPU  time.Duration
+	Wall time.Duration
+}
+
+// timingRecordSteps and timingRecordModules bound what build history keeps
+// of a build's timing.
+const (
+	timingRecordSteps   = 10
+	timingRecordModules = 10
+)
+
+// mtimeSlack allows for file systems stamping modification times from a
+// coarser clock than time.Now.
+const mtimeSlack = time.Second
+
+// ReadBuildTiming reads the .ninja_log of buildDir, resolved against
+// wsRoot. For sysbuild every image's log is included and modules are
+// prefixed with the image name. Logs not written since the given time
+// belong to an earlier build and are skipped, so a build with nothing to
+// do returns os.ErrNotExist.
+func ReadBuildTiming(wsRoot, buildDir string, since time.Time) (*BuildTiming, error) {
+	dir := BuildDirPath(wsRoot, buildDir)
+	d, err := ReadDomains(wsRoot, buildDir)
+	if err != nil {
+		return readNinjaLogFile(filepath.Join(dir, ".ninja_log"), "", since)
+	}
+	var merged *BuildTiming
+	for _, domain := range d.Domains {
+		t, err := readNinjaLogFile(filepath.Join(dir, domain, ".ninja_log"), domain, since)
+		if err != nil {
+			continue
+		}
+		if merged == nil {
+			merged = &BuildTiming{}
+		}
+		merged.Steps = append(merged.Steps, t.Steps...)
+		merged.CPU += t.CPU
+		merged.Wall += t.Wall // images are built one after another
+	}
+	if merged == nil {
+		return nil, os.ErrNotExist
+	}
+	merged.summarize()
+	return merged, nil
+}
+
+func readNinjaLogFile(path, domain string, since time.Time) (*BuildTiming, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if fi, err := f.Stat(); err != nil {
+		return nil, err
+	} else if fi.ModTime().Before(since.Add(-mtimeSlack)) {
+		return nil, os.ErrNotExist
+	}
+	t, err := ParseNinjaLog(f)
+	if err != nil || domain == "" {
+		return t, err
+	}
+	for i := range t.Steps {
+		t.Steps[i].Output = domain + "/" + t.Steps[i].Output
+		t.Steps[i].Module = domain + "/" + t.Steps[i].Module
+	}
+	t.summarize()
+	return t, nil
+}
+
+// ParseNinjaLog parses a v5 or later .ninja_log, keeping only the last run.
+// Each line is "start_ms\tend_ms\tmtime\toutput\thash" with times relative
+// to the start of its run. Ninja appends steps as they finish, so a step
+// ending before the previous one marks the start of a new run.
+func ParseNinjaLog(r io.Reader) (*BuildTiming, error) {
+	type entry struct {
+		start, end int64
+		output     string
+	}
+	var run []entry
+	var lastEnd int64 = -1
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 4 {
+			continue
+		}
+		start, err1 := strconv.ParseInt(fields[0], 10, 64)
+		end, err2 := strconv.ParseInt(fields[1], 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		if end < lastEnd {
+			run = run[:0]
+		}
+		lastEnd = end
+		run = append(run, entry{start, end, fields[3]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	t := &BuildTiming{}
+	var first, last int64 = -1, 0
+	// One command can produce several outputs, each logged with the same
+	// times; count it once.
+	seen := map[[2]int64]bool{}
+	for _, e := range run {
+		if first < 0 || e.start < first {
+			first = e.start
+		}
+		if e.end > last {
+			last = e.end
+		}
+		key := [2]int64{e.start, e.end}
+		if seen[key] && e.end > e.start {
+			continue
+		}
+		seen[key] = true
+		d := time.Duration(e.end-e.start) * time.Millisecond
+		t.CPU += d
+		t.Steps = append(t.Steps, NinjaStep{
+			Output:   e.output,
+			Module:   ninjaModule(e.output),
+			Link:     isLinkOutput(e.output),
+			Duration: d,
+		})
+	}
+	if first >= 0 {
+		t.Wall = time.Duration(last-first) * time.Millisecond
+	}
+	t.summarize()
+	return t, nil
+}
+
+// Slowest returns up to n of the slowest compile and link steps, leaving
+// out code generation and archiving.
+func (t *BuildTiming) Slowest(n int) []NinjaStep {
+	var steps []NinjaStep
+	for _, s := range t.Steps {
+		if len(steps) == n {
+			break
+		}
+		switch ext := filepath.Ext(s.Output); {
+		case s.Link, ext == ".obj", ext == ".o":
+			steps = append(steps, s)
+		}
+	}
+	return steps
+}
+
+// TimingRecord summarises t for build history, or returns nil for a nil t.
+func TimingRecord(t *BuildTiming) *store.BuildTiming {
+	if t == nil {
+		return nil
+	}
+	r := &store.BuildTiming{
+		Steps: len(t.Steps),
+		CPU:   t.CPU.Milliseconds(),
+		Wall:  t.Wall.Milliseconds(),
+	}
+	for i, m := range t.Modules {
+		if i == timingRecordModules {
+			break
+		}
+		r.Modules = append(r.Modules, store.StepTiming{Name: m.Module, Time: m.Duration.Milliseconds(), Steps: m.Steps})
+	}
+	for _, s := range t.Slowest(timingRecordSteps) {
+		r.Slowest = append(r.Slowest, store.StepTiming{Name: s.Output, Module: s.Module, Time: s.Duration.Milliseconds(), Link: s.Link})
+	}
+	return r
+}
+
+// summarize sorts the steps and totals them per module.
+func (t *BuildTiming) summarize() {
+	sort.SliceStable(t.Steps, func(i, j int) bool { return t.Steps[i].Duration > t.Steps[j].Duration })
+	byModule := map[string]int{}
+	t.Modules = nil
+	for _, s := range t.Steps {
+		i, ok := byModule[s.Module]
+		if !ok {
+			i = len(t.Modules)
+			byModule[s.Module] = i
+			t.Modules = append(t.Modules, ModuleTiming{Module: s.Module})
+		}
+		t.Modules[i].Duration += s.Duration
+		t.Modules[i].Steps++
+	}
+	sort.SliceStable(t.Modules, func(i, j int) bool { return t.Modules[i].Duration > t.Modules[j].Duration })
+}
+
+// ninjaModule names the part of the tree an output belongs to, from the
+// layout Zephyr's CMake gives the build directory.
+func ninjaModule(output string) string {
+	parts := strings.Split(filepath.ToSlash(output), "/")
+	switch {
+	case len(parts) >= 2 && parts[0] == "CMakeFiles" && parts[1] == "app.dir":
+		return "app"
+	case isLinkOutput(output):
+		return "link"
+	case parts[0] == "zephyr" && len(parts) > 2 && parts[1] != "CMakeFiles":
+		return "zephyr/" + parts[1]
+	case parts[0] == "zephyr":
+		return "zephyr"
+	case parts[0] == "modules" && len(parts) > 2:
+		return "modules/" + parts[1]
+	case len(parts) == 1:
+		return "other"
+	}
+	return parts[0]
+}
+
+func isLinkOutput(output string) bool {
+	switch filepath.Ext(output) {
+	case ".elf", ".exe":
+		return true
+	}
+	return false
+}
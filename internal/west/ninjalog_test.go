@@ -0,0 +1,104 @@
+package west
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleNinjaLog = `# ninja log v5
+0	900	1700000000	zephyr/kernel/CMakeFiles/kernel.dir/sched.c.obj	aa
+0	5000	1700000000	CMakeFiles/app.dir/src/main.c.obj	bb
+5000	6000	1700000000	zephyr/zephyr.elf	cc
+20	800	1700000100	modules/hal_nordic/modules/hal_nordic/nrfx/CMakeFiles/nrfx.dir/nrfx_uarte.c.obj	ee
+0	1200	1700000100	zephyr/kernel/CMakeFiles/kernel.dir/sched.c.obj	aa
+10	2500	1700000100	zephyr/drivers/serial/CMakeFiles/drivers__serial.dir/uart_nrfx_uarte.c.obj	dd
+30	3000	1700000100	zephyr/kernel/CMakeFiles/kernel.dir/thread.c.obj	ff
+3000	3100	1700000100	zephyr/kernel/libkernel.a	11
+3100	7100	1700000100	zephyr/zephyr_pre0.elf	22
+3100	7100	1700000100	zephyr/zephyr_pre0.map	22
+7100	7300	1700000100	zephyr/zephyr.elf	33
+`
+
+func TestParseNinjaLogKeepsLastRun(t *testing.T) {
+	timing, err := ParseNinjaLog(strings.NewReader(sampleNinjaLog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(timing.Steps) != 7 {
+		t.Fatalf("steps = %d, want 7 (the .map shares its command with the .elf)", len(timing.Steps))
+	}
+	if timing.Wall != 7300*time.Millisecond {
+		t.Fatalf("wall = %v", timing.Wall)
+	}
+	if want := (1200 + 2490 + 780 + 2970 + 100 + 4000 + 200) * time.Millisecond; timing.CPU != want {
+		t.Fatalf("cpu = %v, want %v", timing.CPU, want)
+	}
+	if s := timing.Steps[0]; s.Output != "zephyr/zephyr_pre0.elf" || !s.Link || s.Module != "link" {
+		t.Fatalf("slowest step = %+v", s)
+	}
+
+	modules := map[string]time.Duration{}
+	for _, m := range timing.Modules {
+		modules[m.Module] = m.Duration
+	}
+	if modules["zephyr/kernel"] != 4270*time.Millisecond {
+		t.Fatalf("kernel = %v (%v)", modules["zephyr/kernel"], timing.Modules)
+	}
+	if modules["zephyr/drivers"] == 0 || modules["modules/hal_nordic"] == 0 {
+		t.Fatalf("modules = %v", timing.Modules)
+	}
+	if _, ok := modules["app"]; ok {
+		t.Fatalf("app was not rebuilt in the last run: %v", timing.Modules)
+	}
+
+	slowest := timing.Slowest(3)
+	if len(slowest) != 3 || slowest[1].Output != "zephyr/kernel/CMakeFiles/kernel.dir/thread.c.obj" {
+		t.Fatalf("Slowest = %+v", slowest)
+	}
+	for _, s := range timing.Slowest(10) {
+		if strings.HasSuffix(s.Output, ".a") {
+			t.Fatalf("archive step listed as slowest: %+v", s)
+		}
+	}
+}
+
+func TestNinjaModule(t *testing.T) {
+	for output, want := range map[string]string{
+		"CMakeFiles/app.dir/src/main.c.obj":                  "app",
+		"zephyr/arch/arch/arm/core/CMakeFiles/x.dir/a.c.obj": "zephyr/arch",
+		"zephyr/CMakeFiles/zephyr.dir/lib/os/printk.c.obj":   "zephyr",
+		"modules/hal_stm32/CMakeFiles/x.dir/stm32_hal.c.obj": "modules/hal_stm32",
+		"zephyr/zephyr.elf":                                  "link",
+		"build.ninja":                                        "other",
+	} {
+		if got := ninjaModule(output); got != want {
+			t.Errorf("ninjaModule(%q) = %q, want %q", output, got, want)
+		}
+	}
+}
+
+func TestReadBuildTimingSkipsStaleLog(t *testing.T) {
+	ws := t.TempDir()
+	log := filepath.Join(ws, "build", ".ninja_log")
+	if err := os.MkdirAll(filepath.Dir(log), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(log, []byte(sampleNinjaLog), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(log, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReadBuildTiming(ws, "build", time.Now().Add(-time.Minute)); !os.IsNotExist(err) {
+		t.Fatalf("expected a stale log to be skipped, got %v", err)
+	}
+	timing, err := ReadBuildTiming(ws, "build", old.Add(-time.Minute))
+	if err != nil || len(timing.Steps) != 7 {
+		t.Fatalf("ReadBuildTiming = %+v, %v", timing, err)
+	}
+}
@@ -0,0 +1,226 @@
+package west
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Operations that can announce they finished.
+const (
+	EventBuild  = "build"
+	EventFlash  = "flash"
+	EventTest   = "test"
+	EventUpdate = "update"
+)
+
+// Ways of announcing a finished operation.
+const (
+	NotifyBell    = "bell"    // terminal bell
+	NotifyOSC9    = "osc9"    // OSC 9 desktop notification (iTerm2, WezTerm, Windows Terminal)
+	NotifyOSC777  = "osc777"  // OSC 777 desktop notification (urxvt, foot, Ghostty)
+	NotifyCommand = "command" // external command such as notify-send
+)
+
+// Notifier announces finished operations in the ways configured for each
+// event. It is safe for concurrent use.
+type Notifier struct {
+	// Methods maps an event to the ways it is announced.
+	Methods map[string][]string
+	// Command is run for NotifyCommand; {title} and {message} in its
+	// arguments are replaced.
+	Command []string
+	// Out receives the bell and escape sequences. Notifications are sent
+	// from command goroutines, so it must not be the writer Bubble Tea
+	// renders to; use OpenTerminal.
+	Out io.Writer
+
+	mu sync.Mutex
+	// start launches Command; it is replaced in tests.
+	start func(name string, args ...string) error
+}
+
+// Notify announces the result of an operation of the given event type.
+func (n *Notifier) Notify(event string, result CommandResultMsg) {
+	methods := n.Methods[event]
+	if len(methods) == 0 {
+		return
+	}
+	title, message := notificationText(event, result)
+	for _, m := range methods {
+		switch m {
+		case NotifyBell:
+			n.write("\a")
+		case NotifyOSC9:
+			n.write("\x1b]9;" + oscText(title+": "+message) + "\a")
+		case NotifyOSC777:
+			n.write("\x1b]777;notify;" + oscText(title) + ";" + oscText(message) + "\a")
+		case NotifyCommand:
+			if len(n.Command) == 0 {
+				continue
+			}
+			r := strings.NewReplacer("{title}", title, "{message}", message)
+			args := make([]string, len(n.Command)-1)
+			for i, a := range n.Command[1:] {
+				args[i] = r.Replace(a)
+			}
+			start := n.start
+			if start == nil {
+				start = startDetached
+			}
+			_ = start(n.Command[0], args...)
+		}
+	}
+}
+
+// OpenTerminal opens the terminal for writing through its own file
+// descriptor, so notification sequences are written whole rather than
+// interleaved with frames the renderer is writing to stdout.
+func OpenTerminal() (io.WriteCloser, error) {
+	return os.OpenFile(terminalPath, os.O_WRONLY, 0)
+}
+
+func (n *Notifier) write(s string) {
+	if n.Out == nil {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, _ = io.WriteString(n.Out, s)
+}
+
+// startDetached runs a notification command without waiting for it.
+func startDetached(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+// notificationText describes a result, e.g. "gust: build" and
+// "succeeded in 3m12s".
+func notificationText(event string, result CommandResultMsg) (title, message string) {
+	duration := result.Duration.Round(100 * time.Millisecond).String()
+	switch {
+	case result.Cancelled:
+		message = "cancelled after " + duration
+	case result.ExitCode != 0:
+		message = fmt.Sprintf("failed (exit code %d) after %s", result.ExitCode, duration)
+	default:
+		message = "succeeded in " + duration
+	}
+	return "gust: " + event, message
+}
+
+// oscText strips the characters that would end an OSC sequence early or
+// split OSC 777 fields.
+func oscText(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == ';' {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// CommandEvent classifies a west command as one of the notified events, or
+// returns "" for commands that are not announced. Only a west build of the
+// default target is a build; -t run is a test and other targets, such as
+// rom_report, are not announced.
+func CommandEvent(name string, args []string) string {
+	if name != "west" || len(args) == 0 {
+		return ""
+	}
+	switch args[0] {
+	case "build":
+		switch target, ok := buildTarget(args[1:]); {
+		case !ok:
+			return ""
+		case target == "":
+			return EventBuild
+		case target == "run":
+			return EventTest
+		}
+		return ""
+	case "flash":
+		return EventFlash
+	case "twister":
+		return EventTest
+	case "update":
+		return EventUpdate
+	}
+	return ""
+}
+
+// buildTarget returns the -t/--target of west build arguments, or ok false
+// for a build that only runs CMake. Arguments after -- go to CMake.
+func buildTarget(args []string) (target string, ok bool) {
+	for i, a := range args {
+		switch {
+		case a == "--":
+			return target, true
+		case a == "--cmake-only":
+			return "", false
+		case (a == "-t" || a == "--target") && i+1 < len(args):
+			target = args[i+1]
+		case strings.HasPrefix(a, "--target="):
+			target = strings.TrimPrefix(a, "--target=")
+		}
+	}
+	return target, true
+}
+
+// NotifyRunner wraps r so builds, flashes, tests and updates it runs are
+// announced through n when they finish. Wrap it inside TrackJobs so
+// commands are announced even when no page follows their output.
+func NotifyRunner(r Runner, n *Notifier) Runner {
+	return notifyingRunner{Runner: r, n: n}
+}
+
+type notifyingRunner struct {
+	Runner
+	n *Notifier
+}
+
+func (r notifyingRunner) Run(ctx context.Context, name string, args ...string) tea.Cmd {
+	cmd := r.Runner.Run(ctx, name, args...)
+	if isInternal(ctx) {
+		return cmd
+	}
+	if event := CommandEvent(name, args); event != "" {
+		return notifyOnResult(event, r.n, cmd)
+	}
+	return cmd
+}
+
+func (r notifyingRunner) Update(ctx context.Context) tea.Cmd {
+	return notifyOnResult(EventUpdate, r.n, r.Runner.Update(ctx))
+}
+
+// notifyOnResult follows cmd's streamed output and announces its result.
+func notifyOnResult(event string, n *Notifier, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case CommandOutputMsg:
+			msg.Next = notifyOnResult(event, n, msg.Next)
+			return msg
+		case CommandResultMsg:
+			n.Notify(event, msg)
+			return msg
+		default:
+			return msg
+		}
+	}
+}
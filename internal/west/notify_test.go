@@ -0,0 +1,90 @@
+package west
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// scriptedRunner streams one line and then a fixed result for every command.
+type scriptedRunner struct {
+	DefaultRunner
+	result CommandResultMsg
+}
+
+func (s scriptedRunner) Run(ctx context.Context, name string, args ...string) tea.Cmd {
+	return func() tea.Msg {
+		return CommandOutputMsg{Line: "[1/1] Linking", Next: func() tea.Msg { return s.result }}
+	}
+}
+
+func (s scriptedRunner) Update(ctx context.Context) tea.Cmd {
+	return func() tea.Msg { return s.result }
+}
+
+func TestCommandEvent(t *testing.T) {
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"build", "-b", "qemu_x86", "app"}, EventBuild},
+		{[]string{"build", "-t", "run", "-b", "qemu_x86"}, EventTest},
+		{[]string{"build", "-d", "build", "--cmake-only", "--", "-DFOO=1"}, ""},
+		{[]string{"build", "-d", "build", "-t", "rom_report"}, ""},
+		{[]string{"build", "-d", "build", "--target=ram_report"}, ""},
+		{[]string{"build", "-b", "qemu_x86", "app", "--", "-t", "run"}, EventBuild},
+		{[]string{"flash", "-d", "build"}, EventFlash},
+		{[]string{"twister", "-T", "tests"}, EventTest},
+		{[]string{"update"}, EventUpdate},
+		{[]string{"boards"}, ""},
+	}
+	for _, c := range cases {
+		if got := CommandEvent("west", c.args); got != c.want {
+			t.Errorf("CommandEvent(%v) = %q, want %q", c.args, got, c.want)
+		}
+	}
+}
+
+func TestNotifyRunnerAnnouncesConfiguredEvents(t *testing.T) {
+	var out strings.Builder
+	var started [][]string
+	n := &Notifier{
+		Methods: map[string][]string{
+			EventBuild:  {NotifyBell, NotifyOSC777, NotifyCommand},
+			EventUpdate: {NotifyOSC9},
+		},
+		Command: []string{"notify-send", "{title}", "{message}"},
+		Out:     &out,
+		start: func(name string, args ...string) error {
+			started = append(started, append([]string{name}, args...))
+			return nil
+		},
+	}
+	r := NotifyRunner(scriptedRunner{result: CommandResultMsg{ExitCode: 2, Duration: 3 * time.Minute}}, n)
+
+	if _, ok := Drain(r.Run(context.Background(), "west", "build", "-b", "qemu_x86"), nil).(CommandResultMsg); !ok {
+		t.Fatal("expected the result to pass through")
+	}
+	want := "\a\x1b]777;notify;gust: build;failed (exit code 2) after 3m0s\a"
+	if out.String() != want {
+		t.Fatalf("terminal output = %q, want %q", out.String(), want)
+	}
+	if len(started) != 1 || !reflect.DeepEqual(started[0], []string{"notify-send", "gust: build", "failed (exit code 2) after 3m0s"}) {
+		t.Fatalf("started = %q", started)
+	}
+
+	// Flashes are not configured, updates use OSC 9.
+	out.Reset()
+	Drain(r.Run(context.Background(), "west", "flash"), nil)
+	if out.Len() != 0 {
+		t.Fatalf("expected no notification for flash, got %q", out.String())
+	}
+	Drain(r.Update(context.Background()), nil)
+	if want := "\x1b]9;gust: update: failed (exit code 2) after 3m0s\a"; out.String() != want {
+		t.Fatalf("update notification = %q, want %q", out.String(), want)
+	}
+}
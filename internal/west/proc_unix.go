@@ -0,0 +1,21 @@
+//go:build !windows
+
+package west
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts cmd in its own process group and makes context
+// cancellation kill the whole group, so children spawned by west (cmake,
+// ninja, debug probes) do not outlive it.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+// terminalPath is the controlling terminal, opened apart from stdout.
+const terminalPath = "/dev/tty"
@@ -0,0 +1,12 @@
+//go:build windows
+
+package west
+
+import "os/exec"
+
+// setProcessGroup is a no-op on Windows; cancellation falls back to killing
+// the direct child process.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// terminalPath is the console output, opened apart from stdout.
+const terminalPath = "CONOUT$"
@@ -0,0 +1,79 @@
+package west
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// BuildPhase is the stage a running build has reached.
+type BuildPhase string
+
+const (
+	PhaseStarting  BuildPhase = ""
+	PhaseConfigure BuildPhase = "Configuring"
+	PhaseGenerate  BuildPhase = "Generating"
+	PhaseCompile   BuildPhase = "Compiling"
+	PhaseLink      BuildPhase = "Linking"
+)
+
+// [123/456] Building C object zephyr/CMakeFiles/zephyr.dir/lib/os/printk.c.obj
+var ninjaStepRe = regexp.MustCompile(`^\[(\d+)/(\d+)\]\s*(.*)$`)
+
+// BuildProgress follows the phase and ninja step counts of a build from its
+// output, fed one line at a time. The zero value is ready to use.
+//
+// Sysbuild runs one ninja per image, each restarting at [1/N]; Done and
+// Total describe the image currently building and Images counts how many
+// ninja runs have been seen.
+type BuildProgress struct {
+	Phase  BuildPhase
+	Done   int
+	Total  int
+	Images int
+}
+
+// Feed parses one line of output.
+func (p *BuildProgress) Feed(line string) {
+	line = strings.TrimSpace(line)
+	if m := ninjaStepRe.FindStringSubmatch(line); m != nil {
+		done, _ := strconv.Atoi(m[1])
+		total, _ := strconv.Atoi(m[2])
+		if total == 0 {
+			return
+		}
+		if p.Total == 0 || done < p.Done {
+			p.Images++
+		}
+		p.Done, p.Total = done, total
+		p.Phase = PhaseCompile
+		if strings.HasPrefix(m[3], "Linking") {
+			p.Phase = PhaseLink
+		}
+		return
+	}
+	switch {
+	case strings.HasPrefix(line, "-- west build: generating a build system"),
+		strings.HasPrefix(line, "Loading Zephyr default modules"),
+		strings.HasPrefix(line, "-- Application:"):
+		p.Phase = PhaseConfigure
+	case strings.HasPrefix(line, "-- Configuring done"):
+		p.Phase = PhaseGenerate
+	case strings.HasPrefix(line, "-- Build files have been written to"):
+		// Generation is done; ninja output follows.
+		p.Phase = PhaseGenerate
+	}
+}
+
+// Fraction returns how far the current ninja run is, from 0 to 1.
+func (p *BuildProgress) Fraction() float64 {
+	if p.Total == 0 {
+		return 0
+	}
+	return float64(p.Done) / float64(p.Total)
+}
+
+// Reset clears the progress before a new build.
+func (p *BuildProgress) Reset() {
+	*p = BuildProgress{}
+}
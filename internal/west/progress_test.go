@@ -0,0 +1,43 @@
+package west
+
+import "testing"
+
+func TestBuildProgressFollowsPhasesAndSteps(t *testing.T) {
+	var p BuildProgress
+	lines := []string{
+		"-- west build: generating a build system",
+		"Loading Zephyr default modules (Zephyr base).",
+		"-- Configuring done (3.1s)",
+		"-- Generating done (0.2s)",
+		"-- Build files have been written to: /ws/build",
+	}
+	for _, l := range lines {
+		p.Feed(l)
+	}
+	if p.Phase != PhaseGenerate {
+		t.Fatalf("phase = %q, want %q", p.Phase, PhaseGenerate)
+	}
+
+	p.Feed("[12/48] Building C object zephyr/CMakeFiles/zephyr.dir/lib/os/printk.c.obj")
+	if p.Phase != PhaseCompile || p.Done != 12 || p.Total != 48 || p.Images != 1 {
+		t.Fatalf("after step: %+v", p)
+	}
+	if got := p.Fraction(); got != 0.25 {
+		t.Fatalf("Fraction = %v, want 0.25", got)
+	}
+	p.Feed("[48/48] Linking C executable zephyr/zephyr.elf")
+	if p.Phase != PhaseLink {
+		t.Fatalf("phase = %q, want %q", p.Phase, PhaseLink)
+	}
+
+	// A sysbuild image restarts the step count.
+	p.Feed("[1/200] Generating include/generated/version.h")
+	if p.Images != 2 || p.Done != 1 || p.Total != 200 || p.Phase != PhaseCompile {
+		t.Fatalf("after second image: %+v", p)
+	}
+
+	p.Reset()
+	if p.Phase != PhaseStarting || p.Total != 0 || p.Fraction() != 0 {
+		t.Fatalf("after Reset: %+v", p)
+	}
+}
@@ -0,0 +1,259 @@
+package west
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Provenance records what a build was made with beyond the application
+// sources: the Zephyr tree, the toolchain, the host tools and the revision
+// of every west project.
+type Provenance struct {
+	ZephyrVersion string
+	Toolchain     string // ZEPHYR_TOOLCHAIN_VARIANT, e.g. zephyr or gnuarmemb
+	SDKVersion    string
+	CMakeVersion  string
+	NinjaVersion  string
+	// Manifest maps each west project to its frozen revision.
+	Manifest map[string]string
+}
+
+// ReadProvenance collects the provenance recorded in the build in buildDir,
+// resolved against wsRoot, without running any tools. For a build
+// configured in a container with the workspace at mount, paths in its
+// cache are mapped back to wsRoot; pass "" otherwise. Anything that cannot
+// be determined is left empty.
+func ReadProvenance(wsRoot, buildDir, mount string) Provenance {
+	p, _ := readProvenance(wsRoot, buildDir, mount)
+	return p
+}
+
+// provenanceTools are what CollectProvenance runs to complete a
+// provenance read from a build directory.
+type provenanceTools struct {
+	ninja  string
+	sdkDir string // read through the runner when not on the host
+}
+
+func readProvenance(wsRoot, buildDir, mount string) (Provenance, provenanceTools) {
+	entries, _ := ReadCMakeCache(CMakeCachePath(wsRoot, buildDir))
+	// A sysbuild cache does not describe the toolchain; the default
+	// image's cache does.
+	if d, err := ReadDomains(wsRoot, buildDir); err == nil && d.Default != "" {
+		image, _ := ReadCMakeCache(filepath.Join(BuildDirPath(wsRoot, buildDir), d.Default, "CMakeCache.txt"))
+		entries = append(image, entries...)
+	}
+
+	var p Provenance
+	var tools provenanceTools
+	zephyrBase := cacheValue(entries, "ZEPHYR_BASE")
+	if zephyrBase == "" {
+		zephyrBase = filepath.Join(wsRoot, "zephyr")
+	} else if mount != "" {
+		zephyrBase = translatePath(zephyrBase, mount, wsRoot)
+	}
+	p.ZephyrVersion = ReadZephyrVersion(filepath.Join(zephyrBase, "VERSION"))
+	p.Toolchain = cacheValue(entries, "ZEPHYR_TOOLCHAIN_VARIANT")
+
+	sdkDir := cacheValue(entries, "ZEPHYR_SDK_INSTALL_DIR")
+	if sdkDir == "" {
+		// find_package(Zephyr-sdk) records <sdk>/cmake.
+		if d := cacheValue(entries, "Zephyr-sdk_DIR"); d != "" {
+			sdkDir = filepath.Dir(d)
+		}
+	}
+	if sdkDir != "" {
+		if data, err := os.ReadFile(filepath.Join(sdkDir, "sdk_version")); err == nil {
+			p.SDKVersion = strings.TrimSpace(string(data))
+		} else {
+			tools.sdkDir = sdkDir
+		}
+	}
+
+	if major := cacheValue(entries, "CMAKE_CACHE_MAJOR_VERSION"); major != "" {
+		p.CMakeVersion = major + "." + cacheValue(entries, "CMAKE_CACHE_MINOR_VERSION") + "." + cacheValue(entries, "CMAKE_CACHE_PATCH_VERSION")
+	}
+	tools.ninja = cacheValue(entries, "CMAKE_MAKE_PROGRAM")
+	if tools.ninja == "" {
+		tools.ninja = "ninja"
+	}
+	return p, tools
+}
+
+// CollectProvenance completes ReadProvenance with what only the build's
+// tools can tell: the ninja version, the SDK version when the SDK is not on
+// the host, and the frozen west manifest. The tools run through runner, so
+// a container build queries the container, but as Internal commands that
+// stay off the jobs list. It blocks until they finish; run it from a
+// tea.Cmd.
+func CollectProvenance(ctx context.Context, runner Runner, wsRoot, buildDir, mount string) Provenance {
+	p, tools := readProvenance(wsRoot, buildDir, mount)
+	ctx = Internal(ctx)
+	if out, ok := runnerOutput(ctx, runner, tools.ninja, "--version"); ok {
+		p.NinjaVersion = strings.TrimSpace(out)
+	}
+	if tools.sdkDir != "" {
+		if out, ok := runnerOutput(ctx, runner, "cat", path.Join(tools.sdkDir, "sdk_version")); ok {
+			p.SDKVersion = strings.TrimSpace(out)
+		}
+	}
+	if out, ok := runnerOutput(ctx, runner, "west", "manifest", "--freeze"); ok {
+		p.Manifest = ParseFrozenManifest(out)
+	}
+	return p
+}
+
+// runnerOutput runs a command through runner and returns its output if it
+// succeeded.
+func runnerOutput(ctx context.Context, runner Runner, name string, args ...string) (string, bool) {
+	result, ok := Drain(runner.Run(ctx, name, args...), nil).(CommandResultMsg)
+	if !ok || result.ExitCode != 0 || result.Cancelled {
+		return "", false
+	}
+	return result.Output, true
+}
+
+// ReadZephyrVersion reads a zephyr/VERSION file into e.g. "3.7.0" or
+// "4.0.0-rc1". It returns "" if the file cannot be read.
+func ReadZephyrVersion(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	fields := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if ok {
+			fields[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	if fields["VERSION_MAJOR"] == "" {
+		return ""
+	}
+	v := fields["VERSION_MAJOR"] + "." + fields["VERSION_MINOR"] + "." + fields["PATCHLEVEL"]
+	if extra := fields["EXTRAVERSION"]; extra != "" {
+		v += "-" + extra
+	}
+	return v
+}
+
+// ParseFrozenManifest extracts project revisions from the output of
+// `west manifest --freeze`:
+//
+//	manifest:
+//	  projects:
+//	  - name: hal_nordic
+//	    revision: 5c8d109371ebb740fbef1f440a3b59e488a36717
+//	    path: modules/hal/nordic
+func ParseFrozenManifest(out string) map[string]string {
+	revisions := map[string]string{}
+	var name string
+	projectsIndent := -1 // indentation of "projects:", -1 outside the list
+	itemIndent := -1     // indentation of the "- name:" lines
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if trimmed == "projects:" {
+			projectsIndent, itemIndent = indent, -1
+			continue
+		}
+		// A key at or above the list's level ends it; list items may sit at
+		// the same indentation as "projects:".
+		if projectsIndent >= 0 && indent <= projectsIndent && !strings.HasPrefix(trimmed, "- ") {
+			projectsIndent = -1
+		}
+		if projectsIndent < 0 {
+			continue
+		}
+		item, isItem := strings.CutPrefix(trimmed, "- ")
+		if isItem && itemIndent < 0 {
+			itemIndent = indent
+		}
+		switch {
+		case isItem && indent == itemIndent:
+			name = ""
+		case indent != itemIndent+2:
+			continue // nested under a project, e.g. its groups
+		}
+		key, value, ok := strings.Cut(item, ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "name":
+			name = yamlScalar(value)
+		case "revision":
+			if name != "" {
+				revisions[name] = yamlScalar(value)
+			}
+		}
+	}
+	if len(revisions) == 0 {
+		return nil
+	}
+	return revisions
+}
+
+// DiffProvenance describes what changed from prev to cur, one line per
+// change. Fields unknown in either build are not compared.
+func DiffProvenance(prev, cur Provenance) []string {
+	var changes []string
+	field := func(label, a, b string) {
+		if a != "" && b != "" && a != b {
+			changes = append(changes, fmt.Sprintf("%s %s → %s", label, a, b))
+		}
+	}
+	field("Zephyr", prev.ZephyrVersion, cur.ZephyrVersion)
+	field("Toolchain", prev.Toolchain, cur.Toolchain)
+	field("SDK", prev.SDKVersion, cur.SDKVersion)
+	field("CMake", prev.CMakeVersion, cur.CMakeVersion)
+	field("Ninja", prev.NinjaVersion, cur.NinjaVersion)
+
+	if prev.Manifest == nil || cur.Manifest == nil {
+		return changes
+	}
+	names := make([]string, 0, len(cur.Manifest))
+	for name := range cur.Manifest {
+		names = append(names, name)
+	}
+	for name := range prev.Manifest {
+		if _, ok := cur.Manifest[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		a, inPrev := prev.Manifest[name]
+		b, inCur := cur.Manifest[name]
+		switch {
+		case !inPrev:
+			changes = append(changes, fmt.Sprintf("%s added at %s", name, shortRevision(b)))
+		case !inCur:
+			changes = append(changes, fmt.Sprintf("%s removed", name))
+		case a != b:
+			changes = append(changes, fmt.Sprintf("%s %s → %s", name, shortRevision(a), shortRevision(b)))
+		}
+	}
+	return changes
+}
+
+func shortRevision(rev string) string {
+	if len(rev) > 12 {
+		return rev[:12]
+	}
+	return rev
+}
@@ -0,0 +1,151 @@
+package west
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadZephyrVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "VERSION")
+	data := "VERSION_MAJOR = 4\nVERSION_MINOR = 0\nPATCHLEVEL = 0\nVERSION_TWEAK = 0\nEXTRAVERSION = rc1\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := ReadZephyrVersion(path); got != "4.0.0-rc1" {
+		t.Fatalf("ReadZephyrVersion = %q, want 4.0.0-rc1", got)
+	}
+	if got := ReadZephyrVersion(filepath.Join(t.TempDir(), "missing")); got != "" {
+		t.Fatalf("ReadZephyrVersion(missing) = %q, want empty", got)
+	}
+}
+
+func TestReadProvenanceFromCache(t *testing.T) {
+	wsRoot := t.TempDir()
+	zephyrBase := filepath.Join(wsRoot, "zephyr")
+	sdk := filepath.Join(wsRoot, "sdk")
+	for _, dir := range []string{zephyrBase, sdk, filepath.Join(wsRoot, "build")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		filepath.Join(zephyrBase, "VERSION"): "VERSION_MAJOR = 3\nVERSION_MINOR = 7\nPATCHLEVEL = 1\nEXTRAVERSION =\n",
+		filepath.Join(sdk, "sdk_version"):    "0.16.8\n",
+		filepath.Join(wsRoot, "build", "CMakeCache.txt"): "ZEPHYR_BASE:PATH=" + zephyrBase + "\n" +
+			"ZEPHYR_TOOLCHAIN_VARIANT:STRING=zephyr\n" +
+			"ZEPHYR_SDK_INSTALL_DIR:PATH=" + sdk + "\n" +
+			"CMAKE_CACHE_MAJOR_VERSION:INTERNAL=3\n" +
+			"CMAKE_CACHE_MINOR_VERSION:INTERNAL=28\n" +
+			"CMAKE_CACHE_PATCH_VERSION:INTERNAL=1\n" +
+			"CMAKE_MAKE_PROGRAM:FILEPATH=" + filepath.Join(wsRoot, "no-such-ninja") + "\n",
+	}
+	for path, data := range files {
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := ReadProvenance(wsRoot, "build", "")
+	if p.ZephyrVersion != "3.7.1" || p.Toolchain != "zephyr" || p.SDKVersion != "0.16.8" || p.CMakeVersion != "3.28.1" {
+		t.Fatalf("unexpected provenance: %+v", p)
+	}
+	if p.NinjaVersion != "" {
+		t.Fatalf("expected no tools to be run, got ninja %q", p.NinjaVersion)
+	}
+}
+
+func TestCollectProvenanceQueriesToolsThroughRunner(t *testing.T) {
+	// A build configured in a container: its cache names container paths,
+	// and the SDK only exists inside the image.
+	wsRoot := t.TempDir()
+	for path, data := range map[string]string{
+		filepath.Join(wsRoot, "zephyr", "VERSION"): "VERSION_MAJOR = 3\nVERSION_MINOR = 7\nPATCHLEVEL = 0\n",
+		filepath.Join(wsRoot, "build", "CMakeCache.txt"): "ZEPHYR_BASE:PATH=/workdir/zephyr\n" +
+			"ZEPHYR_SDK_INSTALL_DIR:PATH=/opt/zephyr-sdk\n" +
+			"CMAKE_MAKE_PROGRAM:FILEPATH=/usr/bin/ninja\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runner := NewReplayRunner(Transcript{Commands: []TranscriptEntry{
+		{Method: "run", Name: "/usr/bin/ninja", Args: []string{"--version"}, Output: []string{"1.11.1"}},
+		{Method: "run", Name: "cat", Args: []string{"/opt/zephyr-sdk/sdk_version"}, Output: []string{"0.16.8"}},
+		{Method: "run", Name: "west", Args: []string{"manifest", "--freeze"}, Output: []string{
+			"manifest:", "  projects:", "  - name: cmsis", "    revision: abc",
+		}},
+	}}, wsRoot)
+
+	p := CollectProvenance(context.Background(), runner, wsRoot, "build", "/workdir")
+	want := Provenance{ZephyrVersion: "3.7.0", SDKVersion: "0.16.8", NinjaVersion: "1.11.1", Manifest: map[string]string{"cmsis": "abc"}}
+	if !reflect.DeepEqual(p, want) {
+		t.Fatalf("CollectProvenance = %+v, want %+v", p, want)
+	}
+}
+
+func TestParseFrozenManifest(t *testing.T) {
+	out := `manifest:
+  defaults:
+    remote: upstream
+  remotes:
+  - name: upstream
+    url-base: https://github.com/zephyrproject-rtos
+  projects:
+  - name: cmsis
+    revision: 4b96cbb174678dcd3ca86e11e1f24bc5f8726da0
+    path: modules/hal/cmsis
+    groups:
+    - hal
+  - name: hal_nordic
+    groups:
+    - hal
+    revision: "5c8d109371ebb740fbef1f440a3b59e488a36717"
+    path: modules/hal/nordic
+  self:
+    path: zephyr
+`
+	got := ParseFrozenManifest(out)
+	want := map[string]string{
+		"cmsis":      "4b96cbb174678dcd3ca86e11e1f24bc5f8726da0",
+		"hal_nordic": "5c8d109371ebb740fbef1f440a3b59e488a36717",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseFrozenManifest = %v, want %v", got, want)
+	}
+	if got := ParseFrozenManifest("manifest:\n  self:\n    path: app\n"); got != nil {
+		t.Fatalf("expected nil for a manifest without projects, got %v", got)
+	}
+}
+
+func TestDiffProvenance(t *testing.T) {
+	prev := Provenance{
+		ZephyrVersion: "3.6.0",
+		SDKVersion:    "0.16.5",
+		CMakeVersion:  "3.28.1",
+		Manifest:      map[string]string{"cmsis": "aaaaaaaaaaaaaaaa", "hal_nordic": "1111", "old": "9"},
+	}
+	cur := Provenance{
+		ZephyrVersion: "3.7.0",
+		SDKVersion:    "0.16.5",
+		Manifest:      map[string]string{"cmsis": "bbbbbbbbbbbbbbbb", "hal_nordic": "1111", "new": "7"},
+	}
+	got := DiffProvenance(prev, cur)
+	want := []string{
+		"Zephyr 3.6.0 → 3.7.0",
+		"cmsis aaaaaaaaaaaa → bbbbbbbbbbbb",
+		"new added at 7",
+		"old removed",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("DiffProvenance = %q, want %q", got, want)
+	}
+	if got := DiffProvenance(prev, prev); len(got) != 0 {
+		t.Fatalf("expected no changes for identical provenance, got %q", got)
+	}
+}
@@ -1,7 +1,7 @@
 package west
 
 import (
-	"bufio"
+	"context"
 	"fmt"
 	"os/exec"
 	"time"
@@ -10,32 +10,36 @@ import (
 )
 
 // Runner abstracts west command execution so pages can inject test fakes.
+// Cancelling ctx kills the command's whole process group; the final
+// CommandResultMsg then has Cancelled set.
 type Runner interface {
-	Run(name string, args ...string) tea.Cmd
-	Status() tea.Cmd
-	List() tea.Cmd
-	Diff() tea.Cmd
-	Update() tea.Cmd
-	Init() tea.Cmd
-	ZephyrExport() tea.Cmd
-	PackagesPipInstall() tea.Cmd
-	SdkInstall() tea.Cmd
-	InstallBrewDeps() tea.Cmd
+	Run(ctx context.Context, name string, args ...string) tea.Cmd
+	Status(ctx context.Context) tea.Cmd
+	List(ctx context.Context) tea.Cmd
+	Diff(ctx context.Context) tea.Cmd
+	Update(ctx context.Context) tea.Cmd
+	Init(ctx context.Context) tea.Cmd
+	ZephyrExport(ctx context.Context) tea.Cmd
+	PackagesPipInstall(ctx context.Context) tea.Cmd
+	SdkInstall(ctx context.Context) tea.Cmd
+	InstallBrewDeps(ctx context.Context) tea.Cmd
 }
 
 // DefaultRunner executes real west commands.
 type DefaultRunner struct{}
 
-func (DefaultRunner) Run(name string, args ...string) tea.Cmd { return RunStreaming(name, args...) }
-func (DefaultRunner) Status() tea.Cmd                         { return Status() }
-func (DefaultRunner) List() tea.Cmd                           { return List() }
-func (DefaultRunner) Diff() tea.Cmd                           { return Diff() }
-func (DefaultRunner) Update() tea.Cmd                         { return Update() }
-func (DefaultRunner) Init() tea.Cmd                           { return Init() }
-func (DefaultRunner) ZephyrExport() tea.Cmd                   { return ZephyrExport() }
-func (DefaultRunner) PackagesPipInstall() tea.Cmd             { return PackagesPipInstall() }
-func (DefaultRunner) SdkInstall() tea.Cmd                     { return SdkInstall() }
-func (DefaultRunner) InstallBrewDeps() tea.Cmd                { return InstallBrewDeps() }
+func (DefaultRunner) Run(ctx context.Context, name string, args ...string) tea.Cmd {
+	return RunStreaming(ctx, name, args...)
+}
+func (DefaultRunner) Status(ctx context.Context) tea.Cmd             { return Status(ctx) }
+func (DefaultRunner) List(ctx context.Context) tea.Cmd               { return List(ctx) }
+func (DefaultRunner) Diff(ctx context.Context) tea.Cmd               { return Diff(ctx) }
+func (DefaultRunner) Update(ctx context.Context) tea.Cmd             { return Update(ctx) }
+func (DefaultRunner) Init(ctx context.Context) tea.Cmd               { return Init(ctx) }
+func (DefaultRunner) ZephyrExport(ctx context.Context) tea.Cmd       { return ZephyrExport(ctx) }
+func (DefaultRunner) PackagesPipInstall(ctx context.Context) tea.Cmd { return PackagesPipInstall(ctx) }
+func (DefaultRunner) SdkInstall(ctx context.Context) tea.Cmd         { return SdkInstall(ctx) }
+func (DefaultRunner) InstallBrewDeps(ctx context.Context) tea.Cmd    { return InstallBrewDeps(ctx) }
 
 var realRunner Runner = DefaultRunner{}
 
@@ -45,112 +49,91 @@ func RealRunner() Runner {
 }
 
 // CommandOutputMsg is sent for each line of output from a running command.
+// Stdout and stderr share one pipe, so lines arrive in the order the process
+// wrote them. The page that owns RequestID must return Next to keep receiving
+// output; the final message in the chain is a CommandResultMsg.
 type CommandOutputMsg struct {
-	Line string
-}
-
-// CommandCompletedMsg is sent when a command finishes.
-type CommandCompletedMsg struct {
-	ExitCode int
-	Duration time.Duration
-	Err      error
+	RequestID string
+	Line      string
+	Next      tea.Cmd
 }
 
-// RunStreaming executes a command and returns all output when complete.
-// Note: Despite the name, this currently batches output for simplicity.
-// True line-by-line streaming would require subscription-based architecture.
-func RunStreaming(name string, args ...string) tea.Cmd {
+// RunStreaming starts a command and returns a tea.Cmd that yields a
+// CommandOutputMsg per line of output, followed by a CommandResultMsg when the
+// process exits or ctx is cancelled.
+func RunStreaming(ctx context.Context, name string, args ...string) tea.Cmd {
 	return func() tea.Msg {
-		start := time.Now()
-		cmd := exec.Command(name, args...)
+		cmd := exec.CommandContext(ctx, name, args...)
 		applyEnv(cmd)
-
-		output, err := cmd.CombinedOutput()
-		duration := time.Since(start)
-
-		exitCode := 0
-		if err != nil {
-			if exitErr, ok := err.(*exec.ExitError); ok {
-				exitCode = exitErr.ExitCode()
-			} else {
-				return CommandResultMsg{
-					Output:   string(output),
-					ExitCode: -1,
-					Duration: duration,
-				}
-			}
-		}
-
-		return CommandResultMsg{
-			Output:   string(output),
-			ExitCode: exitCode,
-			Duration: duration,
-		}
+		setProcessGroup(cmd)
+		return startStream(ctx, cmd).next()
 	}
 }
 
-// CommandResultMsg bundles all output from a non-streaming command.
+// CommandResultMsg bundles all output from a command. Streamed is true when the
+// output was already delivered line by line through CommandOutputMsg.
+// Cancelled is true when the command was stopped through its context.
 type CommandResultMsg struct {
 	RequestID string
 	Output    string
 	ExitCode  int
 	Duration  time.Duration
+	Streamed  bool
+	Cancelled bool
+}
+
+type internalKey struct{}
+
+// Internal marks the commands run with the returned context as gust's own
+// probes: runners from TrackJobs and NotifyRunner pass them through without
+// listing them as jobs or announcing them.
+func Internal(ctx context.Context) context.Context {
+	return context.WithValue(ctx, internalKey{}, true)
+}
+
+func isInternal(ctx context.Context) bool {
+	internal, _ := ctx.Value(internalKey{}).(bool)
+	return internal
 }
 
 // WithRequestID tags any west command result with a request ID so callers can
-// correlate responses and ignore unrelated completions.
+// correlate responses and ignore unrelated completions. Streamed output lines
+// and their continuations are tagged as well.
 func WithRequestID(requestID string, cmd tea.Cmd) tea.Cmd {
 	if cmd == nil {
 		return nil
 	}
 	return func() tea.Msg {
-		msg := cmd()
-		result, ok := msg.(CommandResultMsg)
-		if !ok {
+		switch msg := cmd().(type) {
+		case CommandOutputMsg:
+			msg.RequestID = requestID
+			msg.Next = WithRequestID(requestID, msg.Next)
+			return msg
+		case CommandResultMsg:
+			msg.RequestID = requestID
+			return msg
+		default:
 			return msg
 		}
-		result.RequestID = requestID
-		return result
 	}
 }
 
-// StreamCommand starts a command and sends output to a channel for real-time display.
-// The caller should use tea.Program.Send() in a goroutine to forward messages.
-func StreamCommand(p *tea.Program, name string, args ...string) {
-	start := time.Now()
-	cmd := exec.Command(name, args...)
-	applyEnv(cmd)
-
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		p.Send(CommandCompletedMsg{ExitCode: -1, Err: err})
-		return
-	}
-	cmd.Stderr = cmd.Stdout // merge stderr into stdout
-
-	if err := cmd.Start(); err != nil {
-		p.Send(CommandCompletedMsg{ExitCode: -1, Err: err})
-		return
-	}
-
-	scanner := bufio.NewScanner(stdout)
-	for scanner.Scan() {
-		p.Send(CommandOutputMsg{Line: scanner.Text()})
-	}
-
-	exitCode := 0
-	err = cmd.Wait()
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			exitCode = exitErr.ExitCode()
+// Drain runs cmd to completion outside of a Bubble Tea program, following
+// CommandOutputMsg continuations. onLine, when non-nil, is called for every
+// streamed line. It returns the first message that is not streamed output.
+func Drain(cmd tea.Cmd, onLine func(string)) tea.Msg {
+	for cmd != nil {
+		msg := cmd()
+		out, ok := msg.(CommandOutputMsg)
+		if !ok {
+			return msg
 		}
+		if onLine != nil {
+			onLine(out.Line)
+		}
+		cmd = out.Next
 	}
-
-	p.Send(CommandCompletedMsg{
-		ExitCode: exitCode,
-		Duration: time.Since(start),
-		Err:      err,
-	})
+	return nil
 }
 
 // RunSimple executes a command and returns the output as a single string.
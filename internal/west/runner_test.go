@@ -1,7 +1,10 @@
 package west
 
 import (
+	"context"
+	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -45,3 +48,115 @@ func TestWithRequestIDPassesThroughNonResult(t *testing.T) {
 		t.Fatalf("expected passthrough payload, got %q", got.Name)
 	}
 }
+
+func TestRunStreamingEmitsLinesInOrder(t *testing.T) {
+	cmd := WithRequestID("req-1", RunStreaming(context.Background(), "sh", "-c", "echo one; echo two >&2; echo three; exit 3"))
+
+	var lines []string
+	msg := cmd()
+	for {
+		out, ok := msg.(CommandOutputMsg)
+		if !ok {
+			break
+		}
+		if out.RequestID != "req-1" {
+			t.Fatalf("expected output tagged req-1, got %q", out.RequestID)
+		}
+		lines = append(lines, out.Line)
+		msg = out.Next()
+	}
+
+	result, ok := msg.(CommandResultMsg)
+	if !ok {
+		t.Fatalf("expected CommandResultMsg, got %T", msg)
+	}
+	if got := strings.Join(lines, ","); got != "one,two,three" {
+		t.Fatalf("expected lines one,two,three in order, got %q", got)
+	}
+	if result.RequestID != "req-1" {
+		t.Fatalf("expected result tagged req-1, got %q", result.RequestID)
+	}
+	if result.ExitCode != 3 {
+		t.Fatalf("expected exit code 3, got %d", result.ExitCode)
+	}
+	if !result.Streamed {
+		t.Fatal("expected Streamed result")
+	}
+	if result.Output != "one\ntwo\nthree\n" {
+		t.Fatalf("expected full output in result, got %q", result.Output)
+	}
+}
+
+func TestRunStreamingReportsStartFailure(t *testing.T) {
+	msg := Drain(RunStreaming(context.Background(), "gust-definitely-not-a-command"), nil)
+	result, ok := msg.(CommandResultMsg)
+	if !ok {
+		t.Fatalf("expected CommandResultMsg, got %T", msg)
+	}
+	if result.ExitCode != -1 {
+		t.Fatalf("expected exit code -1, got %d", result.ExitCode)
+	}
+	if result.Streamed || result.Output == "" {
+		t.Fatalf("expected unstreamed error output, got %+v", result)
+	}
+}
+
+func TestDrainCollectsLines(t *testing.T) {
+	var lines []string
+	msg := Drain(RunStreaming(context.Background(), "sh", "-c", "printf 'a\\nb\\n'"), func(line string) {
+		lines = append(lines, line)
+	})
+	if _, ok := msg.(CommandResultMsg); !ok {
+		t.Fatalf("expected CommandResultMsg, got %T", msg)
+	}
+	if len(lines) != 2 || lines[0] != "a" || lines[1] != "b" {
+		t.Fatalf("expected [a b], got %v", lines)
+	}
+}
+
+func TestRunStreamingSplitsOverlongLines(t *testing.T) {
+	// A line over maxLineLength used to stop the reader, leaving the
+	// process blocked on a full pipe and the rest of its output lost.
+	script := "head -c 2500000 /dev/zero | tr '\\0' x; printf '\\r\\ndone\\n'"
+	var lines []string
+	msg := Drain(RunStreaming(context.Background(), "sh", "-c", script), func(line string) {
+		lines = append(lines, line)
+	})
+	result, ok := msg.(CommandResultMsg)
+	if !ok || result.ExitCode != 0 {
+		t.Fatalf("expected a successful result, got %+v", msg)
+	}
+	if len(lines) != 4 || len(lines[0]) != maxLineLength || len(lines[1]) != maxLineLength ||
+		len(lines[2]) != 2500000-2*maxLineLength || lines[3] != "done" {
+		t.Fatalf("expected the long line split in three and then done, got %d lines", len(lines))
+	}
+}
+
+func TestRunStreamingCancelKillsProcessGroup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	// The backgrounded sleep inherits the pipe; the result only arrives once
+	// every process in the group has exited.
+	cmd := RunStreaming(ctx, "sh", "-c", "sleep 30 & echo started; sleep 30")
+
+	msg := cmd()
+	out, ok := msg.(CommandOutputMsg)
+	if !ok || out.Line != "started" {
+		t.Fatalf("expected started line, got %#v", msg)
+	}
+	cancel()
+
+	done := make(chan tea.Msg, 1)
+	go func() { done <- Drain(out.Next, nil) }()
+	select {
+	case msg = <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("cancelled command did not finish")
+	}
+	result, ok := msg.(CommandResultMsg)
+	if !ok {
+		t.Fatalf("expected CommandResultMsg, got %T", msg)
+	}
+	if !result.Cancelled {
+		t.Fatalf("expected Cancelled result, got %+v", result)
+	}
+}
@@ -0,0 +1,179 @@
+package west
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxLineLength bounds a single output line; longer lines are split.
+const maxLineLength = 1 << 20
+
+// outputStream queues output lines from a running process until the page
+// that owns the request asks for them. The queue is unbounded so the process
+// never blocks on a page that stopped listening.
+type outputStream struct {
+	mu    sync.Mutex
+	cond  *sync.Cond
+	lines []string
+	done  bool
+	final tea.Msg
+}
+
+func newOutputStream() *outputStream {
+	s := &outputStream{}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// startStream starts cmd with stdout and stderr merged into a single pipe and
+// begins collecting its output in the background. ctx must be the context cmd
+// was created with; it is used to report cancellation.
+func startStream(ctx context.Context, cmd *exec.Cmd) *outputStream {
+	s := newOutputStream()
+	start := time.Now()
+
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		s.finish(CommandResultMsg{Output: fmt.Sprintf("Error: %v\n", err), ExitCode: -1})
+		return s
+	}
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	if err := cmd.Start(); err != nil {
+		pw.Close()
+		pr.Close()
+		s.finish(CommandResultMsg{Output: fmt.Sprintf("Error: %v\n", err), ExitCode: -1, Duration: time.Since(start)})
+		return s
+	}
+	// The child holds its own copy of the write end; closing ours lets the
+	// reader see EOF once the process exits.
+	pw.Close()
+
+	go func() {
+		defer pr.Close()
+		var all strings.Builder
+		emit := func(line string) {
+			all.WriteString(line + "\n")
+			s.push(line)
+		}
+		if err := readLines(pr, emit); err != nil {
+			emit(fmt.Sprintf("Error reading output: %v", err))
+			// Keep draining so the process is never blocked on a full pipe.
+			_, _ = io.Copy(io.Discard, pr)
+		}
+
+		exitCode := 0
+		if err := cmd.Wait(); err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				exitCode = exitErr.ExitCode()
+			} else {
+				exitCode = -1
+			}
+		}
+		s.finish(CommandResultMsg{
+			Output:    all.String(),
+			ExitCode:  exitCode,
+			Duration:  time.Since(start),
+			Streamed:  true,
+			Cancelled: ctx.Err() != nil,
+		})
+	}()
+	return s
+}
+
+// readLines calls emit for every line read from r, without its line
+// ending. Lines longer than maxLineLength are split. A read error other than
+// EOF is returned after the lines read before it.
+func readLines(r io.Reader, emit func(string)) error {
+	br := bufio.NewReaderSize(r, 64*1024)
+	var line []byte
+	for {
+		chunk, err := br.ReadSlice('\n')
+		line = append(line, chunk...)
+		for len(line) > maxLineLength {
+			emit(string(line[:maxLineLength]))
+			line = append(line[:0], line[maxLineLength:]...)
+		}
+		switch err {
+		case bufio.ErrBufferFull:
+			continue
+		case nil:
+			emit(trimLineEnding(line))
+			line = line[:0]
+			continue
+		}
+		if len(line) > 0 {
+			emit(trimLineEnding(line))
+		}
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+}
+
+func trimLineEnding(line []byte) string {
+	s := string(line)
+	s = strings.TrimSuffix(s, "\n")
+	return strings.TrimSuffix(s, "\r")
+}
+
+func (s *outputStream) push(line string) {
+	s.mu.Lock()
+	s.lines = append(s.lines, line)
+	s.mu.Unlock()
+	s.cond.Signal()
+}
+
+// finish records the message that ends the stream, normally a
+// CommandResultMsg.
+func (s *outputStream) finish(final tea.Msg) {
+	s.mu.Lock()
+	s.done = true
+	s.final = final
+	s.mu.Unlock()
+	s.cond.Broadcast()
+}
+
+// next blocks until another line or the final result is available.
+func (s *outputStream) next() tea.Msg {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.lines) == 0 && !s.done {
+		s.cond.Wait()
+	}
+	if len(s.lines) > 0 {
+		line := s.lines[0]
+		s.lines = s.lines[1:]
+		return CommandOutputMsg{Line: line, Next: s.next}
+	}
+	return s.final
+}
+
+// observe runs cmd to completion in the background, calling onLine for every
+// streamed line and onDone with the final message, while replaying the same
+// messages to whoever follows the returned command. The command finishes even
+// if nobody follows it to the end.
+func observe(cmd tea.Cmd, onLine func(string), onDone func(tea.Msg)) tea.Cmd {
+	return func() tea.Msg {
+		s := newOutputStream()
+		go func() {
+			final := Drain(cmd, func(line string) {
+				onLine(line)
+				s.push(line)
+			})
+			onDone(final)
+			s.finish(final)
+		}()
+		return s.next()
+	}
+}
@@ -0,0 +1,284 @@
+package west
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// workspacePlaceholder stands in for the workspace root inside transcripts so
+// a session recorded in one checkout replays in any other.
+const workspacePlaceholder = "$WORKSPACE"
+
+// TranscriptEntry is one recorded command. Method is the Runner method that
+// started it ("run", "status", "update", ...); Name and Args are only set for
+// "run".
+type TranscriptEntry struct {
+	Method     string   `json:"method"`
+	Name       string   `json:"name,omitempty"`
+	Args       []string `json:"args,omitempty"`
+	Output     []string `json:"output"`
+	ExitCode   int      `json:"exit_code"`
+	DurationMS int64    `json:"duration_ms"`
+}
+
+// Transcript is an ordered list of recorded commands.
+type Transcript struct {
+	Commands []TranscriptEntry `json:"commands"`
+}
+
+// LoadTranscript reads a transcript written by a RecordingRunner.
+func LoadTranscript(path string) (Transcript, error) {
+	var t Transcript
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return t, err
+	}
+	if err := json.Unmarshal(data, &t); err != nil {
+		return t, fmt.Errorf("invalid transcript %s: %w", path, err)
+	}
+	return t, nil
+}
+
+// RecordingRunner wraps another Runner and appends every completed command to
+// a transcript file, with the workspace root replaced by a placeholder.
+type RecordingRunner struct {
+	inner  Runner
+	path   string
+	wsRoot string
+	mu     sync.Mutex
+}
+
+// NewRecordingRunner records commands run through inner to the transcript at
+// path. Existing entries in the file are kept.
+func NewRecordingRunner(inner Runner, path, wsRoot string) *RecordingRunner {
+	return &RecordingRunner{inner: inner, path: path, wsRoot: wsRoot}
+}
+
+func (r *RecordingRunner) record(entry TranscriptEntry, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	var lines []string
+	return observe(cmd,
+		func(line string) { lines = append(lines, line) },
+		func(final tea.Msg) {
+			if result, ok := final.(CommandResultMsg); ok {
+				if !result.Streamed {
+					lines = splitOutput(result.Output)
+				}
+				entry.ExitCode = result.ExitCode
+				entry.DurationMS = result.Duration.Milliseconds()
+			} else {
+				entry.ExitCode = -1
+			}
+			entry.Output = make([]string, len(lines))
+			for i, l := range lines {
+				entry.Output[i] = toPlaceholder(l, r.wsRoot)
+			}
+			if err := r.append(entry); err != nil {
+				fmt.Fprintf(os.Stderr, "gust: recording transcript failed: %v\n", err)
+			}
+		},
+	)
+}
+
+func (r *RecordingRunner) append(entry TranscriptEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, err := LoadTranscript(r.path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	t.Commands = append(t.Commands, entry)
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0o644)
+}
+
+func (r *RecordingRunner) Run(ctx context.Context, name string, args ...string) tea.Cmd {
+	entry := TranscriptEntry{Method: "run", Name: name, Args: make([]string, len(args))}
+	for i, a := range args {
+		entry.Args[i] = toPlaceholder(a, r.wsRoot)
+	}
+	return r.record(entry, r.inner.Run(ctx, name, args...))
+}
+
+func (r *RecordingRunner) Status(ctx context.Context) tea.Cmd {
+	return r.record(TranscriptEntry{Method: "status"}, r.inner.Status(ctx))
+}
+
+func (r *RecordingRunner) List(ctx context.Context) tea.Cmd {
+	return r.record(TranscriptEntry{Method: "list"}, r.inner.List(ctx))
+}
+
+func (r *RecordingRunner) Diff(ctx context.Context) tea.Cmd {
+	return r.record(TranscriptEntry{Method: "diff"}, r.inner.Diff(ctx))
+}
+
+func (r *RecordingRunner) Update(ctx context.Context) tea.Cmd {
+	return r.record(TranscriptEntry{Method: "update"}, r.inner.Update(ctx))
+}
+
+func (r *RecordingRunner) Init(ctx context.Context) tea.Cmd {
+	return r.record(TranscriptEntry{Method: "init"}, r.inner.Init(ctx))
+}
+
+func (r *RecordingRunner) ZephyrExport(ctx context.Context) tea.Cmd {
+	return r.record(TranscriptEntry{Method: "zephyr-export"}, r.inner.ZephyrExport(ctx))
+}
+
+func (r *RecordingRunner) PackagesPipInstall(ctx context.Context) tea.Cmd {
+	return r.record(TranscriptEntry{Method: "packages-pip-install"}, r.inner.PackagesPipInstall(ctx))
+}
+
+func (r *RecordingRunner) SdkInstall(ctx context.Context) tea.Cmd {
+	return r.record(TranscriptEntry{Method: "sdk-install"}, r.inner.SdkInstall(ctx))
+}
+
+func (r *RecordingRunner) InstallBrewDeps(ctx context.Context) tea.Cmd {
+	return r.record(TranscriptEntry{Method: "install-brew-deps"}, r.inner.InstallBrewDeps(ctx))
+}
+
+// ReplayRunner serves recorded transcript entries instead of running
+// commands. Each entry is used once, in order of first match; a command with
+// no matching entry fails with exit code -1.
+type ReplayRunner struct {
+	entries []TranscriptEntry
+	used    []bool
+	wsRoot  string
+	mu      sync.Mutex
+}
+
+// NewReplayRunner replays t, substituting wsRoot for the workspace
+// placeholder in arguments and output.
+func NewReplayRunner(t Transcript, wsRoot string) *ReplayRunner {
+	return &ReplayRunner{
+		entries: t.Commands,
+		used:    make([]bool, len(t.Commands)),
+		wsRoot:  wsRoot,
+	}
+}
+
+// Unused returns the entries that no command has consumed yet.
+func (r *ReplayRunner) Unused() []TranscriptEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []TranscriptEntry
+	for i, e := range r.entries {
+		if !r.used[i] {
+			unused = append(unused, e)
+		}
+	}
+	return unused
+}
+
+func (r *ReplayRunner) replay(method, name string, args []string) tea.Cmd {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recorded := make([]string, len(args))
+	for i, a := range args {
+		recorded[i] = toPlaceholder(a, r.wsRoot)
+	}
+	for i, e := range r.entries {
+		if r.used[i] || e.Method != method || e.Name != name {
+			continue
+		}
+		if len(e.Args) != 0 || len(recorded) != 0 {
+			if !reflect.DeepEqual(e.Args, recorded) {
+				continue
+			}
+		}
+		r.used[i] = true
+		return r.serve(e)
+	}
+
+	desc := method
+	if method == "run" {
+		desc = strings.Join(append([]string{name}, recorded...), " ")
+	}
+	return func() tea.Msg {
+		return CommandResultMsg{
+			Output:   fmt.Sprintf("replay: no recorded command for %q\n", desc),
+			ExitCode: -1,
+		}
+	}
+}
+
+func (r *ReplayRunner) serve(e TranscriptEntry) tea.Cmd {
+	return func() tea.Msg {
+		s := newOutputStream()
+		var all strings.Builder
+		for _, line := range e.Output {
+			line = fromPlaceholder(line, r.wsRoot)
+			all.WriteString(line + "\n")
+			s.push(line)
+		}
+		s.finish(CommandResultMsg{
+			Output:   all.String(),
+			ExitCode: e.ExitCode,
+			Duration: time.Duration(e.DurationMS) * time.Millisecond,
+			Streamed: true,
+		})
+		return s.next()
+	}
+}
+
+func (r *ReplayRunner) Run(ctx context.Context, name string, args ...string) tea.Cmd {
+	return r.replay("run", name, args)
+}
+func (r *ReplayRunner) Status(ctx context.Context) tea.Cmd { return r.replay("status", "", nil) }
+func (r *ReplayRunner) List(ctx context.Context) tea.Cmd   { return r.replay("list", "", nil) }
+func (r *ReplayRunner) Diff(ctx context.Context) tea.Cmd   { return r.replay("diff", "", nil) }
+func (r *ReplayRunner) Update(ctx context.Context) tea.Cmd { return r.replay("update", "", nil) }
+func (r *ReplayRunner) Init(ctx context.Context) tea.Cmd   { return r.replay("init", "", nil) }
+func (r *ReplayRunner) ZephyrExport(ctx context.Context) tea.Cmd {
+	return r.replay("zephyr-export", "", nil)
+}
+func (r *ReplayRunner) PackagesPipInstall(ctx context.Context) tea.Cmd {
+	return r.replay("packages-pip-install", "", nil)
+}
+func (r *ReplayRunner) SdkInstall(ctx context.Context) tea.Cmd {
+	return r.replay("sdk-install", "", nil)
+}
+func (r *ReplayRunner) InstallBrewDeps(ctx context.Context) tea.Cmd {
+	return r.replay("install-brew-deps", "", nil)
+}
+
+func toPlaceholder(s, wsRoot string) string {
+	if wsRoot == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, wsRoot, workspacePlaceholder)
+}
+
+func fromPlaceholder(s, wsRoot string) string {
+	if wsRoot == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, workspacePlaceholder, wsRoot)
+}
+
+// splitOutput breaks combined output into lines without a trailing empty one.
+func splitOutput(output string) []string {
+	output = strings.TrimSuffix(output, "\n")
+	if output == "" {
+		return nil
+	}
+	return strings.Split(output, "\n")
+}
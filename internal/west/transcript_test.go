@@ -0,0 +1,86 @@
+package west
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordingRunnerRoundTripsThroughReplay(t *testing.T) {
+	recordRoot := t.TempDir()
+	path := filepath.Join(t.TempDir(), "session.json")
+	rec := NewRecordingRunner(DefaultRunner{}, path, recordRoot)
+
+	msg := Drain(rec.Run(context.Background(), "sh", "-c", "echo building "+recordRoot+"/app; echo oops >&2; exit 3", recordRoot), nil)
+	if result := msg.(CommandResultMsg); result.ExitCode != 3 {
+		t.Fatalf("expected exit 3 from recorded command, got %d", result.ExitCode)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("transcript not written: %v", err)
+	}
+	if strings.Contains(string(data), recordRoot) {
+		t.Fatalf("expected workspace root to be replaced in transcript, got %s", data)
+	}
+
+	tr, err := LoadTranscript(path)
+	if err != nil {
+		t.Fatalf("LoadTranscript() error: %v", err)
+	}
+	if len(tr.Commands) != 1 || tr.Commands[0].Method != "run" {
+		t.Fatalf("expected one run entry, got %+v", tr.Commands)
+	}
+
+	replayRoot := t.TempDir()
+	replay := NewReplayRunner(tr, replayRoot)
+	var lines []string
+	msg = Drain(replay.Run(context.Background(), "sh", "-c", "echo building "+replayRoot+"/app; echo oops >&2; exit 3", replayRoot), func(line string) {
+		lines = append(lines, line)
+	})
+	result := msg.(CommandResultMsg)
+	if result.ExitCode != 3 || !result.Streamed {
+		t.Fatalf("expected streamed exit 3 on replay, got %+v", result)
+	}
+	if len(lines) != 2 || lines[0] != "building "+replayRoot+"/app" || lines[1] != "oops" {
+		t.Fatalf("unexpected replayed lines %q", lines)
+	}
+	if len(replay.Unused()) != 0 {
+		t.Fatal("expected entry to be consumed")
+	}
+}
+
+func TestReplayRunnerUsesEntriesInOrderOnce(t *testing.T) {
+	replay := NewReplayRunner(Transcript{Commands: []TranscriptEntry{
+		{Method: "update", Output: []string{"first"}},
+		{Method: "update", Output: []string{"second"}, ExitCode: 1},
+	}}, "")
+
+	for i, want := range []string{"first\n", "second\n"} {
+		result := Drain(replay.Update(context.Background()), nil).(CommandResultMsg)
+		if result.Output != want {
+			t.Fatalf("update %d: expected %q, got %q", i, want, result.Output)
+		}
+	}
+
+	result := Drain(replay.Update(context.Background()), nil).(CommandResultMsg)
+	if result.ExitCode != -1 || !strings.Contains(result.Output, "no recorded command") {
+		t.Fatalf("expected exhausted transcript to fail, got %+v", result)
+	}
+}
+
+func TestReplayRunnerRejectsUnrecordedArgs(t *testing.T) {
+	replay := NewReplayRunner(Transcript{Commands: []TranscriptEntry{
+		{Method: "run", Name: "west", Args: []string{"build", "-b", "nrf52840dk"}},
+	}}, "")
+
+	result := Drain(replay.Run(context.Background(), "west", "build", "-b", "native_sim"), nil).(CommandResultMsg)
+	if result.ExitCode != -1 || !strings.Contains(result.Output, "west build -b native_sim") {
+		t.Fatalf("expected mismatch to fail with command description, got %+v", result)
+	}
+	if len(replay.Unused()) != 1 {
+		t.Fatal("expected mismatched entry to stay unused")
+	}
+}
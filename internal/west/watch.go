@@ -0,0 +1,74 @@
+package west
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// watchedExts are the files whose changes a rebuild picks up.
+var watchedExts = map[string]bool{
+	".c": true, ".h": true, ".cc": true, ".cpp": true, ".cxx": true, ".hpp": true,
+	".s": true, ".S": true, ".ld": true,
+	".conf": true, ".overlay": true, ".dts": true, ".dtsi": true, ".yaml": true,
+	".cmake": true,
+}
+
+// Snapshot maps the files a build depends on to their modification times.
+type Snapshot map[string]time.Time
+
+// SnapshotSources records the sources, Kconfig fragments, overlays and
+// CMake and Kconfig files under projectDir. buildDir, when it lies inside
+// the project, and other directories holding a configured build are
+// skipped; source dirs that merely start with "build" are not.
+func SnapshotSources(projectDir, buildDir string) Snapshot {
+	snap := Snapshot{}
+	buildDir = filepath.Clean(buildDir)
+	_ = filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != projectDir && (skipDirs[name] || strings.HasPrefix(name, ".") ||
+				path == buildDir || isBuildDir(path)) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !watchedExts[filepath.Ext(name)] && name != "CMakeLists.txt" && !strings.HasPrefix(name, "Kconfig") {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			snap[path] = info.ModTime()
+		}
+		return nil
+	})
+	return snap
+}
+
+// isBuildDir reports whether CMake has configured a build in dir.
+func isBuildDir(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "CMakeCache.txt"))
+	return err == nil
+}
+
+// Changed returns the files added, removed or modified since prev, sorted.
+func (s Snapshot) Changed(prev Snapshot) []string {
+	var changed []string
+	for path, mod := range s {
+		if old, ok := prev[path]; !ok || !old.Equal(mod) {
+			changed = append(changed, path)
+		}
+	}
+	for path := range prev {
+		if _, ok := s[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
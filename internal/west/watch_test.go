@@ -0,0 +1,65 @@
+package west
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSnapshotSourcesSkipsBuildDirs(t *testing.T) {
+	project := t.TempDir()
+	files := []string{
+		"CMakeLists.txt",
+		"prj.conf",
+		"src/main.c",
+		"boards/nrf52840dk_nrf52840.overlay",
+		"Kconfig",
+		"README.md",
+		"build/zephyr/zephyr.c",
+		"build-nrf/CMakeCache.txt",
+		"build-nrf/zephyr/zephyr.c",
+		"buildsys/gen.c",
+		"build_helpers/flags.cmake",
+		"out/CMakeCache.txt",
+		".git/HEAD",
+	}
+	for _, f := range files {
+		path := filepath.Join(project, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snap := SnapshotSources(project, filepath.Join(project, "out"))
+	var got []string
+	for path := range snap {
+		rel, _ := filepath.Rel(project, path)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	want := []string{"CMakeLists.txt", "Kconfig", "boards/nrf52840dk_nrf52840.overlay", "build_helpers/flags.cmake", "buildsys/gen.c", "prj.conf", "src/main.c"}
+	if len(got) != len(want) {
+		t.Fatalf("snapshot = %v, want %v", got, want)
+	}
+	for _, w := range want {
+		if _, ok := snap[filepath.Join(project, filepath.FromSlash(w))]; !ok {
+			t.Fatalf("snapshot missing %s: %v", w, got)
+		}
+	}
+}
+
+func TestSnapshotChanged(t *testing.T) {
+	t0 := time.Unix(100, 0)
+	prev := Snapshot{"a.c": t0, "b.c": t0, "gone.c": t0}
+	cur := Snapshot{"a.c": t0, "b.c": t0.Add(time.Second), "new.c": t0}
+	if got, want := cur.Changed(prev), []string{"b.c", "gone.c", "new.c"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Changed = %v, want %v", got, want)
+	}
+	if got := prev.Changed(prev); len(got) != 0 {
+		t.Fatalf("expected no changes, got %v", got)
+	}
+}